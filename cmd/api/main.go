@@ -6,7 +6,11 @@ import (
 
 	"github.com/GetterSethya/golangApiMarketplace/config"
 	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/jobs"
+	"github.com/GetterSethya/golangApiMarketplace/internal/media"
+	"github.com/GetterSethya/golangApiMarketplace/internal/notifier"
 	"github.com/GetterSethya/golangApiMarketplace/internal/server"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
 	"github.com/joho/godotenv"
 )
 
@@ -29,7 +33,37 @@ func main() {
 	db.SetConnMaxLifetime(5 * time.Minute)
 
 	store := datastore.NewStore(db)
-	api := server.NewServer(cfg.App.Port, store)
+
+	mediaStore, err := media.NewFileSystemStore(cfg.Media.Dir, cfg.Media.BaseUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// purge data soft delete yang sudah lewat grace period
+	purgeJob := jobs.NewPurgeJob(store, mediaStore, cfg.App.DeleteGracePeriod)
+	go jobs.Every("purge", jobs.PURGEINTERVAL, nil, purgeJob.Run)
+
+	// publish/arsip product sesuai jadwal
+	go jobs.Every("product schedule", jobs.PRODUCTSCHEDULEINTERVAL, nil, jobs.NewProductScheduleJob(store).Run)
+
+	// lepas stock dari transaksi yang reservation nya kadaluarsa
+	go jobs.Every("stock reservation", jobs.STOCKRESERVATIONINTERVAL, nil, jobs.NewStockReservationJob(store).Run)
+
+	// proses antrian import product dari file csv/jsonl
+	go jobs.Every("product import", jobs.PRODUCTIMPORTINTERVAL, nil, jobs.NewProductImportJob(store, usecases.ProcessProductImport).Run)
+
+	// pesan ke user (token reset password, kode verifikasi) lewat webhook, file, atau log kalau tidak diset
+	var n notifier.Notifier = notifier.NewLogNotifier()
+	switch {
+	case cfg.Notifier.WebhookUrl != "":
+		n = notifier.NewWebhookNotifier(cfg.Notifier.WebhookUrl)
+	case cfg.Notifier.File != "":
+		if n, err = notifier.NewFileNotifier(cfg.Notifier.File); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	api := server.NewServer(cfg.App.Port, store, mediaStore, n)
 
 	api.Run()
 }
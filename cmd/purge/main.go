@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/config"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/jobs"
+	"github.com/GetterSethya/golangApiMarketplace/internal/media"
+	"github.com/joho/godotenv"
+)
+
+// jalankan purge sekali (contoh: dari cron), go run cmd/purge/main.go
+func main() {
+
+	err := godotenv.Load(".env")
+	if err != nil {
+		log.Fatal("Error loading .env file", err)
+	}
+	cfg := config.LoadConfig()
+
+	db, err := datastore.NewPostgresStorage().Init()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer db.Close()
+
+	mediaStore, err := media.NewFileSystemStore(cfg.Media.Dir, cfg.Media.BaseUrl)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	job := jobs.NewPurgeJob(datastore.NewStore(db), mediaStore, cfg.App.DeleteGracePeriod)
+	if err := job.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -1,10 +1,17 @@
 package config
 
-import "os"
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
 	Postgres *PostgresCfg
 	App      *AppConfig
+	Media    *MediaConfig
+	Notifier *NotifierConfig
 }
 
 type PostgresCfg struct {
@@ -16,19 +23,40 @@ type PostgresCfg struct {
 	Dbname   string
 }
 
+type MediaConfig struct {
+	Dir     string
+	BaseUrl string
+}
+
+type NotifierConfig struct {
+	// kalau dua-duanya kosong pesan hanya ditulis ke log
+	WebhookUrl string
+	File       string
+}
+
 type AppConfig struct {
 	Port      string
 	JWTSecret string
+	AdminIds  []string
+	// lama data yang di soft delete masih bisa di restore sebelum di purge
+	DeleteGracePeriod time.Duration
+	// lama stock ditahan untuk transaksi yang belum diterima seller
+	StockReservationTTL time.Duration
+	// lama token reset password berlaku
+	PasswordResetTTL time.Duration
 }
 
 func LoadConfig() *Config {
 
 	pgCfg := loadPostgresConfig()
 	appCfg := loadAppConfig()
+	mediaCfg := loadMediaConfig()
 
 	return &Config{
 		Postgres: pgCfg,
 		App:      appCfg,
+		Media:    mediaCfg,
+		Notifier: &NotifierConfig{WebhookUrl: os.Getenv("NOTIFIER_WEBHOOK_URL"), File: os.Getenv("NOTIFIER_FILE")},
 	}
 }
 
@@ -46,8 +74,52 @@ func loadPostgresConfig() *PostgresCfg {
 
 func loadAppConfig() *AppConfig {
 
+	// daftar user id admin dipisah koma, contoh: ADMIN_IDS="uuid1,uuid2"
+	var adminIds []string
+	for _, id := range strings.Split(os.Getenv("ADMIN_IDS"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			adminIds = append(adminIds, id)
+		}
+	}
+
+	graceDays, err := strconv.Atoi(os.Getenv("DELETE_GRACE_DAYS"))
+	if err != nil || graceDays < 0 {
+		graceDays = 30
+	}
+
+	reservationHours, err := strconv.Atoi(os.Getenv("STOCK_RESERVATION_HOURS"))
+	if err != nil || reservationHours < 1 {
+		reservationHours = 24
+	}
+
+	resetMinutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_MINUTES"))
+	if err != nil || resetMinutes < 1 {
+		resetMinutes = 30
+	}
+
 	return &AppConfig{
-		Port:      os.Getenv("APP_PORT"),
-		JWTSecret: os.Getenv("JWTSECRET"),
+		Port:                os.Getenv("APP_PORT"),
+		JWTSecret:           os.Getenv("JWTSECRET"),
+		AdminIds:            adminIds,
+		DeleteGracePeriod:   time.Duration(graceDays) * 24 * time.Hour,
+		StockReservationTTL: time.Duration(reservationHours) * time.Hour,
+		PasswordResetTTL:    time.Duration(resetMinutes) * time.Minute,
+	}
+}
+
+func loadMediaConfig() *MediaConfig {
+	dir := os.Getenv("MEDIA_DIR")
+	if dir == "" {
+		dir = "./media"
+	}
+
+	baseUrl := os.Getenv("MEDIA_BASE_URL")
+	if baseUrl == "" {
+		baseUrl = "/v1/media"
+	}
+
+	return &MediaConfig{
+		Dir:     dir,
+		BaseUrl: baseUrl,
 	}
 }
@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,18 +13,28 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// dipasang saat server start, return false kalau session user sudah tidak berlaku
+// (contoh: user dihapus atau tokenVersion user sudah naik karena ganti password). nil = tidak dicek
+var SessionValidator func(userId string, tokenVersion int) bool
+
+type contextKey string
+
+// user id hasil validasi token disimpan di context request, jadi session cukup dicek sekali per request
+const userIdContextKey contextKey = "userId"
+
+// claim token login, ver = tokenVersion user saat token dibuat
+type sessionClaims struct {
+	TokenVersion int `json:"ver"`
+	jwt.RegisteredClaims
+}
+
 // middleware untuk mem-protect route, jika tidak ada Header "Authorization" atau JWT tidak valid, maka akan mereturn error status forbidden 403
 func JWTMiddleware(f helper.AppHandler) helper.AppHandler {
 
 	return func(w http.ResponseWriter, r *http.Request) types.AppError {
-		secret := config.LoadConfig().App.JWTSecret
-
-		// get Authorization header
-		jwtToken := getTokenFromRequest(r)
 
-		//
-		token, err := validateJWT(jwtToken, secret)
-		if err != nil || !token.Valid {
+		userId := userIdFromToken(r)
+		if userId == "" {
 
 			return types.AppError{
 				Error:  fmt.Errorf("Invalid token"),
@@ -32,7 +43,7 @@ func JWTMiddleware(f helper.AppHandler) helper.AppHandler {
 		}
 
 		// call appHandler func
-		if err := f(w, r); err.Error != nil {
+		if err := f(w, withUserId(r, userId)); err.Error != nil {
 
 			return err
 		}
@@ -45,6 +56,47 @@ func JWTMiddleware(f helper.AppHandler) helper.AppHandler {
 
 }
 
+// middleware untuk route khusus admin, selain validasi JWT user id harus terdaftar di ADMIN_IDS
+func AdminMiddleware(f helper.AppHandler) helper.AppHandler {
+
+	return JWTMiddleware(func(w http.ResponseWriter, r *http.Request) types.AppError {
+
+		if !IsAdmin(GetUserIdFromJWT(r)) {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Forbidden"),
+				Status: http.StatusForbidden,
+			}
+		}
+
+		return f(w, r)
+	})
+}
+
+// middleware untuk route publik dengan auth opsional, token tidak valid tidak ditolak, user id nya saja yang kosong
+func OptionalJWTMiddleware(f helper.AppHandler) helper.AppHandler {
+
+	return func(w http.ResponseWriter, r *http.Request) types.AppError {
+
+		return f(w, withUserId(r, userIdFromToken(r)))
+	}
+}
+
+func IsAdmin(userId string) bool {
+
+	if userId == "" {
+		return false
+	}
+
+	for _, id := range config.LoadConfig().App.AdminIds {
+		if id == userId {
+			return true
+		}
+	}
+
+	return false
+}
+
 // subject berisi userId
 //
 // issuer "shopifyx"
@@ -53,16 +105,21 @@ func JWTMiddleware(f helper.AppHandler) helper.AppHandler {
 // not before: The "nbf" (not before) claim identifies the time before which the JWT
 //
 // issued At: The "iat" (issued at) claim identifies the time at which the JWT was issued.  This claim can be used to determine the age of the JWT.MUST NOT be accepted for processing
-func CreateJWT(userId, secret string) (string, error) {
+//
+// ver berisi tokenVersion user, naik setiap password diganti jadi token lama tidak berlaku
+func CreateJWT(userId string, tokenVersion int, secret string) (string, error) {
 	exp := jwt.NewNumericDate(time.Now().Add(time.Hour * 12))
 	nbf := jwt.NewNumericDate(time.Now())
 	iat := jwt.NewNumericDate(time.Now())
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Subject:   userId,
-		Issuer:    "shopifyx",
-		ExpiresAt: exp,
-		NotBefore: nbf,
-		IssuedAt:  iat,
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, sessionClaims{
+		TokenVersion: tokenVersion,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userId,
+			Issuer:    "shopifyx",
+			ExpiresAt: exp,
+			NotBefore: nbf,
+			IssuedAt:  iat,
+		},
 	})
 
 	accessToken, err := token.SignedString([]byte(secret))
@@ -74,17 +131,44 @@ func CreateJWT(userId, secret string) (string, error) {
 	return accessToken, nil
 }
 
-// hanya panggil fungsi ini di route yang sudah ada dijaga oleh middleware JWTMiddleware
+// user id dari context kalau request sudah lewat JWTMiddleware/OptionalJWTMiddleware, selain itu token
+// divalidasi di sini. return "" kalau token tidak ada/tidak valid/session sudah tidak berlaku
 func GetUserIdFromJWT(r *http.Request) string {
+
+	if userId, ok := r.Context().Value(userIdContextKey).(string); ok {
+		return userId
+	}
+
+	return userIdFromToken(r)
+}
+
+func withUserId(r *http.Request, userId string) *http.Request {
+
+	return r.WithContext(context.WithValue(r.Context(), userIdContextKey, userId))
+}
+
+// validasi token dan session user, return "" kalau tidak valid
+func userIdFromToken(r *http.Request) string {
 	secret := config.LoadConfig().App.JWTSecret
-	token := getTokenFromRequest(r)
-	jwtToken, err := validateJWT(token, secret)
+	jwtToken, err := validateJWT(getTokenFromRequest(r), secret)
+	if err != nil || !jwtToken.Valid {
+		return ""
+	}
 
-	if err != nil {
+	claims, ok := jwtToken.Claims.(jwt.MapClaims)
+	if !ok {
+		return ""
+	}
+
+	// token lama tanpa ver dianggap versi 0
+	sub, _ := claims["sub"].(string)
+	ver, _ := claims["ver"].(float64)
+
+	if SessionValidator != nil && !SessionValidator(sub, int(ver)) {
 		return ""
 	}
 
-	return jwtToken.Claims.(jwt.MapClaims)["sub"].(string)
+	return sub
 }
 
 func validateJWT(token, secret string) (*jwt.Token, error) {
@@ -2,9 +2,12 @@ package auth
 
 import (
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
 	"github.com/golang-jwt/jwt/v4"
 )
 
@@ -12,7 +15,7 @@ func TestValidateJWT(t *testing.T) {
 	userId := "12345678"
 	secret := "superSecret"
 
-	jwtString, err := CreateJWT(userId, secret)
+	jwtString, err := CreateJWT(userId, 0, secret)
 	if err != nil {
 		log.Println(jwtString)
 		t.Errorf("Failed when creating jwt")
@@ -41,3 +44,199 @@ func TestValidateJWT(t *testing.T) {
 		t.Errorf("Failed to validate claims")
 	}
 }
+
+func TestJWTMiddlewareSession(t *testing.T) {
+	t.Setenv("JWTSECRET", "superSecret")
+
+	token, err := CreateJWT("12345678", 2, "superSecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var handlerUserId string
+	handler := JWTMiddleware(func(w http.ResponseWriter, r *http.Request) types.AppError {
+		handlerUserId = GetUserIdFromJWT(r)
+		GetUserIdFromJWT(r)
+
+		return types.AppError{Status: http.StatusOK}
+	})
+
+	request := func() types.AppError {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", token)
+
+		return handler(httptest.NewRecorder(), req)
+	}
+
+	defer func() { SessionValidator = nil }()
+
+	t.Run("Should accept token with current token version", func(t *testing.T) {
+		SessionValidator = func(userId string, tokenVersion int) bool {
+			return userId == "12345678" && tokenVersion == 2
+		}
+
+		if err := request(); err.Error != nil {
+			t.Errorf("Expected token to be accepted, but got=%v", err.Error)
+		}
+	})
+
+	t.Run("Should reject token with old token version", func(t *testing.T) {
+		SessionValidator = func(userId string, tokenVersion int) bool {
+			return tokenVersion == 3
+		}
+
+		if err := request(); err.Status != http.StatusForbidden {
+			t.Errorf("Expected status %d, but got=%d", http.StatusForbidden, err.Status)
+		}
+	})
+
+	t.Run("Should validate session once per request", func(t *testing.T) {
+		calls := 0
+		SessionValidator = func(userId string, tokenVersion int) bool {
+			calls++
+			return true
+		}
+
+		if err := request(); err.Error != nil {
+			t.Fatalf("Expected token to be accepted, but got=%v", err.Error)
+		}
+
+		if calls != 1 || handlerUserId != "12345678" {
+			t.Errorf("Expected one session check and user id 12345678, but got calls=%d userId=%s", calls, handlerUserId)
+		}
+	})
+}
+
+func TestGetUserIdFromJWTSession(t *testing.T) {
+	t.Setenv("JWTSECRET", "superSecret")
+
+	token, err := CreateJWT("12345678", 1, "superSecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// route publik dengan auth opsional
+	var handlerUserId string
+	handler := OptionalJWTMiddleware(func(w http.ResponseWriter, r *http.Request) types.AppError {
+		handlerUserId = GetUserIdFromJWT(r)
+		GetUserIdFromJWT(r)
+
+		return types.AppError{Status: http.StatusOK}
+	})
+
+	request := func(token string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", token)
+
+		handlerUserId = "-"
+		if err := handler(httptest.NewRecorder(), req); err.Error != nil {
+			t.Fatalf("Expected optional auth to never reject, but got=%v", err.Error)
+		}
+
+		return handlerUserId
+	}
+
+	defer func() { SessionValidator = nil }()
+
+	t.Run("Should return user id when token version still current", func(t *testing.T) {
+		calls := 0
+		SessionValidator = func(userId string, tokenVersion int) bool {
+			calls++
+			return tokenVersion == 1
+		}
+
+		if userId := request(token); userId != "12345678" || calls != 1 {
+			t.Errorf("Expected user id 12345678 with one session check, but got=%s calls=%d", userId, calls)
+		}
+	})
+
+	t.Run("Should return empty user id when password changed after token was created", func(t *testing.T) {
+		SessionValidator = func(userId string, tokenVersion int) bool {
+			return tokenVersion == 2
+		}
+
+		if userId := request(token); userId != "" {
+			t.Errorf("Expected empty user id for stale token, but got=%s", userId)
+		}
+	})
+
+	t.Run("Should return empty user id without token", func(t *testing.T) {
+		SessionValidator = nil
+
+		if userId := request(""); userId != "" {
+			t.Errorf("Expected empty user id, but got=%s", userId)
+		}
+	})
+}
+
+func TestTOTP(t *testing.T) {
+	// test vector RFC 6238 appendix B (SHA1), secret ascii "12345678901234567890"
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+
+	t.Run("Should match RFC 6238 test vectors", func(t *testing.T) {
+		cases := map[int64]string{
+			59:          "94287082",
+			1111111109:  "07081804",
+			1111111111:  "14050471",
+			1234567890:  "89005924",
+			2000000000:  "69279037",
+			20000000000: "65353130",
+		}
+
+		key, err := decodeTOTPSecret(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for unix, expected := range cases {
+			if code := hotp(key, totpStep(time.Unix(unix, 0)), 8); code != expected {
+				t.Errorf("Time %d expected %s, but got=%s", unix, expected, code)
+			}
+		}
+	})
+
+	t.Run("Should accept code within skew and reject outside", func(t *testing.T) {
+		now := time.Unix(1234567890, 0)
+
+		code, err := TOTPCode(secret, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if code != "005924" {
+			t.Errorf("Expected 6 digit code 005924, but got=%s", code)
+		}
+
+		if step, ok := ValidateTOTP(secret, code, now.Add(TOTPPERIOD*time.Second)); !ok || step != totpStep(now) {
+			t.Errorf("Expected code to be valid one step later, got step=%d ok=%v", step, ok)
+		}
+
+		if _, ok := ValidateTOTP(secret, code, now.Add(3*TOTPPERIOD*time.Second)); ok {
+			t.Errorf("Expected code to be rejected three steps later")
+		}
+	})
+
+	t.Run("Should not accept challenge token as login token", func(t *testing.T) {
+		challenge, err := CreateChallengeJWT("12345678", "superSecret")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if userId := ValidateChallengeJWT(challenge, "superSecret"); userId != "12345678" {
+			t.Errorf("Expected challenge for 12345678, but got=%s", userId)
+		}
+
+		if _, err := validateJWT(challenge, "superSecret"); err == nil {
+			t.Errorf("Expected challenge token to be rejected with login secret")
+		}
+
+		login, err := CreateJWT("12345678", 0, "superSecret")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if userId := ValidateChallengeJWT(login, "superSecret"); userId != "" {
+			t.Errorf("Expected login token to be rejected as challenge, but got=%s", userId)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+const categoryColumns = `
+        id,
+        parentId,
+        name,
+        slug,
+        position,
+        createdAt,
+        updatedAt,
+        deletedAt`
+
+func (s *Storage) CreateCategory(id string, c *entities.Category) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO categories (
+            id,
+            parentId,
+            name,
+            slug,
+            position
+        ) VALUES ($1,$2,$3,$4,$5)`,
+		id,
+		sql.NullString{String: c.ParentId, Valid: c.ParentId != ""},
+		c.Name,
+		c.Slug,
+		c.Position,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) GetCategoryById(id string) (*entities.Category, error) {
+
+	row := s.db.QueryRow(`SELECT `+categoryColumns+` FROM categories WHERE id = $1`, id)
+
+	return handleCategoryRow(row)
+}
+
+func (s *Storage) GetCategoryBySlug(slug string) (*entities.Category, error) {
+
+	row := s.db.QueryRow(`SELECT `+categoryColumns+` FROM categories WHERE slug = $1`, slug)
+
+	return handleCategoryRow(row)
+}
+
+// semua category dalam bentuk flat, urut berdasarkan position lalu name
+func (s *Storage) ListCategories() (*[]entities.Category, error) {
+
+	returnCategories := []entities.Category{}
+	rows, err := s.db.Query(`SELECT ` + categoryColumns + ` FROM categories ORDER BY position ASC, name ASC`)
+	if err != nil {
+		log.Println("err inside ListCategories", err)
+		return &returnCategories, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return &[]entities.Category{}, err
+		}
+
+		returnCategories = append(returnCategories, *category)
+	}
+
+	return &returnCategories, nil
+}
+
+func (s *Storage) UpdateCategory(id string, c *entities.Category) error {
+
+	_, err := s.db.Exec(`
+        UPDATE categories 
+        SET parentId = $1,
+            name = $2,
+            slug = $3,
+            position = $4,
+            updatedAt = NOW()
+        WHERE id = $5`,
+		sql.NullString{String: c.ParentId, Valid: c.ParentId != ""},
+		c.Name,
+		c.Slug,
+		c.Position,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteCategory(id string) error {
+
+	_, err := s.db.Exec(`DELETE FROM categories WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// breadcrumb dari root sampai category id (termasuk category itu sendiri)
+func (s *Storage) GetCategoryPath(id string) (*[]entities.CategoryMinimal, error) {
+
+	returnPath := []entities.CategoryMinimal{}
+	rows, err := s.db.Query(`
+        WITH RECURSIVE path AS (
+            SELECT id, parentId, name, slug, 0 AS depth FROM categories WHERE id = $1
+            UNION ALL
+            SELECT categories.id, categories.parentId, categories.name, categories.slug, path.depth + 1
+            FROM categories 
+            JOIN path ON categories.id = path.parentId
+        )
+        SELECT id, name, slug FROM path ORDER BY depth DESC`, id)
+	if err != nil {
+		log.Println("err inside GetCategoryPath", err)
+		return &returnPath, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var category entities.CategoryMinimal
+		if err := rows.Scan(&category.ID, &category.Name, &category.Slug); err != nil {
+			return &[]entities.CategoryMinimal{}, err
+		}
+
+		returnPath = append(returnPath, category)
+	}
+
+	return &returnPath, nil
+}
+
+// jumlah sub category dan product yang langsung menempel ke category
+func (s *Storage) CountCategoryUsage(id string) (int, int, error) {
+
+	var children, products int
+	err := s.db.QueryRow(`
+        SELECT 
+            (SELECT COUNT(*) FROM categories WHERE parentId = $1),
+            (SELECT COUNT(*) FROM products WHERE categoryId = $1)`, id).Scan(&children, &products)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return children, products, nil
+}
+
+func handleCategoryRow(row *sql.Row) (*entities.Category, error) {
+
+	category, err := scanCategory(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.Category{}, fmt.Errorf("Category did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.Category{}, fmt.Errorf("Something went wrong")
+	default:
+		return category, nil
+	}
+}
+
+func scanCategory(row rowScanner) (*entities.Category, error) {
+
+	var category entities.Category
+	var parentId sql.NullString
+
+	err := row.Scan(
+		&category.ID,
+		&parentId,
+		&category.Name,
+		&category.Slug,
+		&category.Position,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+		&category.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	category.ParentId = parentId.String
+
+	return &category, nil
+}
@@ -0,0 +1,175 @@
+package datastore
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/google/uuid"
+)
+
+// salah kode lebih dari ini, kode harus dikirim ulang
+const MAXCONTACTVERIFYATTEMPTS = 5
+
+var ErrContactVerificationInvalid = fmt.Errorf("Invalid or expired verification code")
+
+// email/phone yang berubah status verifikasinya direset, kode yang belum dipakai untuk kontak lama dihapus
+func (s *Storage) UpdateUserContact(id, email, phone string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        UPDATE users
+        SET email = $1,
+            emailVerifiedAt = CASE WHEN email = $1 THEN emailVerifiedAt ELSE NULL END,
+            phone = $2,
+            phoneVerifiedAt = CASE WHEN phone = $2 THEN phoneVerifiedAt ELSE NULL END,
+            updatedAt = NOW()
+        WHERE id = $3 AND deletedAt IS NULL`, email, phone, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+        DELETE FROM contactVerifications
+        WHERE userId = $1 AND verifiedAt IS NULL
+            AND ((channel = 'email' AND target <> $2) OR (channel = 'phone' AND target <> $3))`, id, email, phone)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// kode lama yang belum dipakai untuk channel yang sama tidak berlaku lagi
+func (s *Storage) CreateContactVerification(v *entities.ContactVerification) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM contactVerifications WHERE userId = $1 AND channel = $2 AND verifiedAt IS NULL`, v.UserId, v.Channel); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO contactVerifications (id, userId, channel, target, codeHash, expiresAt)
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.NewString(),
+		v.UserId,
+		v.Channel,
+		v.Target,
+		v.CodeHash,
+		v.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// return nil kalau belum pernah ada kode untuk channel tersebut
+func (s *Storage) GetLatestContactVerification(userId, channel string) (*entities.ContactVerification, error) {
+
+	var v entities.ContactVerification
+	err := s.db.QueryRow(`
+        SELECT id, userId, channel, target, codeHash, attempts, expiresAt, verifiedAt, createdAt
+        FROM contactVerifications
+        WHERE userId = $1 AND channel = $2
+        ORDER BY createdAt DESC
+        LIMIT 1`, userId, channel).Scan(
+		&v.ID,
+		&v.UserId,
+		&v.Channel,
+		&v.Target,
+		&v.CodeHash,
+		&v.Attempts,
+		&v.ExpiresAt,
+		&v.VerifiedAt,
+		&v.CreatedAt,
+	)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	default:
+		return &v, nil
+	}
+}
+
+// cocokkan kode terakhir untuk channel, kontak user hanya ditandai verified kalau
+// masih sama dengan kontak saat kode dibuat. kode salah menambah attempts
+func (s *Storage) VerifyContact(userId, channel, codeHash string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var id, target, storedHash string
+	var attempts int
+	err = tx.QueryRow(`
+        SELECT id, target, codeHash, attempts
+        FROM contactVerifications
+        WHERE userId = $1 AND channel = $2 AND verifiedAt IS NULL AND expiresAt > NOW()
+        ORDER BY createdAt DESC
+        LIMIT 1
+        FOR UPDATE`, userId, channel).Scan(&id, &target, &storedHash, &attempts)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return ErrContactVerificationInvalid
+	case err != nil:
+		return err
+	}
+
+	if attempts >= MAXCONTACTVERIFYATTEMPTS {
+		return ErrContactVerificationInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(storedHash), []byte(codeHash)) != 1 {
+		if _, err := tx.Exec(`UPDATE contactVerifications SET attempts = attempts + 1 WHERE id = $1`, id); err != nil {
+			return err
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+
+		return ErrContactVerificationInvalid
+	}
+
+	if _, err := tx.Exec(`UPDATE contactVerifications SET verifiedAt = NOW() WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	query := `UPDATE users SET emailVerifiedAt = NOW(), updatedAt = NOW() WHERE id = $1 AND email = $2`
+	if channel == "phone" {
+		query = `UPDATE users SET phoneVerifiedAt = NOW(), updatedAt = NOW() WHERE id = $1 AND phone = $2`
+	}
+
+	res, err := tx.Exec(query, userId, target)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected < 1 {
+		return ErrContactVerificationInvalid
+	}
+
+	return tx.Commit()
+}
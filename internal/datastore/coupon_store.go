@@ -0,0 +1,275 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/google/uuid"
+)
+
+// error dari ClaimCoupon, dicek oleh pemanggil untuk menentukan response
+var (
+	ErrCouponUnavailable = fmt.Errorf("Coupon is expired or no longer available")
+	ErrCouponUsageLimit  = fmt.Errorf("Coupon usage limit reached")
+	ErrCouponUserLimit   = fmt.Errorf("You have reached the usage limit for this coupon")
+)
+
+const couponColumns = `
+        id,
+        code,
+        sellerId,
+        type,
+        value,
+        maxDiscount,
+        minSpend,
+        productIds,
+        categoryIds,
+        usageLimit,
+        perUserLimit,
+        usedCount,
+        startsAt,
+        expiresAt,
+        createdAt,
+        updatedAt,
+        deletedAt`
+
+func (s *Storage) CreateCoupon(id string, c *entities.Coupon) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO coupons (
+            id,
+            code,
+            sellerId,
+            type,
+            value,
+            maxDiscount,
+            minSpend,
+            productIds,
+            categoryIds,
+            usageLimit,
+            perUserLimit,
+            startsAt,
+            expiresAt
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)`,
+		id,
+		c.Code,
+		sql.NullString{String: c.SellerId, Valid: c.SellerId != ""},
+		c.Type,
+		c.Value,
+		c.MaxDiscount,
+		c.MinSpend,
+		c.ProductIds,
+		c.CategoryIds,
+		c.UsageLimit,
+		c.PerUserLimit,
+		c.StartsAt,
+		c.ExpiresAt,
+	)
+
+	return err
+}
+
+func (s *Storage) GetCoupon(id string) (*entities.Coupon, error) {
+
+	row := s.db.QueryRow(`SELECT `+couponColumns+` FROM coupons WHERE id = $1 AND deletedAt IS NULL`, id)
+
+	return getCoupon(row)
+}
+
+// code disimpan uppercase, pemanggil yang menormalkan code
+func (s *Storage) GetCouponByCode(code string) (*entities.Coupon, error) {
+
+	row := s.db.QueryRow(`SELECT `+couponColumns+` FROM coupons WHERE code = $1 AND deletedAt IS NULL`, code)
+
+	return getCoupon(row)
+}
+
+// coupon milik seller, sellerId kosong = coupon platform
+func (s *Storage) ListCoupons(sellerId string) (*[]entities.Coupon, error) {
+
+	returnCoupons := []entities.Coupon{}
+	rows, err := s.db.Query(`
+        SELECT `+couponColumns+`
+        FROM coupons
+        WHERE sellerId IS NOT DISTINCT FROM $1 AND deletedAt IS NULL
+        ORDER BY createdAt DESC`, sql.NullString{String: sellerId, Valid: sellerId != ""})
+	if err != nil {
+		log.Println("err inside ListCoupons", err)
+		return &returnCoupons, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		coupon, err := scanCoupon(rows)
+		if err != nil {
+			return &[]entities.Coupon{}, err
+		}
+
+		returnCoupons = append(returnCoupons, *coupon)
+	}
+
+	return &returnCoupons, nil
+}
+
+// nonaktifkan coupon, code nya bisa dipakai lagi untuk coupon baru
+func (s *Storage) DeleteCoupon(id string) error {
+
+	_, err := s.db.Exec(`UPDATE coupons SET deletedAt = NOW(), updatedAt = NOW() WHERE id = $1`, id)
+
+	return err
+}
+
+// pakai coupon untuk satu transaksi. row coupon di lock supaya batas pemakaian global
+// dan per user tetap benar saat checkout bersamaan
+func (s *Storage) ClaimCoupon(couponId, userId, transactionId string, amount float64) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var usageLimit, perUserLimit, usedCount int
+	err = tx.QueryRow(`
+        SELECT usageLimit, perUserLimit, usedCount
+        FROM coupons
+        WHERE id = $1 AND deletedAt IS NULL AND startsAt <= NOW() AND expiresAt > NOW()
+        FOR UPDATE`, couponId).Scan(&usageLimit, &perUserLimit, &usedCount)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return ErrCouponUnavailable
+	case err != nil:
+		return err
+	}
+
+	// pemakaian user hanya dihitung kalau coupon punya batas per user
+	var used int
+	if perUserLimit > 0 {
+		if err := tx.QueryRow(`
+            SELECT COUNT(*) FROM couponRedemptions
+            WHERE couponId = $1 AND userId = $2`, couponId, userId).Scan(&used); err != nil {
+			return err
+		}
+	}
+
+	if err := checkCouponClaim(usageLimit, usedCount, perUserLimit, used); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        INSERT INTO couponRedemptions (id, couponId, userId, transactionId, amount)
+        VALUES ($1, $2, $3, $4, $5)`, uuid.NewString(), couponId, userId, transactionId, amount); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        UPDATE coupons
+        SET usedCount = usedCount + 1,
+            updatedAt = NOW()
+        WHERE id = $1`, couponId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// limit 0 = tidak dibatasi. used = jumlah coupon yang sudah dipakai user sebelumnya
+func checkCouponClaim(usageLimit, usedCount, perUserLimit, used int) error {
+
+	if usageLimit > 0 && usedCount >= usageLimit {
+		return ErrCouponUsageLimit
+	}
+
+	if perUserLimit > 0 && used >= perUserLimit {
+		return ErrCouponUserLimit
+	}
+
+	return nil
+}
+
+// kembalikan pemakaian coupon kalau transaksi gagal dibuat
+func (s *Storage) ReleaseCouponClaim(transactionId string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var couponId string
+	err = tx.QueryRow(`
+        DELETE FROM couponRedemptions
+        WHERE transactionId = $1
+        RETURNING couponId`, transactionId).Scan(&couponId)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        UPDATE coupons
+        SET usedCount = GREATEST(usedCount - 1, 0),
+            updatedAt = NOW()
+        WHERE id = $1`, couponId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func getCoupon(row *sql.Row) (*entities.Coupon, error) {
+
+	coupon, err := scanCoupon(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.Coupon{}, fmt.Errorf("Coupon did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.Coupon{}, fmt.Errorf("Something went wrong")
+	default:
+		return coupon, nil
+	}
+}
+
+func scanCoupon(row rowScanner) (*entities.Coupon, error) {
+
+	var coupon entities.Coupon
+	var sellerId sql.NullString
+
+	err := row.Scan(
+		&coupon.ID,
+		&coupon.Code,
+		&sellerId,
+		&coupon.Type,
+		&coupon.Value,
+		&coupon.MaxDiscount,
+		&coupon.MinSpend,
+		&coupon.ProductIds,
+		&coupon.CategoryIds,
+		&coupon.UsageLimit,
+		&coupon.PerUserLimit,
+		&coupon.UsedCount,
+		&coupon.StartsAt,
+		&coupon.ExpiresAt,
+		&coupon.CreatedAt,
+		&coupon.UpdatedAt,
+		&coupon.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon.SellerId = sellerId.String
+
+	return &coupon, nil
+}
@@ -0,0 +1,30 @@
+package datastore
+
+import "testing"
+
+func TestCheckCouponClaim(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		usageLimit   int
+		usedCount    int
+		perUserLimit int
+		used         int
+		expectedErr  error
+	}{
+		{"Should allow claim without limits", 0, 100, 0, 10, nil},
+		{"Should allow last coupon of usage limit", 5, 4, 0, 0, nil},
+		{"Should reject claim when usage limit reached", 5, 5, 0, 0, ErrCouponUsageLimit},
+		{"Should allow claim below per user limit", 0, 3, 2, 1, nil},
+		{"Should reject claim when per user limit reached", 0, 3, 1, 1, ErrCouponUserLimit},
+		{"Should check usage limit before per user limit", 5, 5, 1, 1, ErrCouponUsageLimit},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := checkCouponClaim(c.usageLimit, c.usedCount, c.perUserLimit, c.used); err != c.expectedErr {
+				t.Errorf("Expected error %v, but got=%v", c.expectedErr, err)
+			}
+		})
+	}
+}
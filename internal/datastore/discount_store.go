@@ -0,0 +1,325 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// error dari ClaimDiscount, dicek oleh pemanggil untuk menentukan response
+var (
+	ErrDiscountUnavailable = fmt.Errorf("Discount is no longer available")
+	ErrDiscountSoldOut     = fmt.Errorf("Not enough discounted stock left")
+	ErrDiscountBuyerLimit  = fmt.Errorf("Purchase limit for this sale reached")
+)
+
+const discountColumns = `
+        id,
+        productId,
+        sellerId,
+        name,
+        type,
+        value,
+        startsAt,
+        endsAt,
+        quantityLimit,
+        quantitySold,
+        perBuyerLimit,
+        isFlashSale,
+        createdAt,
+        updatedAt,
+        deletedAt`
+
+// diskon aktif = sudah mulai, belum berakhir, belum dibatalkan dan kuota belum habis
+const activeDiscountCondition = `
+        deletedAt IS NULL
+        AND startsAt <= NOW() AND endsAt > NOW()
+        AND (quantityLimit = 0 OR quantitySold < quantityLimit)`
+
+func (s *Storage) CreateDiscount(id string, d *entities.Discount) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO discounts (
+            id,
+            productId,
+            sellerId,
+            name,
+            type,
+            value,
+            startsAt,
+            endsAt,
+            quantityLimit,
+            perBuyerLimit,
+            isFlashSale
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+		id,
+		d.ProductId,
+		d.SellerId,
+		d.Name,
+		d.Type,
+		d.Value,
+		d.StartsAt,
+		d.EndsAt,
+		d.QuantityLimit,
+		d.PerBuyerLimit,
+		d.IsFlashSale,
+	)
+
+	return err
+}
+
+func (s *Storage) GetDiscount(id string) (*entities.Discount, error) {
+
+	row := s.db.QueryRow(`SELECT `+discountColumns+` FROM discounts WHERE id = $1 AND deletedAt IS NULL`, id)
+	discount, err := scanDiscount(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.Discount{}, fmt.Errorf("Discount did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.Discount{}, fmt.Errorf("Something went wrong")
+	default:
+		return discount, nil
+	}
+}
+
+// semua diskon product (termasuk yang sudah berakhir), yang terbaru dulu
+func (s *Storage) ListProductDiscounts(productId string) (*[]entities.Discount, error) {
+
+	returnDiscounts := []entities.Discount{}
+	rows, err := s.db.Query(`
+        SELECT `+discountColumns+`
+        FROM discounts
+        WHERE productId = $1 AND deletedAt IS NULL
+        ORDER BY startsAt DESC`, productId)
+	if err != nil {
+		log.Println("err inside ListProductDiscounts", err)
+		return &returnDiscounts, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		discount, err := scanDiscount(rows)
+		if err != nil {
+			return &[]entities.Discount{}, err
+		}
+
+		returnDiscounts = append(returnDiscounts, *discount)
+	}
+
+	return &returnDiscounts, nil
+}
+
+// batalkan diskon, redemption yang sudah ada tetap tercatat
+func (s *Storage) DeleteDiscount(id string) error {
+
+	_, err := s.db.Exec(`UPDATE discounts SET deletedAt = NOW(), updatedAt = NOW() WHERE id = $1`, id)
+
+	return err
+}
+
+// jumlah diskon product yang periodenya bersinggungan dengan periode baru
+func (s *Storage) CountOverlappingDiscounts(productId string, startsAt, endsAt time.Time) (int, error) {
+
+	var count int
+	err := s.db.QueryRow(`
+        SELECT COUNT(*) FROM discounts
+        WHERE productId = $1 AND deletedAt IS NULL
+            AND startsAt < $3 AND endsAt > $2`, productId, startsAt, endsAt).Scan(&count)
+
+	return count, err
+}
+
+// return nil tanpa error kalau product tidak punya diskon aktif
+func (s *Storage) GetActiveDiscount(productId string) (*entities.Discount, error) {
+
+	row := s.db.QueryRow(`
+        SELECT `+discountColumns+`
+        FROM discounts
+        WHERE productId = $1 AND `+activeDiscountCondition+`
+        ORDER BY startsAt DESC
+        LIMIT 1`, productId)
+	discount, err := scanDiscount(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		log.Println(err)
+		return nil, fmt.Errorf("Something went wrong")
+	default:
+		return discount, nil
+	}
+}
+
+// diskon aktif untuk banyak product sekaligus, key = productId
+func (s *Storage) ListActiveDiscounts(productIds []string) (map[string]entities.Discount, error) {
+
+	discounts := map[string]entities.Discount{}
+	if len(productIds) == 0 {
+		return discounts, nil
+	}
+
+	rows, err := s.db.Query(`
+        SELECT DISTINCT ON (productId) `+discountColumns+`
+        FROM discounts
+        WHERE productId = ANY($1::uuid[]) AND `+activeDiscountCondition+`
+        ORDER BY productId, startsAt DESC`, pq.Array(productIds))
+	if err != nil {
+		log.Println("err inside ListActiveDiscounts", err)
+		return discounts, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		discount, err := scanDiscount(rows)
+		if err != nil {
+			return map[string]entities.Discount{}, err
+		}
+
+		discounts[discount.ProductId] = *discount
+	}
+
+	return discounts, nil
+}
+
+// ambil kuota diskon untuk satu transaksi. row diskon di lock supaya kuota dan batas per buyer
+// tetap benar saat banyak checkout bersamaan (flash sale)
+func (s *Storage) ClaimDiscount(discountId, buyerId, transactionId string, quantity int) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var quantityLimit, quantitySold, perBuyerLimit int
+	err = tx.QueryRow(`
+        SELECT quantityLimit, quantitySold, perBuyerLimit
+        FROM discounts
+        WHERE id = $1 AND deletedAt IS NULL AND startsAt <= NOW() AND endsAt > NOW()
+        FOR UPDATE`, discountId).Scan(&quantityLimit, &quantitySold, &perBuyerLimit)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return ErrDiscountUnavailable
+	case err != nil:
+		return err
+	}
+
+	// jumlah yang sudah dibeli buyer hanya dihitung kalau diskon punya batas per buyer
+	var bought int
+	if perBuyerLimit > 0 {
+		if err := tx.QueryRow(`
+            SELECT COALESCE(SUM(quantity), 0) FROM discountRedemptions
+            WHERE discountId = $1 AND buyerId = $2`, discountId, buyerId).Scan(&bought); err != nil {
+			return err
+		}
+	}
+
+	if err := checkDiscountClaim(quantityLimit, quantitySold, perBuyerLimit, bought, quantity); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        INSERT INTO discountRedemptions (id, discountId, buyerId, transactionId, quantity)
+        VALUES ($1, $2, $3, $4, $5)`, uuid.NewString(), discountId, buyerId, transactionId, quantity); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        UPDATE discounts
+        SET quantitySold = quantitySold + $1,
+            updatedAt = NOW()
+        WHERE id = $2`, quantity, discountId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// limit 0 = tidak dibatasi. bought = unit diskon yang sudah di-claim buyer sebelumnya
+func checkDiscountClaim(quantityLimit, quantitySold, perBuyerLimit, bought, quantity int) error {
+
+	if quantityLimit > 0 && quantitySold+quantity > quantityLimit {
+		return ErrDiscountSoldOut
+	}
+
+	if perBuyerLimit > 0 && bought+quantity > perBuyerLimit {
+		return ErrDiscountBuyerLimit
+	}
+
+	return nil
+}
+
+// kembalikan kuota diskon kalau transaksi gagal dibuat
+func (s *Storage) ReleaseDiscountClaim(transactionId string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var discountId string
+	var quantity int
+	err = tx.QueryRow(`
+        DELETE FROM discountRedemptions
+        WHERE transactionId = $1
+        RETURNING discountId, quantity`, transactionId).Scan(&discountId, &quantity)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        UPDATE discounts
+        SET quantitySold = GREATEST(quantitySold - $1, 0),
+            updatedAt = NOW()
+        WHERE id = $2`, quantity, discountId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func scanDiscount(row rowScanner) (*entities.Discount, error) {
+
+	var discount entities.Discount
+
+	err := row.Scan(
+		&discount.ID,
+		&discount.ProductId,
+		&discount.SellerId,
+		&discount.Name,
+		&discount.Type,
+		&discount.Value,
+		&discount.StartsAt,
+		&discount.EndsAt,
+		&discount.QuantityLimit,
+		&discount.QuantitySold,
+		&discount.PerBuyerLimit,
+		&discount.IsFlashSale,
+		&discount.CreatedAt,
+		&discount.UpdatedAt,
+		&discount.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &discount, nil
+}
@@ -0,0 +1,33 @@
+package datastore
+
+import "testing"
+
+func TestCheckDiscountClaim(t *testing.T) {
+
+	cases := []struct {
+		name          string
+		quantityLimit int
+		quantitySold  int
+		perBuyerLimit int
+		bought        int
+		quantity      int
+		expectedErr   error
+	}{
+		{"Should allow claim without limits", 0, 100, 0, 50, 10, nil},
+		{"Should allow claim up to quantity limit", 5, 3, 0, 0, 2, nil},
+		{"Should reject claim above quantity limit", 5, 4, 0, 0, 2, ErrDiscountSoldOut},
+		{"Should allow claim up to buyer cap", 0, 0, 3, 1, 2, nil},
+		{"Should reject claim above buyer cap in one order", 0, 0, 2, 0, 3, ErrDiscountBuyerLimit},
+		{"Should reject claim when earlier orders used buyer cap", 0, 0, 2, 1, 2, ErrDiscountBuyerLimit},
+		{"Should check sold out before buyer cap", 5, 5, 2, 2, 1, ErrDiscountSoldOut},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := checkDiscountClaim(c.quantityLimit, c.quantitySold, c.perBuyerLimit, c.bought, c.quantity)
+			if err != c.expectedErr {
+				t.Errorf("Expected error %v, but got=%v", c.expectedErr, err)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package datastore
 
 import (
+	"database/sql"
+	"time"
+
 	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
 	"github.com/GetterSethya/golangApiMarketplace/internal/types"
 )
@@ -33,11 +36,6 @@ func (m *MockStore) UpdateBankAccount(id string, p *entities.BankAccount) error
 	return nil
 }
 
-func (m *MockStore) UpdateStockProduct(id string, stock int) error {
-
-	return nil
-}
-
 func (m *MockStore) DeleteProduct(id string) error {
 
 	return nil
@@ -48,9 +46,9 @@ func (m *MockStore) GetProductSeller(id string) (string, error) {
 	return "75ea96d2-8077-48aa-aad6-a02fbd282f3c", nil
 }
 
-func (m *MockStore) ListProducts(q types.ListQueryValid, userId string) (*[]entities.Product, error) {
+func (m *MockStore) ListProducts(q types.ListQueryValid, userId string) (*[]entities.Product, *types.PageInfo, error) {
 
-	return &[]entities.Product{}, nil
+	return &[]entities.Product{}, &types.PageInfo{}, nil
 }
 
 func (m *MockStore) CreateUser(id string, u *entities.User) error {
@@ -70,7 +68,11 @@ func (m *MockStore) UpdateUser(id, name, username string) error {
 
 func (m *MockStore) GetUserById(id string) (*entities.User, error) {
 
-	return &entities.User{}, nil
+	return &entities.User{
+		ID:              id,
+		EmailVerifiedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		TotpEnabledAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	}, nil
 }
 
 func (m *MockStore) GetUserByUsername(username string) (*entities.User, error) {
@@ -85,7 +87,8 @@ func (m *MockStore) CreateProduct(id, sellerId string, p *entities.Product) erro
 
 func (m *MockStore) GetProductById(id string) (*entities.Product, error) {
 
-	return &entities.Product{}, nil
+	// status default product di db
+	return &entities.Product{Status: "published"}, nil
 }
 
 func (m *MockStore) SearchProduct(q string) (*entities.Product, error) {
@@ -97,3 +100,633 @@ func (m *MockStore) UpdateProduct(id string, p *entities.Product) error {
 
 	return nil
 }
+
+func (m *MockStore) CreateTransaction(id, buyerId, sellerId, productId string, total float64, t *entities.Transaction) error {
+
+	return nil
+}
+
+func (m *MockStore) GetTransaction(id string) (*TransactionReturn, error) {
+
+	return &TransactionReturn{}, nil
+}
+
+func (m *MockStore) ListTransaction(q types.ListQueryTransactionValid, userId string) (*[]TransactionReturn, *types.PageInfo, error) {
+
+	return &[]TransactionReturn{}, &types.PageInfo{}, nil
+}
+
+func (m *MockStore) UpdateStatusTransaction(id, from, status string) error {
+
+	return nil
+}
+
+func (m *MockStore) CreateProductImages(productId string, imgs []entities.ProductImage, maxImages int) error {
+
+	return nil
+}
+
+func (m *MockStore) GetProductImage(id string) (*entities.ProductImage, error) {
+
+	return &entities.ProductImage{}, nil
+}
+
+func (m *MockStore) ListProductImages(productId string) (*[]entities.ProductImage, error) {
+
+	return &[]entities.ProductImage{}, nil
+}
+
+func (m *MockStore) DeleteProductImage(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) ReorderProductImages(productId string, imageIds []string) error {
+
+	return nil
+}
+
+func (m *MockStore) UpdateProductImageUrl(productId, imageUrl string) error {
+
+	return nil
+}
+
+func (m *MockStore) CreateProductVariant(id, productId string, v *entities.ProductVariant) error {
+
+	return nil
+}
+
+func (m *MockStore) GetProductVariant(id string) (*entities.ProductVariant, error) {
+
+	return &entities.ProductVariant{}, nil
+}
+
+func (m *MockStore) ListProductVariants(productId string) (*[]entities.ProductVariant, error) {
+
+	return &[]entities.ProductVariant{}, nil
+}
+
+func (m *MockStore) UpdateProductVariant(id string, v *entities.ProductVariant) error {
+
+	return nil
+}
+
+func (m *MockStore) DeleteProductVariant(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) CreateCategory(id string, c *entities.Category) error {
+
+	return nil
+}
+
+func (m *MockStore) GetCategoryById(id string) (*entities.Category, error) {
+
+	return &entities.Category{}, nil
+}
+
+func (m *MockStore) GetCategoryBySlug(slug string) (*entities.Category, error) {
+
+	return &entities.Category{}, nil
+}
+
+func (m *MockStore) ListCategories() (*[]entities.Category, error) {
+
+	return &[]entities.Category{}, nil
+}
+
+func (m *MockStore) UpdateCategory(id string, c *entities.Category) error {
+
+	return nil
+}
+
+func (m *MockStore) DeleteCategory(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) GetCategoryPath(id string) (*[]entities.CategoryMinimal, error) {
+
+	return &[]entities.CategoryMinimal{}, nil
+}
+
+func (m *MockStore) CountCategoryUsage(id string) (int, int, error) {
+
+	return 0, 0, nil
+}
+
+func (m *MockStore) SuggestProducts(search string, limit int) (*[]ProductSuggestion, error) {
+
+	return &[]ProductSuggestion{}, nil
+}
+
+func (m *MockStore) ListProductFacets(q types.ListQueryValid, userId string) (*ProductFacets, error) {
+
+	return &ProductFacets{}, nil
+}
+
+func (m *MockStore) CreateReview(id string, r *entities.Review) error {
+
+	return nil
+}
+
+func (m *MockStore) GetReview(id string) (*entities.Review, error) {
+
+	return &entities.Review{}, nil
+}
+
+func (m *MockStore) ListProductReviews(productId string, q types.ListQueryReviewValid) (*[]entities.Review, error) {
+
+	return &[]entities.Review{}, nil
+}
+
+func (m *MockStore) UpdateReview(id string, rating int, body string) error {
+
+	return nil
+}
+
+func (m *MockStore) ReplyReview(id, reply string) error {
+
+	return nil
+}
+
+func (m *MockStore) VoteReview(reviewId, userId string) error {
+
+	return nil
+}
+
+func (m *MockStore) UnvoteReview(reviewId, userId string) error {
+
+	return nil
+}
+
+func (m *MockStore) CreateWishlist(id, userId, name string) error {
+
+	return nil
+}
+
+func (m *MockStore) GetWishlist(id string) (*entities.Wishlist, error) {
+
+	return &entities.Wishlist{}, nil
+}
+
+func (m *MockStore) GetWishlistByShareToken(token string) (*entities.Wishlist, error) {
+
+	return &entities.Wishlist{}, nil
+}
+
+func (m *MockStore) ListWishlists(userId string) (*[]entities.Wishlist, error) {
+
+	return &[]entities.Wishlist{}, nil
+}
+
+func (m *MockStore) UpdateWishlist(id, name string) error {
+
+	return nil
+}
+
+func (m *MockStore) SetWishlistShareToken(id, token string) error {
+
+	return nil
+}
+
+func (m *MockStore) DeleteWishlist(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) AddWishlistItem(id, wishlistId string, item *entities.WishlistItem) error {
+
+	return nil
+}
+
+func (m *MockStore) ListWishlistItems(wishlistId string) (*[]entities.WishlistItem, error) {
+
+	return &[]entities.WishlistItem{}, nil
+}
+
+func (m *MockStore) DeleteWishlistItem(wishlistId, itemId string) error {
+
+	return nil
+}
+
+func (m *MockStore) CreateProductQuestion(id string, q *entities.ProductQuestion) error {
+
+	return nil
+}
+
+func (m *MockStore) GetProductQuestion(id string) (*entities.ProductQuestion, error) {
+
+	return &entities.ProductQuestion{}, nil
+}
+
+func (m *MockStore) ListProductQuestions(productId string, limit, offset int, includeHidden bool) (*[]entities.ProductQuestion, int, error) {
+
+	return &[]entities.ProductQuestion{}, 0, nil
+}
+
+func (m *MockStore) AnswerProductQuestion(id, answer string) error {
+
+	return nil
+}
+
+func (m *MockStore) SetProductQuestionHidden(id string, hidden bool) error {
+
+	return nil
+}
+
+func (m *MockStore) DeleteProductQuestion(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) GetDeletedUserByUsername(username string) (*entities.User, error) {
+
+	return &entities.User{}, nil
+}
+
+func (m *MockStore) RestoreUser(id string, grace time.Duration) error {
+
+	return nil
+}
+
+func (m *MockStore) RestoreProduct(id, sellerId string, grace time.Duration) error {
+
+	return nil
+}
+
+func (m *MockStore) RestoreBankAccount(id, sellerId string, grace time.Duration) error {
+
+	return nil
+}
+
+func (m *MockStore) PurgeDeleted(grace time.Duration) (*PurgeResult, error) {
+
+	return &PurgeResult{}, nil
+}
+
+func (m *MockStore) CreateProductHistory(productId, changedBy string, before, after *entities.Product) error {
+
+	return nil
+}
+
+func (m *MockStore) ListProductHistory(productId string, limit, offset int) (*[]entities.ProductHistory, int, error) {
+
+	return &[]entities.ProductHistory{}, 0, nil
+}
+
+func (m *MockStore) ListProductPriceHistory(productId string) (*[]entities.PricePoint, error) {
+
+	return &[]entities.PricePoint{}, nil
+}
+
+func (m *MockStore) UpdateProductStatus(id string, p *entities.Product) error {
+
+	return nil
+}
+
+func (m *MockStore) ApplyProductSchedule() (int64, int64, error) {
+
+	return 0, 0, nil
+}
+
+func (m *MockStore) CreateDiscount(id string, d *entities.Discount) error {
+
+	return nil
+}
+
+func (m *MockStore) GetDiscount(id string) (*entities.Discount, error) {
+
+	return &entities.Discount{}, nil
+}
+
+func (m *MockStore) ListProductDiscounts(productId string) (*[]entities.Discount, error) {
+
+	return &[]entities.Discount{}, nil
+}
+
+func (m *MockStore) DeleteDiscount(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) CountOverlappingDiscounts(productId string, startsAt, endsAt time.Time) (int, error) {
+
+	return 0, nil
+}
+
+func (m *MockStore) GetActiveDiscount(productId string) (*entities.Discount, error) {
+
+	return nil, nil
+}
+
+func (m *MockStore) ListActiveDiscounts(productIds []string) (map[string]entities.Discount, error) {
+
+	return map[string]entities.Discount{}, nil
+}
+
+func (m *MockStore) ClaimDiscount(discountId, buyerId, transactionId string, quantity int) error {
+
+	return nil
+}
+
+func (m *MockStore) ReleaseDiscountClaim(transactionId string) error {
+
+	return nil
+}
+
+func (m *MockStore) CreateCoupon(id string, c *entities.Coupon) error {
+
+	return nil
+}
+
+func (m *MockStore) GetCoupon(id string) (*entities.Coupon, error) {
+
+	return &entities.Coupon{}, nil
+}
+
+func (m *MockStore) GetCouponByCode(code string) (*entities.Coupon, error) {
+
+	return &entities.Coupon{}, nil
+}
+
+func (m *MockStore) ListCoupons(sellerId string) (*[]entities.Coupon, error) {
+
+	return &[]entities.Coupon{}, nil
+}
+
+func (m *MockStore) DeleteCoupon(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) ClaimCoupon(couponId, userId, transactionId string, amount float64) error {
+
+	return nil
+}
+
+func (m *MockStore) ReleaseCouponClaim(transactionId string) error {
+
+	return nil
+}
+
+func (m *MockStore) AdjustStock(movement *entities.StockMovement) (int, error) {
+
+	return 0, nil
+}
+
+func (m *MockStore) SetStock(movement *entities.StockMovement, stock int) (int, error) {
+
+	return stock, nil
+}
+
+func (m *MockStore) ReserveStock(r *entities.StockReservation) error {
+
+	return nil
+}
+
+func (m *MockStore) AcceptTransaction(transactionId, from, actorId string) error {
+
+	return nil
+}
+
+func (m *MockStore) ReleaseReservation(transactionId, actorId, reason string) error {
+
+	return nil
+}
+
+func (m *MockStore) ReleaseExpiredReservations() ([]string, error) {
+
+	return []string{}, nil
+}
+
+func (m *MockStore) ListStockMovements(productId, movementType string, limit, offset int) (*[]entities.StockMovement, int, error) {
+
+	return &[]entities.StockMovement{}, 0, nil
+}
+
+func (m *MockStore) CreateWarehouse(id string, w *entities.Warehouse) error {
+
+	return nil
+}
+
+func (m *MockStore) GetWarehouse(id string) (*entities.Warehouse, error) {
+
+	return &entities.Warehouse{ID: id}, nil
+}
+
+func (m *MockStore) ListWarehouses(sellerId string) (*[]entities.Warehouse, error) {
+
+	return &[]entities.Warehouse{}, nil
+}
+
+func (m *MockStore) UpdateWarehouse(id string, w *entities.Warehouse) error {
+
+	return nil
+}
+
+func (m *MockStore) DeleteWarehouse(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) ListWarehouseStocks(productId string) (*[]entities.WarehouseStock, error) {
+
+	return &[]entities.WarehouseStock{}, nil
+}
+
+func (m *MockStore) TransferStock(t *entities.StockTransfer) error {
+
+	return nil
+}
+
+func (m *MockStore) ChooseFulfilmentWarehouse(productId, variantId string, quantity int, latitude, longitude *float64) (string, error) {
+
+	return "", nil
+}
+
+func (m *MockStore) SetLowStockThreshold(productId string, threshold int) error {
+
+	return nil
+}
+
+func (m *MockStore) NotifyLowStock(productId string, quantity int) error {
+
+	return nil
+}
+
+func (m *MockStore) SubscribeStock(productId, userId string) error {
+
+	return nil
+}
+
+func (m *MockStore) UnsubscribeStock(productId, userId string) error {
+
+	return nil
+}
+
+func (m *MockStore) NotifyBackInStock(productId string) (int, error) {
+
+	return 0, nil
+}
+
+func (m *MockStore) ListNotifications(userId string, unreadOnly bool, limit, offset int) (*[]entities.Notification, int, error) {
+
+	return &[]entities.Notification{}, 0, nil
+}
+
+func (m *MockStore) MarkNotificationRead(id, userId string) error {
+
+	return nil
+}
+
+func (m *MockStore) MarkAllNotificationsRead(userId string) error {
+
+	return nil
+}
+
+func (m *MockStore) GetProductBySku(sellerId, sku string) (*entities.Product, error) {
+
+	return nil, nil
+}
+
+func (m *MockStore) ListSellerProducts(sellerId string) (*[]entities.Product, error) {
+
+	return &[]entities.Product{}, nil
+}
+
+func (m *MockStore) CreateProductImportJob(id string, job *entities.ProductImportJob) error {
+
+	return nil
+}
+
+func (m *MockStore) GetProductImportJob(id string) (*entities.ProductImportJob, error) {
+
+	return &entities.ProductImportJob{ID: id}, nil
+}
+
+func (m *MockStore) ClaimProductImportJob() (*entities.ProductImportJob, error) {
+
+	return nil, nil
+}
+
+func (m *MockStore) FinishProductImportJob(job *entities.ProductImportJob) error {
+
+	return nil
+}
+
+func (m *MockStore) BatchUpdateProducts(sellerId string, items []entities.ProductBatchItem, atomic bool) ([]entities.ProductBatchResult, error) {
+
+	results := make([]entities.ProductBatchResult, len(items))
+	for i, item := range items {
+		results[i] = entities.ProductBatchResult{Index: i, ProductId: item.ProductId, VariantId: item.VariantId, Success: true}
+	}
+
+	return results, nil
+}
+
+func (m *MockStore) CreateStorefront(id string, f *entities.Storefront) error {
+
+	return nil
+}
+
+func (m *MockStore) GetStorefrontBySeller(sellerId string) (*entities.Storefront, error) {
+
+	return &entities.Storefront{}, ErrStorefrontNotFound
+}
+
+func (m *MockStore) GetStorefrontBySlug(slug string) (*entities.Storefront, error) {
+
+	return &entities.Storefront{}, ErrStorefrontNotFound
+}
+
+func (m *MockStore) UpdateStorefront(id string, f *entities.Storefront) error {
+
+	return nil
+}
+
+func (m *MockStore) GetStorefrontStats(sellerId string) (*entities.StorefrontStats, error) {
+
+	return &entities.StorefrontStats{}, nil
+}
+
+func (m *MockStore) GetUserStats(id string) (*entities.UserStats, error) {
+
+	return &entities.UserStats{}, nil
+}
+
+func (m *MockStore) UpdateUserPassword(id, password string) (int, error) {
+
+	return 1, nil
+}
+
+func (m *MockStore) CreatePasswordReset(userId, tokenHash string, expiresAt time.Time) error {
+
+	return nil
+}
+
+func (m *MockStore) ResetPassword(tokenHash, password string) (string, error) {
+
+	return "", ErrPasswordResetInvalid
+}
+
+func (m *MockStore) UpdateUserContact(id, email, phone string) error {
+
+	return nil
+}
+
+func (m *MockStore) CreateContactVerification(v *entities.ContactVerification) error {
+
+	return nil
+}
+
+func (m *MockStore) GetLatestContactVerification(userId, channel string) (*entities.ContactVerification, error) {
+
+	return nil, nil
+}
+
+func (m *MockStore) VerifyContact(userId, channel, codeHash string) error {
+
+	return ErrContactVerificationInvalid
+}
+
+func (m *MockStore) SetTOTPSecret(id, secret string) error {
+
+	return nil
+}
+
+func (m *MockStore) EnableTOTP(id string, step int64, recoveryCodeHashes []string) error {
+
+	return nil
+}
+
+func (m *MockStore) DisableTOTP(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) UseTOTPStep(id string, step int64) error {
+
+	return nil
+}
+
+func (m *MockStore) UseRecoveryCode(id, codeHash string) error {
+
+	return ErrTOTPInvalid
+}
+
+func (m *MockStore) RecordTOTPFailure(id string) error {
+
+	return nil
+}
+
+func (m *MockStore) ReplaceRecoveryCodes(id string, recoveryCodeHashes []string) error {
+
+	return nil
+}
+
+func (m *MockStore) CountRecoveryCodes(id string) (int, error) {
+
+	return 0, nil
+}
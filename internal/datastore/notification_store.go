@@ -0,0 +1,202 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/google/uuid"
+)
+
+var ErrNotificationNotFound = fmt.Errorf("Notification did not exists")
+
+// threshold 0 = alert stock menipis tidak aktif
+func (s *Storage) SetLowStockThreshold(productId string, threshold int) error {
+
+	_, err := s.db.Exec(`UPDATE products SET lowStockThreshold = $1, updatedAt = NOW() WHERE id = $2`, threshold, productId)
+
+	return err
+}
+
+// kabari seller kalau stock baru saja turun di bawah threshold karena penjualan sebanyak quantity,
+// hanya sekali saat melewati threshold supaya tidak dikirim di setiap penjualan
+func (s *Storage) NotifyLowStock(productId string, quantity int) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO notifications (id, userId, type, message, productId)
+        SELECT $1, sellerId, 'low_stock', LEFT(FORMAT('Stock of %s is running low (%s left)', name, stock), 255), id
+        FROM products
+        WHERE id = $2
+            AND deletedAt IS NULL
+            AND lowStockThreshold > 0
+            AND stock < lowStockThreshold
+            AND stock + $3 >= lowStockThreshold`, uuid.NewString(), productId, quantity)
+
+	return err
+}
+
+// subscribe yang masih menunggu tidak diduplikasi
+func (s *Storage) SubscribeStock(productId, userId string) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO stockSubscriptions (id, productId, userId)
+        VALUES ($1, $2, $3)
+        ON CONFLICT (productId, userId) WHERE notifiedAt IS NULL DO NOTHING`, uuid.NewString(), productId, userId)
+
+	return err
+}
+
+func (s *Storage) UnsubscribeStock(productId, userId string) error {
+
+	_, err := s.db.Exec(`DELETE FROM stockSubscriptions WHERE productId = $1 AND userId = $2 AND notifiedAt IS NULL`, productId, userId)
+
+	return err
+}
+
+// kabari semua buyer yang subscribe kalau stock product sudah tersedia lagi,
+// subscription hanya dipakai sekali. return jumlah buyer yang dikabari
+func (s *Storage) NotifyBackInStock(productId string) (int, error) {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	var name string
+	var stock int
+	err = tx.QueryRow(`SELECT name, stock FROM products WHERE id = $1 AND deletedAt IS NULL`, productId).Scan(&name, &stock)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, nil
+	case err != nil:
+		return 0, err
+	}
+
+	if stock <= 0 {
+		return 0, nil
+	}
+
+	rows, err := tx.Query(`
+        UPDATE stockSubscriptions
+        SET notifiedAt = NOW()
+        WHERE productId = $1 AND notifiedAt IS NULL
+        RETURNING userId`, productId)
+	if err != nil {
+		return 0, err
+	}
+
+	userIds := []string{}
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		userIds = append(userIds, userId)
+	}
+	rows.Close()
+
+	message := fmt.Sprintf("%s is back in stock", name)
+	if len(message) > 255 {
+		message = message[:255]
+	}
+
+	for _, userId := range userIds {
+		if _, err := tx.Exec(`
+            INSERT INTO notifications (id, userId, type, message, productId)
+            VALUES ($1, $2, 'back_in_stock', $3, $4)`, uuid.NewString(), userId, message, productId); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(userIds), tx.Commit()
+}
+
+// notifikasi user terbaru dulu beserta total
+func (s *Storage) ListNotifications(userId string, unreadOnly bool, limit, offset int) (*[]entities.Notification, int, error) {
+
+	returnNotifications := []entities.Notification{}
+
+	var total int
+	err := s.db.QueryRow(`
+        SELECT COUNT(*) FROM notifications
+        WHERE userId = $1 AND (NOT $2 OR readAt IS NULL)`, userId, unreadOnly).Scan(&total)
+	if err != nil {
+		log.Println("err when counting notifications", err)
+		return &returnNotifications, 0, err
+	}
+
+	rows, err := s.db.Query(`
+        SELECT id, userId, type, message, productId, readAt, createdAt
+        FROM notifications
+        WHERE userId = $1 AND (NOT $2 OR readAt IS NULL)
+        ORDER BY createdAt DESC, id DESC
+        LIMIT $3 OFFSET $4`, userId, unreadOnly, limit, offset)
+	if err != nil {
+		log.Println("err inside ListNotifications", err)
+		return &returnNotifications, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var notification entities.Notification
+		var productId sql.NullString
+		var readAt sql.NullTime
+
+		if err := rows.Scan(
+			&notification.ID,
+			&notification.UserId,
+			&notification.Type,
+			&notification.Message,
+			&productId,
+			&readAt,
+			&notification.CreatedAt,
+		); err != nil {
+			return &[]entities.Notification{}, 0, err
+		}
+
+		notification.ProductId = productId.String
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+
+		returnNotifications = append(returnNotifications, notification)
+	}
+
+	return &returnNotifications, total, nil
+}
+
+func (s *Storage) MarkNotificationRead(id, userId string) error {
+
+	res, err := s.db.Exec(`
+        UPDATE notifications
+        SET readAt = COALESCE(readAt, NOW())
+        WHERE id = $1 AND userId = $2`, id, userId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return ErrNotificationNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) MarkAllNotificationsRead(userId string) error {
+
+	_, err := s.db.Exec(`UPDATE notifications SET readAt = NOW() WHERE userId = $1 AND readAt IS NULL`, userId)
+
+	return err
+}
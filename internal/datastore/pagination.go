@@ -0,0 +1,118 @@
+package datastore
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+)
+
+// satu kolom/ekspresi yang dipakai untuk sort dan keyset pagination.
+// Cast adalah tipe postgres untuk value cursor (value cursor disimpan sebagai text)
+type sortKey struct {
+	Expr string
+	Cast string
+	Desc bool
+}
+
+// ekspresi sort ikut di-select sebagai text supaya bisa dipakai untuk bikin cursor
+func generateSelectSortKeys(keys []sortKey) string {
+
+	query := ""
+	for i, key := range keys {
+		query += `,
+        (` + key.Expr + `)::text AS sortKey` + strconv.Itoa(i)
+	}
+
+	return query
+}
+
+func generateOrderBy(keys []sortKey, backward bool) string {
+
+	var orders []string
+	for _, key := range keys {
+		desc := key.Desc != backward
+		if desc {
+			orders = append(orders, key.Expr+` DESC`)
+		} else {
+			orders = append(orders, key.Expr+` ASC`)
+		}
+	}
+
+	return ` ORDER BY ` + strings.Join(orders, ", ")
+}
+
+// kondisi keyset untuk row setelah (atau sebelum kalau backward) posisi cursor:
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... , operator mengikuti arah sort tiap key
+func generateKeysetCondition(keys []sortKey, cursor *types.Cursor, queryIndex int) (string, []interface{}, int) {
+
+	var params []interface{}
+	var ors []string
+
+	for i := range keys {
+		var ands []string
+
+		for j := 0; j <= i; j++ {
+			placeholder := `$` + strconv.Itoa(queryIndex+j) + `::` + keys[j].Cast
+
+			if j < i {
+				ands = append(ands, keys[j].Expr+` = `+placeholder)
+				continue
+			}
+
+			op := `>`
+			if keys[j].Desc != cursor.Backward {
+				op = `<`
+			}
+
+			ands = append(ands, keys[j].Expr+` `+op+` `+placeholder)
+		}
+
+		ors = append(ors, `(`+strings.Join(ands, ` AND `)+`)`)
+	}
+
+	for _, value := range cursor.Values {
+		params = append(params, value)
+	}
+
+	return `(` + strings.Join(ors, ` OR `) + `)`, params, queryIndex + len(keys)
+}
+
+// cursor hanya valid kalau dibuat dari sort yang sama dan jumlah value-nya cocok
+func validCursor(cursor *types.Cursor, keys []sortKey) bool {
+
+	return cursor != nil && len(cursor.Values) == len(keys)
+}
+
+// bikin cursor next/prev dari sort value row pertama dan terakhir halaman.
+// rows sudah dalam urutan tampil (kalau backward sudah dibalik)
+func buildPageInfo(order string, cursor *types.Cursor, hasMore bool, hasOffset bool, sortValues [][]string) *types.PageInfo {
+
+	pageInfo := &types.PageInfo{}
+	if len(sortValues) == 0 {
+		return pageInfo
+	}
+
+	backward := cursor != nil && cursor.Backward
+	first := sortValues[0]
+	last := sortValues[len(sortValues)-1]
+
+	// next ada kalau masih ada row setelah halaman ini, atau kalau kita datang dari arah belakang
+	if (!backward && hasMore) || backward {
+		pageInfo.Next = helper.EncodeCursor(types.Cursor{Order: order, Values: last})
+	}
+
+	// prev ada kalau halaman ini bukan halaman pertama
+	if (backward && hasMore) || (!backward && (cursor != nil || hasOffset)) {
+		pageInfo.Prev = helper.EncodeCursor(types.Cursor{Order: order, Values: first, Backward: true})
+	}
+
+	return pageInfo
+}
+
+func reverseSortValues(values [][]string) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
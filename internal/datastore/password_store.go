@@ -0,0 +1,96 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/google/uuid"
+)
+
+var ErrPasswordResetInvalid = fmt.Errorf("Invalid or expired reset token")
+
+// tokenVersion ikut naik, token yang dibuat sebelumnya jadi tidak berlaku. return tokenVersion baru
+func (s *Storage) UpdateUserPassword(id, password string) (int, error) {
+
+	var tokenVersion int
+	err := s.db.QueryRow(`
+        UPDATE users
+        SET hashPassword = $1,
+            passwordChangedAt = NOW(),
+            tokenVersion = tokenVersion + 1,
+            updatedAt = NOW()
+        WHERE id = $2 AND deletedAt IS NULL
+        RETURNING tokenVersion`, helper.GenerateHash(password), id).Scan(&tokenVersion)
+
+	return tokenVersion, err
+}
+
+// token reset lama yang belum dipakai langsung tidak berlaku, hanya token terakhir yang bisa dipakai
+func (s *Storage) CreatePasswordReset(userId, tokenHash string, expiresAt time.Time) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM passwordResets WHERE userId = $1 AND usedAt IS NULL`, userId); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        INSERT INTO passwordResets (id, userId, tokenHash, expiresAt)
+        VALUES ($1, $2, $3, $4)`, uuid.NewString(), userId, tokenHash, expiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// pakai token reset (sekali pakai) lalu ganti password, return user id
+func (s *Storage) ResetPassword(tokenHash, password string) (string, error) {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return "", err
+	}
+
+	defer tx.Rollback()
+
+	var resetId, userId string
+	err = tx.QueryRow(`
+        SELECT passwordResets.id, passwordResets.userId
+        FROM passwordResets
+        JOIN users ON passwordResets.userId = users.id
+        WHERE passwordResets.tokenHash = $1
+            AND passwordResets.usedAt IS NULL
+            AND passwordResets.expiresAt > NOW()
+            AND users.deletedAt IS NULL
+        FOR UPDATE OF passwordResets`, tokenHash).Scan(&resetId, &userId)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return "", ErrPasswordResetInvalid
+	case err != nil:
+		return "", err
+	}
+
+	if _, err := tx.Exec(`UPDATE passwordResets SET usedAt = NOW() WHERE id = $1`, resetId); err != nil {
+		return "", err
+	}
+
+	if _, err := tx.Exec(`
+        UPDATE users
+        SET hashPassword = $1,
+            passwordChangedAt = NOW(),
+            tokenVersion = tokenVersion + 1,
+            updatedAt = NOW()
+        WHERE id = $2`, helper.GenerateHash(password), userId); err != nil {
+		return "", err
+	}
+
+	return userId, tx.Commit()
+}
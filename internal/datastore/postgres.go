@@ -55,9 +55,562 @@ func (s *PostgresStorage) Init() (*sql.DB, error) {
 		return nil, err
 	}
 
+	// bikin tabel productImage
+	if err := s.createProductImageTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel productVariant
+	if err := s.createProductVariantTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel category
+	if err := s.createCategoryTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel review
+	if err := s.createReviewTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel wishlist
+	if err := s.createWishlistTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel productQuestion
+	if err := s.createProductQuestionTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel productHistory
+	if err := s.createProductHistoryTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel discount
+	if err := s.createDiscountTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel coupon
+	if err := s.createCouponTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel stock ledger
+	if err := s.createStockTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel warehouse
+	if err := s.createWarehouseTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel notification
+	if err := s.createNotificationTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel productImportJob
+	if err := s.createProductImportTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel storefront
+	if err := s.createStorefrontTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel passwordReset
+	if err := s.createPasswordResetTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel contactVerification
+	if err := s.createContactVerificationTable(); err != nil {
+		return nil, err
+	}
+
+	// bikin tabel recoveryCode
+	if err := s.createRecoveryCodeTable(); err != nil {
+		return nil, err
+	}
+
 	return s.db, nil
 }
 
+func (s *PostgresStorage) createRecoveryCodeTable() error {
+	_, err := s.db.Exec(`
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS totpSecret VARCHAR(64) NOT NULL DEFAULT '';
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS totpEnabledAt TIMESTAMPTZ;
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS totpLastStep BIGINT NOT NULL DEFAULT 0;
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS totpFailures INTEGER NOT NULL DEFAULT 0;
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS totpLockedUntil TIMESTAMPTZ;
+
+        CREATE TABLE IF NOT EXISTS recoveryCodes (
+            id uuid NOT NULL PRIMARY KEY,
+            userId uuid NOT NULL,
+            codeHash VARCHAR(64) NOT NULL,
+            usedAt TIMESTAMPTZ,
+
+            createdAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        );
+        CREATE UNIQUE INDEX IF NOT EXISTS recoveryCodes_userId_codeHash_idx ON recoveryCodes (userId, codeHash);`)
+
+	return err
+}
+
+func (s *PostgresStorage) createContactVerificationTable() error {
+	_, err := s.db.Exec(`
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS email VARCHAR(255) NOT NULL DEFAULT '';
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(20) NOT NULL DEFAULT '';
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS emailVerifiedAt TIMESTAMPTZ;
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS phoneVerifiedAt TIMESTAMPTZ;
+        CREATE UNIQUE INDEX IF NOT EXISTS users_email_idx ON users (email) WHERE email <> '' AND deletedAt IS NULL;
+        CREATE UNIQUE INDEX IF NOT EXISTS users_phone_idx ON users (phone) WHERE phone <> '' AND deletedAt IS NULL;
+
+        CREATE TABLE IF NOT EXISTS contactVerifications (
+            id uuid NOT NULL PRIMARY KEY,
+            userId uuid NOT NULL,
+            channel VARCHAR(10) NOT NULL,
+            target VARCHAR(255) NOT NULL,
+            codeHash VARCHAR(64) NOT NULL,
+            attempts INTEGER NOT NULL DEFAULT 0,
+            expiresAt TIMESTAMPTZ NOT NULL,
+            verifiedAt TIMESTAMPTZ,
+
+            createdAt TIMESTAMPTZ NOT NULL DEFAULT NOW()
+        );
+        CREATE INDEX IF NOT EXISTS contactVerifications_userId_idx ON contactVerifications (userId, channel, createdAt DESC);`)
+
+	return err
+}
+
+func (s *PostgresStorage) createPasswordResetTable() error {
+	_, err := s.db.Exec(`
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS passwordChangedAt TIMESTAMPTZ;
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS tokenVersion INT NOT NULL DEFAULT 0;
+
+        CREATE TABLE IF NOT EXISTS passwordResets (
+            id uuid NOT NULL PRIMARY KEY,
+            userId uuid NOT NULL,
+            tokenHash VARCHAR(64) NOT NULL UNIQUE,
+            expiresAt TIMESTAMPTZ NOT NULL,
+            usedAt TIMESTAMPTZ,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS passwordResets_userId_idx ON passwordResets (userId);`)
+
+	return err
+}
+
+func (s *PostgresStorage) createStorefrontTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS storefronts (
+            id uuid NOT NULL PRIMARY KEY,
+            sellerId uuid NOT NULL,
+            name VARCHAR(100) NOT NULL,
+            slug VARCHAR(100) NOT NULL,
+            description TEXT NOT NULL DEFAULT '',
+            logoUrl VARCHAR(255) NOT NULL DEFAULT '',
+            bannerUrl VARCHAR(255) NOT NULL DEFAULT '',
+            location VARCHAR(100) NOT NULL DEFAULT '',
+            openingHours JSONB NOT NULL DEFAULT '{}',
+            vacationMode BOOLEAN NOT NULL DEFAULT FALSE,
+            vacationMessage VARCHAR(255) NOT NULL DEFAULT '',
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE UNIQUE INDEX IF NOT EXISTS storefronts_sellerId_idx ON storefronts (sellerId) WHERE deletedAt IS NULL;
+        CREATE UNIQUE INDEX IF NOT EXISTS storefronts_slug_idx ON storefronts (slug) WHERE deletedAt IS NULL;
+
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS vacationPaused BOOLEAN NOT NULL DEFAULT FALSE;`)
+
+	return err
+}
+
+func (s *PostgresStorage) createProductImportTable() error {
+	_, err := s.db.Exec(`
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64) NOT NULL DEFAULT '';
+        CREATE UNIQUE INDEX IF NOT EXISTS products_seller_sku_idx
+            ON products (sellerId, sku) WHERE sku <> '' AND deletedAt IS NULL;
+
+        CREATE TABLE IF NOT EXISTS productImportJobs (
+            id uuid NOT NULL PRIMARY KEY,
+            sellerId uuid NOT NULL,
+            format VARCHAR(10) NOT NULL,
+            status VARCHAR(20) NOT NULL DEFAULT 'pending',
+            totalRows INTEGER NOT NULL DEFAULT 0,
+            createdCount INTEGER NOT NULL DEFAULT 0,
+            updatedCount INTEGER NOT NULL DEFAULT 0,
+            failedCount INTEGER NOT NULL DEFAULT 0,
+            errors JSONB NOT NULL DEFAULT '[]',
+            payload TEXT NOT NULL DEFAULT '',
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            startedAt TIMESTAMP,
+            finishedAt TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS productImportJobs_status_idx ON productImportJobs (status, createdAt);`)
+
+	return err
+}
+
+func (s *PostgresStorage) createNotificationTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS notifications (
+            id uuid NOT NULL PRIMARY KEY,
+            userId uuid NOT NULL,
+            type VARCHAR(30) NOT NULL,
+            message VARCHAR(255) NOT NULL,
+            productId uuid,
+            readAt TIMESTAMP,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS notifications_userId_idx ON notifications (userId, createdAt DESC);
+
+        CREATE TABLE IF NOT EXISTS stockSubscriptions (
+            id uuid NOT NULL PRIMARY KEY,
+            productId uuid NOT NULL,
+            userId uuid NOT NULL,
+            notifiedAt TIMESTAMP,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE UNIQUE INDEX IF NOT EXISTS stockSubscriptions_pending_idx
+            ON stockSubscriptions (productId, userId) WHERE notifiedAt IS NULL;
+
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS lowStockThreshold INTEGER NOT NULL DEFAULT 0;`)
+
+	return err
+}
+
+func (s *PostgresStorage) createWarehouseTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS warehouses (
+            id uuid NOT NULL PRIMARY KEY,
+            sellerId uuid NOT NULL,
+            name VARCHAR(100) NOT NULL,
+            address VARCHAR(255) NOT NULL DEFAULT '',
+            city VARCHAR(100) NOT NULL DEFAULT '',
+            latitude DOUBLE PRECISION,
+            longitude DOUBLE PRECISION,
+            priority INTEGER NOT NULL DEFAULT 0,
+            isActive BOOLEAN NOT NULL DEFAULT TRUE,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS warehouses_sellerId_idx ON warehouses (sellerId);
+
+        CREATE TABLE IF NOT EXISTS warehouseStocks (
+            id uuid NOT NULL PRIMARY KEY,
+            warehouseId uuid NOT NULL REFERENCES warehouses (id),
+            productId uuid NOT NULL,
+            variantId uuid,
+            stock INTEGER NOT NULL DEFAULT 0 CHECK (stock >= 0),
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE UNIQUE INDEX IF NOT EXISTS warehouseStocks_unique_idx
+            ON warehouseStocks (warehouseId, productId, COALESCE(variantId, '00000000-0000-0000-0000-000000000000'));
+        CREATE INDEX IF NOT EXISTS warehouseStocks_productId_idx ON warehouseStocks (productId);
+
+        ALTER TABLE stockMovements ADD COLUMN IF NOT EXISTS warehouseId uuid;
+        ALTER TABLE stockReservations ADD COLUMN IF NOT EXISTS warehouseId uuid;`)
+
+	return err
+}
+
+func (s *PostgresStorage) createStockTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS stockMovements (
+            id uuid NOT NULL PRIMARY KEY,
+            productId uuid NOT NULL,
+            variantId uuid,
+            type VARCHAR(20) NOT NULL,
+            delta INTEGER NOT NULL,
+            stockAfter INTEGER NOT NULL,
+            reason VARCHAR(255) NOT NULL DEFAULT '',
+            actorId uuid,
+            transactionId uuid,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS stockMovements_productId_idx ON stockMovements (productId, createdAt);
+
+        CREATE TABLE IF NOT EXISTS stockReservations (
+            id uuid NOT NULL PRIMARY KEY,
+            transactionId uuid NOT NULL UNIQUE,
+            productId uuid NOT NULL,
+            variantId uuid,
+            buyerId uuid NOT NULL,
+            quantity INTEGER NOT NULL,
+            status VARCHAR(20) NOT NULL DEFAULT 'active',
+            expiresAt TIMESTAMPTZ NOT NULL,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS stockReservations_active_idx ON stockReservations (expiresAt) WHERE status = 'active';`)
+
+	return err
+}
+
+func (s *PostgresStorage) createCouponTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS coupons (
+            id uuid NOT NULL PRIMARY KEY,
+            code VARCHAR(32) NOT NULL,
+            sellerId uuid,
+            type VARCHAR(20) NOT NULL,
+            value NUMERIC(100,2) NOT NULL,
+            maxDiscount NUMERIC(100,2) NOT NULL DEFAULT 0,
+            minSpend NUMERIC(100,2) NOT NULL DEFAULT 0,
+            productIds uuid[] NOT NULL DEFAULT '{}',
+            categoryIds uuid[] NOT NULL DEFAULT '{}',
+            usageLimit INTEGER NOT NULL DEFAULT 0,
+            perUserLimit INTEGER NOT NULL DEFAULT 0,
+            usedCount INTEGER NOT NULL DEFAULT 0,
+            startsAt TIMESTAMPTZ NOT NULL,
+            expiresAt TIMESTAMPTZ NOT NULL,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE UNIQUE INDEX IF NOT EXISTS coupons_code_idx ON coupons (code) WHERE deletedAt IS NULL;
+
+        CREATE TABLE IF NOT EXISTS couponRedemptions (
+            id uuid NOT NULL PRIMARY KEY,
+            couponId uuid NOT NULL REFERENCES coupons (id),
+            userId uuid NOT NULL,
+            transactionId uuid NOT NULL UNIQUE,
+            amount NUMERIC(100,2) NOT NULL,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS couponRedemptions_user_idx ON couponRedemptions (couponId, userId);`)
+
+	return err
+}
+
+func (s *PostgresStorage) createDiscountTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS discounts (
+            id uuid NOT NULL PRIMARY KEY,
+            productId uuid NOT NULL,
+            sellerId uuid NOT NULL,
+            name VARCHAR(100) NOT NULL,
+            type VARCHAR(20) NOT NULL,
+            value NUMERIC(100,2) NOT NULL,
+            startsAt TIMESTAMPTZ NOT NULL,
+            endsAt TIMESTAMPTZ NOT NULL,
+            quantityLimit INTEGER NOT NULL DEFAULT 0,
+            quantitySold INTEGER NOT NULL DEFAULT 0,
+            perBuyerLimit INTEGER NOT NULL DEFAULT 0,
+            isFlashSale BOOLEAN NOT NULL DEFAULT FALSE,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS discounts_productId_idx ON discounts (productId, startsAt);
+
+        CREATE TABLE IF NOT EXISTS discountRedemptions (
+            id uuid NOT NULL PRIMARY KEY,
+            discountId uuid NOT NULL REFERENCES discounts (id),
+            buyerId uuid NOT NULL,
+            transactionId uuid NOT NULL UNIQUE,
+            quantity INTEGER NOT NULL,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS discountRedemptions_buyer_idx ON discountRedemptions (discountId, buyerId);`)
+
+	return err
+}
+
+func (s *PostgresStorage) createProductHistoryTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS productHistory (
+            id uuid NOT NULL PRIMARY KEY,
+            productId uuid NOT NULL,
+            version INTEGER NOT NULL,
+            changedBy uuid,
+            snapshot JSONB NOT NULL,
+            diff JSONB NOT NULL DEFAULT '{}',
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            UNIQUE (productId, version)
+        );`)
+
+	return err
+}
+
+func (s *PostgresStorage) createProductQuestionTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS productQuestions (
+            id uuid NOT NULL PRIMARY KEY,
+            productId uuid NOT NULL,
+            askerId uuid NOT NULL,
+            question TEXT NOT NULL,
+            answer TEXT NOT NULL DEFAULT '',
+            answeredAt TIMESTAMP,
+            isHidden BOOLEAN NOT NULL DEFAULT FALSE,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS productQuestions_productId_idx ON productQuestions (productId, createdAt);`)
+
+	return err
+}
+
+func (s *PostgresStorage) createWishlistTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS wishlists (
+            id uuid NOT NULL PRIMARY KEY,
+            userId uuid NOT NULL,
+            name VARCHAR(100) NOT NULL,
+            shareToken VARCHAR(64) UNIQUE,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS wishlists_userId_idx ON wishlists (userId);
+
+        CREATE TABLE IF NOT EXISTS wishlistItems (
+            id uuid NOT NULL PRIMARY KEY,
+            wishlistId uuid NOT NULL REFERENCES wishlists (id) ON DELETE CASCADE,
+            productId uuid NOT NULL,
+            variantId uuid,
+            quantity INTEGER NOT NULL DEFAULT 1,
+            priceAtAdd NUMERIC(100,2) NOT NULL,
+            stockAtAdd INTEGER NOT NULL,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE UNIQUE INDEX IF NOT EXISTS wishlistItems_unique_idx 
+            ON wishlistItems (wishlistId, productId, COALESCE(variantId, '00000000-0000-0000-0000-000000000000'));`)
+
+	return err
+}
+
+func (s *PostgresStorage) createReviewTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS reviews (
+            id uuid NOT NULL PRIMARY KEY,
+            transactionId uuid NOT NULL UNIQUE,
+            productId uuid NOT NULL,
+            variantId uuid,
+            buyerId uuid NOT NULL,
+            sellerId uuid NOT NULL,
+            rating SMALLINT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+            body TEXT NOT NULL DEFAULT '',
+            reply TEXT NOT NULL DEFAULT '',
+            repliedAt TIMESTAMP,
+            helpfulCount INTEGER NOT NULL DEFAULT 0,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS reviews_productId_idx ON reviews (productId, createdAt);
+
+        CREATE TABLE IF NOT EXISTS reviewVotes (
+            reviewId uuid NOT NULL REFERENCES reviews (id) ON DELETE CASCADE,
+            userId uuid NOT NULL,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+            PRIMARY KEY (reviewId, userId)
+        );`)
+
+	return err
+}
+
+func (s *PostgresStorage) createCategoryTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS categories (
+            id uuid NOT NULL PRIMARY KEY,
+            parentId uuid REFERENCES categories (id),
+            name VARCHAR(100) NOT NULL,
+            slug VARCHAR(100) NOT NULL UNIQUE,
+            position INTEGER NOT NULL DEFAULT 0,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS categories_parentId_idx ON categories (parentId, position);`)
+
+	return err
+}
+
+func (s *PostgresStorage) createProductVariantTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS productVariants (
+            id uuid NOT NULL PRIMARY KEY,
+            productId uuid NOT NULL,
+            sku VARCHAR(64) NOT NULL,
+            name VARCHAR(100) NOT NULL,
+            attributes JSONB NOT NULL DEFAULT '{}',
+            price NUMERIC(100,2),
+            stock INTEGER NOT NULL DEFAULT 0,
+            imageIds uuid[] NOT NULL DEFAULT '{}',
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP,
+
+            UNIQUE (productId, sku)
+        );
+        CREATE INDEX IF NOT EXISTS productVariants_attributes_idx ON productVariants USING GIN (attributes);
+
+        -- stock product = total stock variant, bisa lebih dari batas SMALLINT
+        DO $$
+        BEGIN
+            IF (SELECT data_type FROM information_schema.columns WHERE table_name = 'products' AND column_name = 'stock') = 'smallint' THEN
+                ALTER TABLE products ALTER COLUMN stock TYPE INTEGER;
+            END IF;
+        END $$;`)
+
+	return err
+}
+
+func (s *PostgresStorage) createProductImageTable() error {
+	_, err := s.db.Exec(`
+        CREATE TABLE IF NOT EXISTS productImages (
+            id uuid NOT NULL PRIMARY KEY,
+            productId uuid NOT NULL,
+            position INTEGER NOT NULL DEFAULT 0,
+            contentType VARCHAR(50) NOT NULL,
+            storageKey VARCHAR(255) NOT NULL,
+
+            createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            deletedAt TIMESTAMP
+        );
+        CREATE INDEX IF NOT EXISTS productImages_productId_idx ON productImages (productId, position);`)
+
+	return err
+}
+
 func (s *PostgresStorage) createTransactionTable() error {
 	_, err := s.db.Exec(`
         CREATE TABLE IF NOT EXISTS transactions (
@@ -73,7 +626,15 @@ func (s *PostgresStorage) createTransactionTable() error {
             createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
             updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
             deletedAt TIMESTAMP
-        )`)
+        );
+        ALTER TABLE transactions ADD COLUMN IF NOT EXISTS variantId uuid;
+        ALTER TABLE transactions ADD COLUMN IF NOT EXISTS discountId uuid;
+        ALTER TABLE transactions ADD COLUMN IF NOT EXISTS discountAmount NUMERIC(100,2) NOT NULL DEFAULT 0;
+        ALTER TABLE transactions ADD COLUMN IF NOT EXISTS couponId uuid;
+        ALTER TABLE transactions ADD COLUMN IF NOT EXISTS subtotal NUMERIC(100,2);
+        ALTER TABLE transactions ADD COLUMN IF NOT EXISTS couponDiscount NUMERIC(100,2) NOT NULL DEFAULT 0;
+        ALTER TABLE transactions ADD COLUMN IF NOT EXISTS warehouseId uuid;
+        UPDATE transactions SET subtotal = total WHERE subtotal IS NULL;`)
 
 	if err != nil {
 		return err
@@ -120,13 +681,49 @@ func (s *PostgresStorage) createProductTable() error {
             createdAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
             updatedAt TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
             deletedAt TIMESTAMP
-        )`)
+        );
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS categoryId uuid;
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'published';
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS publishAt TIMESTAMPTZ;
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS archiveAt TIMESTAMPTZ;
+        CREATE INDEX IF NOT EXISTS products_status_idx ON products (status);
+        CREATE INDEX IF NOT EXISTS products_categoryId_idx ON products (categoryId);
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS ratingAverage NUMERIC(3,2) NOT NULL DEFAULT 0;
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS ratingCount INTEGER NOT NULL DEFAULT 0;`)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return s.createProductSearchIndex()
+}
+
+// full-text search product, bobot: name (A) > tags (B) > descriptions (C).
+// pakai config 'simple' karena isi product campuran bahasa indonesia/inggris
+func (s *PostgresStorage) createProductSearchIndex() error {
+	_, err := s.db.Exec(`
+        ALTER TABLE products ADD COLUMN IF NOT EXISTS searchVector tsvector;
+
+        CREATE OR REPLACE FUNCTION products_search_vector_update() RETURNS trigger AS $$
+        BEGIN
+            NEW.searchVector :=
+                setweight(to_tsvector('simple', coalesce(NEW.name, '')), 'A') ||
+                setweight(to_tsvector('simple', coalesce(array_to_string(NEW.tags, ' '), '')), 'B') ||
+                setweight(to_tsvector('simple', coalesce(NEW.descriptions, '')), 'C');
+            RETURN NEW;
+        END
+        $$ LANGUAGE plpgsql;
+
+        DROP TRIGGER IF EXISTS products_search_vector_trigger ON products;
+        CREATE TRIGGER products_search_vector_trigger
+            BEFORE INSERT OR UPDATE OF name, tags, descriptions ON products
+            FOR EACH ROW EXECUTE FUNCTION products_search_vector_update();
+
+        UPDATE products SET name = name WHERE searchVector IS NULL;
+
+        CREATE INDEX IF NOT EXISTS products_searchVector_idx ON products USING GIN (searchVector);`)
+
+	return err
 }
 
 func (s *PostgresStorage) createUserTable() error {
@@ -0,0 +1,231 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+// batch atomic dibatalkan karena ada item yang gagal
+var ErrProductBatchFailed = fmt.Errorf("Batch update failed, no changes were applied")
+
+// ubah stock dan/atau harga banyak product seller dalam satu transaksi db. atomic = satu item gagal
+// membatalkan semua item, selain itu item yang gagal saja yang dibatalkan (savepoint per item)
+func (s *Storage) BatchUpdateProducts(sellerId string, items []entities.ProductBatchItem, atomic bool) ([]entities.ProductBatchResult, error) {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return make([]entities.ProductBatchResult, len(items)), err
+	}
+
+	defer tx.Rollback()
+
+	results, err := runProductBatch(items, atomic, productBatchSteps{
+		savepoint: func(i int) error {
+			_, err := tx.Exec(`SAVEPOINT batch_item_` + strconv.Itoa(i))
+			return err
+		},
+		rollback: func(i int) error {
+			_, err := tx.Exec(`ROLLBACK TO SAVEPOINT batch_item_` + strconv.Itoa(i))
+			return err
+		},
+		apply: func(item *entities.ProductBatchItem) (entities.ProductBatchResult, error) {
+			return applyProductBatchItem(tx, sellerId, item)
+		},
+	})
+	if err != nil {
+		return results, err
+	}
+
+	return results, tx.Commit()
+}
+
+// langkah per item batch, dipisah dari sql supaya aturan atomic/best effort bisa dites tanpa db
+type productBatchSteps struct {
+	savepoint func(i int) error
+	rollback  func(i int) error
+	apply     func(item *entities.ProductBatchItem) (entities.ProductBatchResult, error)
+}
+
+// jalankan item satu per satu, error dari apply = item gagal, error dari savepoint/rollback
+// menghentikan batch. atomic yang gagal return ErrProductBatchFailed tanpa commit
+func runProductBatch(items []entities.ProductBatchItem, atomic bool, steps productBatchSteps) ([]entities.ProductBatchResult, error) {
+
+	results := make([]entities.ProductBatchResult, len(items))
+
+	failed := false
+	for i, item := range items {
+		if err := steps.savepoint(i); err != nil {
+			return results, err
+		}
+
+		result, err := steps.apply(&item)
+		result.Index = i
+		result.ProductId = item.ProductId
+		result.VariantId = item.VariantId
+
+		if err != nil {
+			failed = true
+			result.Success = false
+			result.Stock, result.Price = nil, nil
+			result.Error = err.Error()
+			results[i] = result
+
+			if atomic {
+				break
+			}
+
+			if err := steps.rollback(i); err != nil {
+				return results, err
+			}
+
+			continue
+		}
+
+		result.Success = true
+		results[i] = result
+	}
+
+	if atomic && failed {
+		for i := range results {
+			results[i].Index = i
+			results[i].ProductId = items[i].ProductId
+			results[i].VariantId = items[i].VariantId
+
+			if results[i].Error == "" {
+				results[i].Success = false
+				results[i].Stock, results[i].Price = nil, nil
+				results[i].Error = "Not applied, another item in the batch failed"
+			}
+		}
+
+		return results, ErrProductBatchFailed
+	}
+
+	return results, nil
+}
+
+func applyProductBatchItem(tx *sql.Tx, sellerId string, item *entities.ProductBatchItem) (entities.ProductBatchResult, error) {
+
+	var result entities.ProductBatchResult
+	failed := fmt.Errorf("Failed to update item")
+
+	var productSellerId string
+	var productStock int
+	var productPrice float64
+	err := tx.QueryRow(`
+        SELECT sellerId, stock, price FROM products
+        WHERE id = $1 AND deletedAt IS NULL
+        FOR UPDATE`, item.ProductId).Scan(&productSellerId, &productStock, &productPrice)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return result, fmt.Errorf("Product not found")
+	case err != nil:
+		log.Println("error when locking product in batch update", err)
+		return result, failed
+	}
+
+	if productSellerId != sellerId {
+		return result, fmt.Errorf("Forbidden")
+	}
+
+	result.PreviousStock = productStock
+	currentStock := productStock
+	currentPrice := productPrice
+
+	if item.VariantId != "" {
+		var variantPrice sql.NullFloat64
+		err := tx.QueryRow(`
+            SELECT stock, price FROM productVariants
+            WHERE id = $1 AND productId = $2
+            FOR UPDATE`, item.VariantId, item.ProductId).Scan(&currentStock, &variantPrice)
+
+		switch {
+		case err == sql.ErrNoRows:
+			return result, fmt.Errorf("Variant not found")
+		case err != nil:
+			log.Println("error when locking variant in batch update", err)
+			return result, failed
+		}
+
+		if variantPrice.Valid {
+			currentPrice = variantPrice.Float64
+		}
+	} else if item.Stock != nil || item.Delta != nil {
+		// stock product yang punya variant dihitung dari stock variantnya
+		var hasVariants bool
+		if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM productVariants WHERE productId = $1)`, item.ProductId).Scan(&hasVariants); err != nil {
+			log.Println("error when checking variants in batch update", err)
+			return result, failed
+		}
+
+		if hasVariants {
+			return result, fmt.Errorf("Product has variants, update the variant stock instead")
+		}
+	}
+
+	if item.Stock != nil || item.Delta != nil {
+		delta := 0
+		if item.Delta != nil {
+			delta = *item.Delta
+		} else {
+			delta = *item.Stock - currentStock
+		}
+
+		stockAfter := currentStock
+		if delta != 0 {
+			stockAfter, err = applyStockDelta(tx, item.ProductId, item.VariantId, delta)
+			switch {
+			case err == sql.ErrNoRows:
+				return result, ErrInsufficientStock
+			case err != nil:
+				log.Println("error when updating stock in batch update", err)
+				return result, failed
+			}
+
+			reason := item.Reason
+			if reason == "" {
+				reason = "batch update"
+			}
+
+			if err := insertStockMovement(tx, &entities.StockMovement{
+				ProductId:  item.ProductId,
+				VariantId:  item.VariantId,
+				Type:       "adjustment",
+				Delta:      delta,
+				StockAfter: stockAfter,
+				Reason:     reason,
+				ActorId:    sellerId,
+			}); err != nil {
+				log.Println("error when recording stock movement in batch update", err)
+				return result, failed
+			}
+		}
+
+		result.Stock = &stockAfter
+	}
+
+	if item.Price != nil {
+		query := `UPDATE products SET price = $1, updatedAt = NOW() WHERE id = $2`
+		id := item.ProductId
+		if item.VariantId != "" {
+			query = `UPDATE productVariants SET price = $1, updatedAt = NOW() WHERE id = $2`
+			id = item.VariantId
+		}
+
+		if _, err := tx.Exec(query, *item.Price, id); err != nil {
+			log.Println("error when updating price in batch update", err)
+			return result, failed
+		}
+
+		currentPrice = *item.Price
+	}
+
+	result.Price = &currentPrice
+
+	return result, nil
+}
@@ -0,0 +1,147 @@
+package datastore
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+// langkah batch palsu, item dengan productId "gagal" selalu error
+type batchStepsRecorder struct {
+	applied      []string
+	rolledBack   []int
+	savepointErr error
+	rollbackErr  error
+}
+
+func (r *batchStepsRecorder) steps() productBatchSteps {
+
+	return productBatchSteps{
+		savepoint: func(i int) error {
+			return r.savepointErr
+		},
+		rollback: func(i int) error {
+			r.rolledBack = append(r.rolledBack, i)
+			return r.rollbackErr
+		},
+		apply: func(item *entities.ProductBatchItem) (entities.ProductBatchResult, error) {
+			r.applied = append(r.applied, item.ProductId)
+
+			stock := 5
+			result := entities.ProductBatchResult{Stock: &stock}
+			if item.ProductId == "gagal" {
+				return result, fmt.Errorf("Product not found")
+			}
+
+			return result, nil
+		},
+	}
+}
+
+func TestRunProductBatch(t *testing.T) {
+
+	items := func(ids ...string) []entities.ProductBatchItem {
+		items := make([]entities.ProductBatchItem, len(ids))
+		for i, id := range ids {
+			items[i] = entities.ProductBatchItem{ProductId: id}
+		}
+		return items
+	}
+
+	t.Run("Atomic should apply all items when nothing fails", func(t *testing.T) {
+		recorder := &batchStepsRecorder{}
+
+		results, err := runProductBatch(items("kaos", "celana"), true, recorder.steps())
+		if err != nil {
+			t.Fatalf("Expected no error, but got=%v", err)
+		}
+
+		for i, result := range results {
+			if !result.Success || result.Index != i || result.Stock == nil {
+				t.Errorf("Unexpected result at %d, got=%+v", i, result)
+			}
+		}
+	})
+
+	t.Run("Atomic should stop at first failed item and mark all items failed", func(t *testing.T) {
+		recorder := &batchStepsRecorder{}
+
+		results, err := runProductBatch(items("kaos", "gagal", "celana"), true, recorder.steps())
+		if err != ErrProductBatchFailed {
+			t.Fatalf("Expected ErrProductBatchFailed, but got=%v", err)
+		}
+
+		if len(recorder.applied) != 2 {
+			t.Errorf("Expected items after failure not applied, but got=%v", recorder.applied)
+		}
+
+		// transaksi dibatalkan seluruhnya, bukan per savepoint
+		if len(recorder.rolledBack) != 0 {
+			t.Errorf("Expected no savepoint rollback, but got=%v", recorder.rolledBack)
+		}
+
+		expectedErrors := []string{"Not applied, another item in the batch failed", "Product not found", "Not applied, another item in the batch failed"}
+		for i, result := range results {
+			if result.Success || result.Index != i || result.Stock != nil || result.Error != expectedErrors[i] {
+				t.Errorf("Unexpected result at %d, got=%+v", i, result)
+			}
+		}
+
+		if results[2].ProductId != "celana" {
+			t.Errorf("Expected productId of unapplied item to be set, but got=%+v", results[2])
+		}
+	})
+
+	t.Run("Best effort should roll back only failed item", func(t *testing.T) {
+		recorder := &batchStepsRecorder{}
+
+		results, err := runProductBatch(items("gagal", "kaos", "gagal", "celana"), false, recorder.steps())
+		if err != nil {
+			t.Fatalf("Expected no error, but got=%v", err)
+		}
+
+		if len(recorder.applied) != 4 {
+			t.Errorf("Expected all items applied, but got=%v", recorder.applied)
+		}
+
+		if len(recorder.rolledBack) != 2 || recorder.rolledBack[0] != 0 || recorder.rolledBack[1] != 2 {
+			t.Errorf("Expected rollback of item 0 and 2, but got=%v", recorder.rolledBack)
+		}
+
+		expectedSuccess := []bool{false, true, false, true}
+		for i, result := range results {
+			if result.Success != expectedSuccess[i] || result.Index != i {
+				t.Errorf("Unexpected result at %d, got=%+v", i, result)
+			}
+
+			if !result.Success && (result.Error == "" || result.Stock != nil) {
+				t.Errorf("Expected failed item %d to have error and no stock, but got=%+v", i, result)
+			}
+		}
+	})
+
+	t.Run("Should stop batch when savepoint fails", func(t *testing.T) {
+		recorder := &batchStepsRecorder{savepointErr: fmt.Errorf("connection lost")}
+
+		if _, err := runProductBatch(items("kaos"), false, recorder.steps()); err != recorder.savepointErr {
+			t.Errorf("Expected savepoint error, but got=%v", err)
+		}
+
+		if len(recorder.applied) != 0 {
+			t.Errorf("Expected no item applied, but got=%v", recorder.applied)
+		}
+	})
+
+	t.Run("Should stop batch when rollback of failed item fails", func(t *testing.T) {
+		recorder := &batchStepsRecorder{rollbackErr: fmt.Errorf("connection lost")}
+
+		if _, err := runProductBatch(items("gagal", "kaos"), false, recorder.steps()); err != recorder.rollbackErr {
+			t.Errorf("Expected rollback error, but got=%v", err)
+		}
+
+		if len(recorder.applied) != 1 {
+			t.Errorf("Expected batch to stop after failed rollback, but got=%v", recorder.applied)
+		}
+	})
+}
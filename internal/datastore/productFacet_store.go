@@ -0,0 +1,136 @@
+package datastore
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+)
+
+const MAXFACETVALUES = 20
+
+// batas atas tiap bucket harga, bucket terakhir = diatas batas terakhir
+var PriceFacetBuckets = []float64{50000, 100000, 500000, 1000000}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+type ProductFacets struct {
+	Condition   []FacetCount `json:"condition"`
+	Tags        []FacetCount `json:"tags"`
+	PriceRanges []FacetCount `json:"priceRanges"`
+	Sellers     []FacetCount `json:"sellers"`
+	Stock       []FacetCount `json:"stock"`
+}
+
+// hitung facet dengan filter yang sama seperti ListProducts.
+// filter milik facet itu sendiri dilepas, jadi user tetap bisa lihat jumlah untuk pilihan lain
+// (contoh: saat filter condition=new, facet condition tetap menampilkan jumlah second)
+func (s *Storage) ListProductFacets(q types.ListQueryValid, userId string) (*ProductFacets, error) {
+
+	facets := &ProductFacets{}
+	var err error
+
+	conditionQuery := q
+	conditionQuery.Condition = "any"
+	if facets.Condition, err = s.queryFacet(conditionQuery, userId, `
+        SELECT condition, '', COUNT(*) FROM products WHERE %s 
+        GROUP BY condition ORDER BY COUNT(*) DESC`); err != nil {
+		return nil, err
+	}
+
+	tagQuery := q
+	tagQuery.Tags = nil
+	if facets.Tags, err = s.queryFacet(tagQuery, userId, `
+        SELECT tag, '', COUNT(*) FROM products, unnest(tags) AS tag WHERE %s 
+        GROUP BY tag ORDER BY COUNT(*) DESC, tag ASC LIMIT `+strconv.Itoa(MAXFACETVALUES)); err != nil {
+		return nil, err
+	}
+
+	priceQuery := q
+	priceQuery.MinPrice = 0
+	priceQuery.MaxPrice = 0
+	if facets.PriceRanges, err = s.queryFacet(priceQuery, userId, `
+        SELECT bucket, '', COUNT(*) FROM (
+            SELECT `+generatePriceBucketCase()+` AS bucket FROM products WHERE %s
+        ) AS buckets 
+        GROUP BY bucket ORDER BY bucket ASC`); err != nil {
+		return nil, err
+	}
+
+	for i := range facets.PriceRanges {
+		_, facets.PriceRanges[i].Value, _ = strings.Cut(facets.PriceRanges[i].Value, ":")
+	}
+
+	sellerQuery := q
+	sellerQuery.UserOnly = "false"
+	sellerQuery.SellerId = ""
+	if facets.Sellers, err = s.queryFacet(sellerQuery, userId, `
+        SELECT sellerFacet.sellerId::text, users.username, sellerFacet.count FROM (
+            SELECT sellerId, COUNT(*) AS count FROM products WHERE %s 
+            GROUP BY sellerId ORDER BY COUNT(*) DESC LIMIT `+strconv.Itoa(MAXFACETVALUES)+`
+        ) AS sellerFacet 
+        JOIN users ON users.id = sellerFacet.sellerId 
+        ORDER BY sellerFacet.count DESC`); err != nil {
+		return nil, err
+	}
+
+	stockQuery := q
+	stockQuery.Stock = "include"
+	if facets.Stock, err = s.queryFacet(stockQuery, userId, `
+        SELECT CASE WHEN stock > 0 THEN 'inStock' ELSE 'outOfStock' END AS availability, '', COUNT(*) 
+        FROM products WHERE %s 
+        GROUP BY availability ORDER BY availability ASC`); err != nil {
+		return nil, err
+	}
+
+	return facets, nil
+}
+
+// query harus return 3 kolom: value, label, count. %s diganti dengan kondisi WHERE list product
+func (s *Storage) queryFacet(q types.ListQueryValid, userId, query string) ([]FacetCount, error) {
+
+	where, params := NewProductQuery(q, userId).WhereSQL()
+	counts := []FacetCount{}
+
+	rows, err := s.db.Query(fmt.Sprintf(query, where), params...)
+	if err != nil {
+		log.Println("err inside queryFacet", err)
+		return counts, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var count FacetCount
+		if err := rows.Scan(&count.Value, &count.Label, &count.Count); err != nil {
+			return []FacetCount{}, err
+		}
+
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// bucket harga dalam bentuk "min-max", bucket terakhir "min+".
+// tiap bucket diawali "index:" supaya bisa diurutkan di query, prefix ini dibuang setelah query
+func generatePriceBucketCase() string {
+
+	query := `CASE `
+	lower := 0.0
+
+	for i, upper := range PriceFacetBuckets {
+		query += fmt.Sprintf(`WHEN price < %.0f THEN '%d:%.0f-%.0f' `, upper, i, lower, upper)
+		lower = upper
+	}
+
+	query += fmt.Sprintf(`ELSE '%d:%.0f+' END`, len(PriceFacetBuckets), lower)
+
+	return query
+}
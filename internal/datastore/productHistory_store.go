@@ -0,0 +1,261 @@
+package datastore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// batas titik harga yang dikembalikan price history (yang terbaru)
+const MAXPRICEHISTORYPOINTS = 100
+
+// catat versi baru product. before nil = product baru dibuat (versi pertama).
+// product lama yang belum punya history dapat versi awal dari before dulu supaya perubahan pertama tetap punya diff,
+// update yang tidak mengubah apapun tidak dicatat
+func (s *Storage) CreateProductHistory(productId, changedBy string, before, after *entities.Product) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// lock product supaya nomor versi tidak bentrok saat update bersamaan
+	if _, err := tx.Exec(`SELECT id FROM products WHERE id = $1 FOR UPDATE`, productId); err != nil {
+		return err
+	}
+
+	var version int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM productHistory WHERE productId = $1`, productId).Scan(&version); err != nil {
+		return err
+	}
+
+	diff := map[string]entities.FieldChange{}
+
+	if before != nil {
+		if version == 0 {
+			if err := insertProductHistory(tx, productId, "", 1, newProductSnapshot(before), diff, &before.UpdatedAt); err != nil {
+				return err
+			}
+
+			version = 1
+		}
+
+		diff = diffProductSnapshot(newProductSnapshot(before), newProductSnapshot(after))
+		if len(diff) == 0 {
+			return nil
+		}
+	}
+
+	if err := insertProductHistory(tx, productId, changedBy, version+1, newProductSnapshot(after), diff, nil); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// history terbaru dulu beserta total versi
+func (s *Storage) ListProductHistory(productId string, limit, offset int) (*[]entities.ProductHistory, int, error) {
+
+	returnHistory := []entities.ProductHistory{}
+
+	var total int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM productHistory WHERE productId = $1`, productId).Scan(&total); err != nil {
+		log.Println("err when counting product history", err)
+		return &returnHistory, 0, err
+	}
+
+	rows, err := s.db.Query(`
+        SELECT
+            productHistory.id,
+            productHistory.productId,
+            productHistory.version,
+            productHistory.snapshot,
+            productHistory.diff,
+            productHistory.createdAt,
+            users.id,
+            users.name,
+            users.username
+        FROM productHistory
+        LEFT JOIN users ON productHistory.changedBy = users.id
+        WHERE productHistory.productId = $1
+        ORDER BY productHistory.version DESC
+        LIMIT $2 OFFSET $3`, productId, limit, offset)
+	if err != nil {
+		log.Println("err inside ListProductHistory", err)
+		return &returnHistory, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var history entities.ProductHistory
+		var snapshot, diff []byte
+		var userId, userName, userUsername sql.NullString
+
+		if err := rows.Scan(
+			&history.ID,
+			&history.ProductId,
+			&history.Version,
+			&snapshot,
+			&diff,
+			&history.CreatedAt,
+			&userId,
+			&userName,
+			&userUsername,
+		); err != nil {
+			return &[]entities.ProductHistory{}, 0, err
+		}
+
+		if err := json.Unmarshal(snapshot, &history.Snapshot); err != nil {
+			return &[]entities.ProductHistory{}, 0, err
+		}
+
+		if err := json.Unmarshal(diff, &history.Diff); err != nil {
+			return &[]entities.ProductHistory{}, 0, err
+		}
+
+		history.ChangedBy = entities.UserMinimal{
+			ID:       userId.String,
+			Name:     userName.String,
+			Username: userUsername.String,
+		}
+
+		returnHistory = append(returnHistory, history)
+	}
+
+	return &returnHistory, total, nil
+}
+
+// harga dari versi pertama dan setiap versi yang mengubah harga product atau harga variant, urut dari yang terlama
+func (s *Storage) ListProductPriceHistory(productId string) (*[]entities.PricePoint, error) {
+
+	points := []entities.PricePoint{}
+	rows, err := s.db.Query(`
+        SELECT price, createdAt FROM (
+            SELECT (snapshot->>'price')::numeric AS price, COALESCE(snapshot->'variantPrices', '{}') AS variantPrices, createdAt, version
+            FROM productHistory
+            WHERE productId = $1 AND (version = 1 OR diff ?| ARRAY['price', 'variantPrices'])
+            ORDER BY version DESC
+            LIMIT $2
+        ) AS pricePoints
+        ORDER BY version ASC`, productId, MAXPRICEHISTORYPOINTS)
+	if err != nil {
+		log.Println("err inside ListProductPriceHistory", err)
+		return &points, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var point entities.PricePoint
+		var variantPrices []byte
+		if err := rows.Scan(&point.Price, &variantPrices, &point.ChangedAt); err != nil {
+			return &[]entities.PricePoint{}, err
+		}
+
+		if err := json.Unmarshal(variantPrices, &point.VariantPrices); err != nil {
+			return &[]entities.PricePoint{}, err
+		}
+
+		points = append(points, point)
+	}
+
+	return &points, nil
+}
+
+func insertProductHistory(tx *sql.Tx, productId, changedBy string, version int, snapshot entities.ProductSnapshot, diff map[string]entities.FieldChange, createdAt *time.Time) error {
+
+	snapshotJson, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	diffJson, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO productHistory (
+            id,
+            productId,
+            version,
+            changedBy,
+            snapshot,
+            diff,
+            createdAt
+        ) VALUES ($1,$2,$3,$4,$5,$6,COALESCE($7::timestamp, LOCALTIMESTAMP))`,
+		uuid.NewString(),
+		productId,
+		version,
+		sql.NullString{String: changedBy, Valid: changedBy != ""},
+		snapshotJson,
+		diffJson,
+		createdAt,
+	)
+
+	return err
+}
+
+func newProductSnapshot(p *entities.Product) entities.ProductSnapshot {
+
+	// tags null dan kosong dianggap sama
+	tags := p.Tags
+	if tags == nil {
+		tags = pq.StringArray{}
+	}
+
+	variantPrices := map[string]float64{}
+	for _, variant := range p.Variants {
+		if variant.Price != nil {
+			variantPrices[variant.ID] = *variant.Price
+		}
+	}
+
+	return entities.ProductSnapshot{
+		Name:           p.Name,
+		Price:          p.Price,
+		ImageUrl:       p.ImageUrl,
+		Stock:          p.Stock,
+		Condition:      p.Condition,
+		Tags:           tags,
+		IsPurchaseable: p.IsPurchaseable,
+		Descriptions:   p.Descriptions,
+		CategoryId:     p.CategoryId,
+		Status:         p.Status,
+		Sku:            p.Sku,
+		VariantPrices:  variantPrices,
+	}
+}
+
+// bandingkan tiap field snapshot, key diff pakai nama field di json
+func diffProductSnapshot(before, after entities.ProductSnapshot) map[string]entities.FieldChange {
+
+	diff := map[string]entities.FieldChange{}
+	beforeValue := reflect.ValueOf(before)
+	afterValue := reflect.ValueOf(after)
+	snapshotType := beforeValue.Type()
+
+	for i := 0; i < snapshotType.NumField(); i++ {
+		from := beforeValue.Field(i).Interface()
+		to := afterValue.Field(i).Interface()
+
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+
+		name := strings.Split(snapshotType.Field(i).Tag.Get("json"), ",")[0]
+		diff[name] = entities.FieldChange{From: from, To: to}
+	}
+
+	return diff
+}
@@ -0,0 +1,69 @@
+package datastore
+
+import (
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/lib/pq"
+)
+
+func TestDiffProductSnapshot(t *testing.T) {
+
+	before := &entities.Product{Name: "Kaos", Price: 50000, Stock: 10, Tags: pq.StringArray{"baju"}}
+
+	t.Run("Should be empty when nothing changed", func(t *testing.T) {
+		after := *before
+		after.RatingCount = 3
+
+		if diff := diffProductSnapshot(newProductSnapshot(before), newProductSnapshot(&after)); len(diff) != 0 {
+			t.Errorf("Expected empty diff, but got=%v", diff)
+		}
+	})
+
+	t.Run("Should treat nil and empty tags as equal", func(t *testing.T) {
+		noTags := &entities.Product{Name: "Kaos"}
+		emptyTags := &entities.Product{Name: "Kaos", Tags: pq.StringArray{}}
+
+		if diff := diffProductSnapshot(newProductSnapshot(noTags), newProductSnapshot(emptyTags)); len(diff) != 0 {
+			t.Errorf("Expected empty diff, but got=%v", diff)
+		}
+	})
+
+	t.Run("Should record changed fields with json names", func(t *testing.T) {
+		after := *before
+		after.Price = 45000
+		after.Tags = pq.StringArray{"baju", "kaos"}
+
+		diff := diffProductSnapshot(newProductSnapshot(before), newProductSnapshot(&after))
+		if len(diff) != 2 {
+			t.Fatalf("Expected 2 changed fields, but got=%v", diff)
+		}
+
+		if diff["price"].From != 50000.0 || diff["price"].To != 45000.0 {
+			t.Errorf("Unexpected price change, got=%v", diff["price"])
+		}
+
+		if _, ok := diff["tags"]; !ok {
+			t.Errorf("Expected tags in diff, but got=%v", diff)
+		}
+	})
+
+	t.Run("Should record variant price overrides", func(t *testing.T) {
+		oldPrice, newPrice := 55000.0, 60000.0
+		withVariant := *before
+		withVariant.Variants = []entities.ProductVariant{{ID: "variant-xl", Price: &oldPrice}, {ID: "variant-m"}}
+
+		after := withVariant
+		after.Variants = []entities.ProductVariant{{ID: "variant-xl", Price: &newPrice}, {ID: "variant-m"}}
+
+		diff := diffProductSnapshot(newProductSnapshot(&withVariant), newProductSnapshot(&after))
+		if len(diff) != 1 {
+			t.Fatalf("Expected only variantPrices changed, but got=%v", diff)
+		}
+
+		to, ok := diff["variantPrices"].To.(map[string]float64)
+		if !ok || len(to) != 1 || to["variant-xl"] != newPrice {
+			t.Errorf("Unexpected variant price change, got=%v", diff["variantPrices"])
+		}
+	})
+}
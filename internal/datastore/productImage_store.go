@@ -0,0 +1,201 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+var ErrProductImageLimit = fmt.Errorf("Product image limit reached")
+
+// semua gambar satu upload disimpan dalam satu transaksi, gagal satu gagal semua.
+// row product di-lock dulu supaya dua upload bersamaan tidak bisa melewati maxImages
+func (s *Storage) CreateProductImages(productId string, imgs []entities.ProductImage, maxImages int) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT 1 FROM products WHERE id = $1 FOR UPDATE`, productId); err != nil {
+		return err
+	}
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM productImages WHERE productId = $1`, productId).Scan(&count); err != nil {
+		return err
+	}
+
+	if count+len(imgs) > maxImages {
+		return ErrProductImageLimit
+	}
+
+	// position otomatis di paling belakang
+	query := `
+    INSERT INTO productImages (
+        id,
+        productId,
+        position,
+        contentType,
+        storageKey
+    ) VALUES (
+        $1,
+        $2,
+        (SELECT COALESCE(MAX(position) + 1, 0) FROM productImages WHERE productId = $2),
+        $3,
+        $4
+    )`
+
+	for _, img := range imgs {
+		if _, err := tx.Exec(query, img.ID, productId, img.ContentType, img.StorageKey); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) GetProductImage(id string) (*entities.ProductImage, error) {
+
+	var img entities.ProductImage
+	query := `
+    SELECT 
+        id,
+        productId,
+        position,
+        contentType,
+        storageKey,
+        createdAt,
+        updatedAt,
+        deletedAt
+    FROM productImages WHERE id = $1`
+
+	err := s.db.QueryRow(query, id).Scan(
+		&img.ID,
+		&img.ProductId,
+		&img.Position,
+		&img.ContentType,
+		&img.StorageKey,
+		&img.CreatedAt,
+		&img.UpdatedAt,
+		&img.DeletedAt,
+	)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.ProductImage{}, fmt.Errorf("Image did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.ProductImage{}, fmt.Errorf("Something went wrong")
+	default:
+		return &img, nil
+	}
+}
+
+func (s *Storage) ListProductImages(productId string) (*[]entities.ProductImage, error) {
+
+	returnImages := []entities.ProductImage{}
+	query := `
+    SELECT 
+        id,
+        productId,
+        position,
+        contentType,
+        storageKey,
+        createdAt,
+        updatedAt,
+        deletedAt
+    FROM productImages 
+    WHERE productId = $1
+    ORDER BY position ASC, createdAt ASC`
+
+	rows, err := s.db.Query(query, productId)
+	if err != nil {
+		log.Println("err inside ListProductImages", err)
+		return &returnImages, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var img entities.ProductImage
+		if err := rows.Scan(
+			&img.ID,
+			&img.ProductId,
+			&img.Position,
+			&img.ContentType,
+			&img.StorageKey,
+			&img.CreatedAt,
+			&img.UpdatedAt,
+			&img.DeletedAt,
+		); err != nil {
+			return &[]entities.ProductImage{}, err
+		}
+
+		returnImages = append(returnImages, img)
+	}
+
+	return &returnImages, nil
+}
+
+func (s *Storage) DeleteProductImage(id string) error {
+
+	_, err := s.db.Exec(`DELETE FROM productImages WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// set ulang posisi gambar sesuai urutan imageIds, imageIds harus berisi semua gambar milik product
+func (s *Storage) ReorderProductImages(productId string, imageIds []string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for position, imageId := range imageIds {
+		res, err := tx.Exec(`
+            UPDATE productImages 
+            SET position = $1,
+                updatedAt = NOW()
+            WHERE id = $2 AND productId = $3`, position, imageId, productId)
+		if err != nil {
+			return err
+		}
+
+		rowAffect, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowAffect < 1 {
+			return fmt.Errorf("Image %s did not exists", imageId)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// imageUrl di tabel products selalu diisi url gambar dengan posisi paling depan
+func (s *Storage) UpdateProductImageUrl(productId, imageUrl string) error {
+
+	_, err := s.db.Exec(`
+        UPDATE products 
+        SET imageUrl = $1,
+            updatedAt = NOW()
+        WHERE id = $2`, imageUrl, productId)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
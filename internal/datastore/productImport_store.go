@@ -0,0 +1,213 @@
+package datastore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+// job yang processing lebih lama dari ini dianggap macet (server mati saat proses) dan diambil ulang
+const PRODUCTIMPORTSTALE = 30 * time.Minute
+
+// product seller dengan sku ini, nil kalau tidak ada
+func (s *Storage) GetProductBySku(sellerId, sku string) (*entities.Product, error) {
+
+	var id string
+	err := s.db.QueryRow(`
+        SELECT id FROM products
+        WHERE sellerId = $1 AND sku = $2 AND sku <> '' AND deletedAt IS NULL`, sellerId, sku).Scan(&id)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	return s.GetProductById(id)
+}
+
+// semua product seller (semua status) untuk export katalog
+func (s *Storage) ListSellerProducts(sellerId string) (*[]entities.Product, error) {
+
+	returnProducts := []entities.Product{}
+	rows, err := s.db.Query(`
+        SELECT `+productListColumns+`
+        FROM products
+        WHERE sellerId = $1 AND deletedAt IS NULL
+        ORDER BY createdAt ASC, id ASC`, sellerId)
+	if err != nil {
+		log.Println("err inside ListSellerProducts", err)
+		return &returnProducts, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var product entities.Product
+		var categoryId sql.NullString
+		var publishAt, archiveAt sql.NullTime
+
+		if err := rows.Scan(
+			&product.ID,
+			&product.Name,
+			&product.Price,
+			&product.ImageUrl,
+			&product.Condition,
+			&product.Tags,
+			&product.IsPurchaseable,
+			&product.SellerId,
+			&product.Stock,
+			&product.CreatedAt,
+			&product.UpdatedAt,
+			&product.DeletedAt,
+			&product.Descriptions,
+			&categoryId,
+			&product.RatingAverage,
+			&product.RatingCount,
+			&product.Status,
+			&publishAt,
+			&archiveAt,
+			&product.Sku,
+		); err != nil {
+			return &[]entities.Product{}, err
+		}
+
+		product.CategoryId = categoryId.String
+		product.PublishAt, product.ArchiveAt = nullTimePtr(publishAt), nullTimePtr(archiveAt)
+		returnProducts = append(returnProducts, product)
+	}
+
+	return &returnProducts, nil
+}
+
+func (s *Storage) CreateProductImportJob(id string, job *entities.ProductImportJob) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO productImportJobs (id, sellerId, format, payload)
+        VALUES ($1, $2, $3, $4)`, id, job.SellerId, job.Format, job.Payload)
+
+	return err
+}
+
+// status job tanpa isi file
+func (s *Storage) GetProductImportJob(id string) (*entities.ProductImportJob, error) {
+
+	row := s.db.QueryRow(`
+        SELECT id, sellerId, format, status, totalRows, createdCount, updatedCount, failedCount, errors, '', createdAt, startedAt, finishedAt
+        FROM productImportJobs
+        WHERE id = $1`, id)
+
+	job, err := scanProductImportJob(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.ProductImportJob{}, fmt.Errorf("Import job did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.ProductImportJob{}, fmt.Errorf("Something went wrong")
+	default:
+		return job, nil
+	}
+}
+
+// ambil satu job pending (atau yang macet) paling lama beserta isi file dan tandai processing,
+// nil kalau tidak ada job. SKIP LOCKED supaya aman dijalankan lebih dari satu instance
+func (s *Storage) ClaimProductImportJob() (*entities.ProductImportJob, error) {
+
+	row := s.db.QueryRow(`
+        UPDATE productImportJobs
+        SET status = 'processing',
+            startedAt = NOW()
+        WHERE id = (
+            SELECT id FROM productImportJobs
+            WHERE status = 'pending'
+                OR (status = 'processing' AND startedAt < NOW() - make_interval(secs => $1))
+            ORDER BY createdAt
+            LIMIT 1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, sellerId, format, status, totalRows, createdCount, updatedCount, failedCount, errors, payload, createdAt, startedAt, finishedAt`,
+		PRODUCTIMPORTSTALE.Seconds())
+
+	job, err := scanProductImportJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+// simpan hasil import, isi file dihapus karena sudah tidak dibutuhkan
+func (s *Storage) FinishProductImportJob(job *entities.ProductImportJob) error {
+
+	errors := job.Errors
+	if errors == nil {
+		errors = []entities.ProductImportError{}
+	}
+
+	errorsJson, err := json.Marshal(errors)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.db.Exec(`
+        UPDATE productImportJobs
+        SET status = $1,
+            totalRows = $2,
+            createdCount = $3,
+            updatedCount = $4,
+            failedCount = $5,
+            errors = $6,
+            payload = '',
+            finishedAt = NOW()
+        WHERE id = $7`,
+		job.Status,
+		job.TotalRows,
+		job.CreatedCount,
+		job.UpdatedCount,
+		job.FailedCount,
+		errorsJson,
+		job.ID,
+	)
+
+	return err
+}
+
+func scanProductImportJob(row rowScanner) (*entities.ProductImportJob, error) {
+
+	var job entities.ProductImportJob
+	var errorsJson []byte
+	var startedAt, finishedAt sql.NullTime
+
+	err := row.Scan(
+		&job.ID,
+		&job.SellerId,
+		&job.Format,
+		&job.Status,
+		&job.TotalRows,
+		&job.CreatedCount,
+		&job.UpdatedCount,
+		&job.FailedCount,
+		&errorsJson,
+		&job.Payload,
+		&job.CreatedAt,
+		&startedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(errorsJson, &job.Errors); err != nil {
+		return nil, err
+	}
+
+	job.StartedAt, job.FinishedAt = nullTimePtr(startedAt), nullTimePtr(finishedAt)
+
+	return &job, nil
+}
@@ -0,0 +1,170 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+const productQuestionColumns = `
+        productQuestions.id,
+        productQuestions.productId,
+        productQuestions.askerId,
+        productQuestions.question,
+        productQuestions.answer,
+        productQuestions.answeredAt,
+        productQuestions.isHidden,
+        productQuestions.createdAt,
+        productQuestions.updatedAt,
+        productQuestions.deletedAt,
+        askers.id,
+        askers.name,
+        askers.username`
+
+func (s *Storage) CreateProductQuestion(id string, q *entities.ProductQuestion) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO productQuestions (
+            id,
+            productId,
+            askerId,
+            question
+        ) VALUES ($1,$2,$3,$4)`,
+		id,
+		q.ProductId,
+		q.AskerId,
+		q.Question,
+	)
+
+	return err
+}
+
+func (s *Storage) GetProductQuestion(id string) (*entities.ProductQuestion, error) {
+
+	row := s.db.QueryRow(`
+        SELECT `+productQuestionColumns+`
+        FROM productQuestions
+        LEFT JOIN users AS askers ON productQuestions.askerId = askers.id
+        WHERE productQuestions.id = $1`, id)
+	question, err := scanProductQuestion(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.ProductQuestion{}, fmt.Errorf("Question did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.ProductQuestion{}, fmt.Errorf("Something went wrong")
+	default:
+		return question, nil
+	}
+}
+
+// list pertanyaan terbaru beserta total, pertanyaan tersembunyi hanya ikut kalau includeHidden
+func (s *Storage) ListProductQuestions(productId string, limit, offset int, includeHidden bool) (*[]entities.ProductQuestion, int, error) {
+
+	returnQuestions := []entities.ProductQuestion{}
+
+	var total int
+	err := s.db.QueryRow(`
+        SELECT COUNT(*) FROM productQuestions
+        WHERE productId = $1 AND ($2 OR isHidden = FALSE)`, productId, includeHidden).Scan(&total)
+	if err != nil {
+		log.Println("err when counting product questions", err)
+		return &returnQuestions, 0, err
+	}
+
+	rows, err := s.db.Query(`
+        SELECT `+productQuestionColumns+`
+        FROM productQuestions
+        LEFT JOIN users AS askers ON productQuestions.askerId = askers.id
+        WHERE productQuestions.productId = $1 AND ($2 OR productQuestions.isHidden = FALSE)
+        ORDER BY productQuestions.createdAt DESC, productQuestions.id DESC
+        LIMIT $3 OFFSET $4`, productId, includeHidden, limit, offset)
+	if err != nil {
+		log.Println("err inside ListProductQuestions", err)
+		return &returnQuestions, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		question, err := scanProductQuestion(rows)
+		if err != nil {
+			return &[]entities.ProductQuestion{}, 0, err
+		}
+
+		returnQuestions = append(returnQuestions, *question)
+	}
+
+	return &returnQuestions, total, nil
+}
+
+func (s *Storage) AnswerProductQuestion(id, answer string) error {
+
+	_, err := s.db.Exec(`
+        UPDATE productQuestions
+        SET answer = $1,
+            answeredAt = NOW(),
+            updatedAt = NOW()
+        WHERE id = $2`, answer, id)
+
+	return err
+}
+
+func (s *Storage) SetProductQuestionHidden(id string, hidden bool) error {
+
+	_, err := s.db.Exec(`
+        UPDATE productQuestions
+        SET isHidden = $1,
+            updatedAt = NOW()
+        WHERE id = $2`, hidden, id)
+
+	return err
+}
+
+func (s *Storage) DeleteProductQuestion(id string) error {
+
+	_, err := s.db.Exec(`DELETE FROM productQuestions WHERE id = $1`, id)
+
+	return err
+}
+
+func scanProductQuestion(row rowScanner) (*entities.ProductQuestion, error) {
+
+	var question entities.ProductQuestion
+	var answeredAt sql.NullTime
+	var askerId, askerName, askerUsername sql.NullString
+
+	err := row.Scan(
+		&question.ID,
+		&question.ProductId,
+		&question.AskerId,
+		&question.Question,
+		&question.Answer,
+		&answeredAt,
+		&question.IsHidden,
+		&question.CreatedAt,
+		&question.UpdatedAt,
+		&question.DeletedAt,
+		&askerId,
+		&askerName,
+		&askerUsername,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if answeredAt.Valid {
+		question.AnsweredAt = &answeredAt.Time
+	}
+
+	question.Asker = entities.UserMinimal{
+		ID:       askerId.String,
+		Name:     askerName.String,
+		Username: askerUsername.String,
+	}
+
+	return &question, nil
+}
@@ -0,0 +1,76 @@
+package datastore
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+func (s *Storage) UpdateProductStatus(id string, p *entities.Product) error {
+
+	_, err := s.db.Exec(`
+        UPDATE products
+        SET status = $1,
+            publishAt = $2,
+            archiveAt = $3,
+            updatedAt = NOW()
+        WHERE id = $4`, p.Status, p.PublishAt, p.ArchiveAt, id)
+
+	return err
+}
+
+// publish product scheduled yang publishAt nya sudah lewat dan arsip product published yang archiveAt nya sudah lewat,
+// return jumlah product yang dipublish dan diarsip
+func (s *Storage) ApplyProductSchedule() (int64, int64, error) {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+        UPDATE products
+        SET status = 'published',
+            updatedAt = NOW()
+        WHERE status = 'scheduled' AND publishAt <= NOW() AND deletedAt IS NULL`)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	published, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// product scheduled yang publishAt dan archiveAt sudah lewat bersamaan ikut diarsip disini
+	res, err = tx.Exec(`
+        UPDATE products
+        SET status = 'archived',
+            updatedAt = NOW()
+        WHERE status = 'published' AND archiveAt <= NOW() AND deletedAt IS NULL`)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	archived, err := res.RowsAffected()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+
+	return published, archived, nil
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+
+	return &t.Time
+}
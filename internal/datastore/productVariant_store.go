@@ -0,0 +1,288 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+const productVariantColumns = `
+        id,
+        productId,
+        sku,
+        name,
+        attributes,
+        price,
+        stock,
+        imageIds,
+        createdAt,
+        updatedAt,
+        deletedAt`
+
+func (s *Storage) CreateProductVariant(id, productId string, v *entities.ProductVariant) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        INSERT INTO productVariants (
+            id,
+            productId,
+            sku,
+            name,
+            attributes,
+            price,
+            stock,
+            imageIds
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		id,
+		productId,
+		v.Sku,
+		v.Name,
+		v.Attributes,
+		v.Price,
+		v.Stock,
+		v.ImageIds,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := syncProductStockFromVariants(tx, productId); err != nil {
+		return err
+	}
+
+	if v.Stock != 0 {
+		if err := insertStockMovement(tx, &entities.StockMovement{
+			ProductId:  productId,
+			VariantId:  id,
+			Type:       "restock",
+			Delta:      v.Stock,
+			StockAfter: v.Stock,
+			Reason:     "initial stock",
+			ActorId:    productSellerId(tx, productId),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) GetProductVariant(id string) (*entities.ProductVariant, error) {
+
+	row := s.db.QueryRow(`SELECT `+productVariantColumns+` FROM productVariants WHERE id = $1`, id)
+	variant, err := scanProductVariant(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.ProductVariant{}, fmt.Errorf("Variant did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.ProductVariant{}, fmt.Errorf("Something went wrong")
+	default:
+		return variant, nil
+	}
+}
+
+func (s *Storage) ListProductVariants(productId string) (*[]entities.ProductVariant, error) {
+
+	returnVariants := []entities.ProductVariant{}
+	rows, err := s.db.Query(`
+        SELECT `+productVariantColumns+` 
+        FROM productVariants 
+        WHERE productId = $1 
+        ORDER BY createdAt ASC`, productId)
+	if err != nil {
+		log.Println("err inside ListProductVariants", err)
+		return &returnVariants, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		variant, err := scanProductVariant(rows)
+		if err != nil {
+			return &[]entities.ProductVariant{}, err
+		}
+
+		returnVariants = append(returnVariants, *variant)
+	}
+
+	return &returnVariants, nil
+}
+
+func (s *Storage) UpdateProductVariant(id string, v *entities.ProductVariant) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var productId string
+	var previousStock int
+	err = tx.QueryRow(`SELECT productId, stock FROM productVariants WHERE id = $1 FOR UPDATE`, id).Scan(&productId, &previousStock)
+	if err != nil {
+		return err
+	}
+
+	// stock variant tidak boleh kurang dari stock yang sudah dialokasikan ke warehouse
+	var allocated int
+	if err := tx.QueryRow(`SELECT COALESCE(SUM(stock), 0) FROM warehouseStocks WHERE variantId = $1`, id).Scan(&allocated); err != nil {
+		return err
+	}
+
+	if v.Stock < allocated {
+		return ErrInsufficientStock
+	}
+
+	err = tx.QueryRow(`
+        UPDATE productVariants 
+        SET sku = $1,
+            name = $2,
+            attributes = $3,
+            price = $4,
+            stock = $5,
+            imageIds = $6,
+            updatedAt = NOW()
+        WHERE id = $7
+        RETURNING productId`,
+		v.Sku,
+		v.Name,
+		v.Attributes,
+		v.Price,
+		v.Stock,
+		v.ImageIds,
+		id,
+	).Scan(&productId)
+	if err != nil {
+		return err
+	}
+
+	if err := syncProductStockFromVariants(tx, productId); err != nil {
+		return err
+	}
+
+	if v.Stock != previousStock {
+		if err := insertStockMovement(tx, &entities.StockMovement{
+			ProductId:  productId,
+			VariantId:  id,
+			Type:       "adjustment",
+			Delta:      v.Stock - previousStock,
+			StockAfter: v.Stock,
+			Reason:     "variant updated",
+			ActorId:    productSellerId(tx, productId),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) DeleteProductVariant(id string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var productId string
+	var stock int
+	err = tx.QueryRow(`DELETE FROM productVariants WHERE id = $1 RETURNING productId, stock`, id).Scan(&productId, &stock)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM warehouseStocks WHERE variantId = $1`, id); err != nil {
+		return err
+	}
+
+	if err := syncProductStockFromVariants(tx, productId); err != nil {
+		return err
+	}
+
+	if stock != 0 {
+		if err := insertStockMovement(tx, &entities.StockMovement{
+			ProductId:  productId,
+			VariantId:  id,
+			Type:       "adjustment",
+			Delta:      -stock,
+			StockAfter: 0,
+			Reason:     "variant deleted",
+			ActorId:    productSellerId(tx, productId),
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// stock product yang punya variant selalu = total stock semua variantnya,
+// jadi filter stock di ListProducts otomatis ikut ketersediaan variant.
+// variant terakhir dihapus -> stock product jadi 0, stock lama sebelum ada variant tidak dikembalikan
+func syncProductStockFromVariants(tx *sql.Tx, productId string) error {
+
+	_, err := tx.Exec(`
+        UPDATE products 
+        SET stock = (SELECT COALESCE(SUM(stock), 0) FROM productVariants WHERE productId = $1),
+            updatedAt = NOW()
+        WHERE id = $1`, productId)
+
+	return err
+}
+
+// seller product sebagai actor perubahan stock dari endpoint variant
+func productSellerId(tx *sql.Tx, productId string) string {
+
+	var sellerId string
+	if err := tx.QueryRow(`SELECT sellerId FROM products WHERE id = $1`, productId).Scan(&sellerId); err != nil {
+		log.Println("error when getting product seller for stock movement", err)
+	}
+
+	return sellerId
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanProductVariant(row rowScanner) (*entities.ProductVariant, error) {
+
+	var variant entities.ProductVariant
+	var price sql.NullFloat64
+
+	err := row.Scan(
+		&variant.ID,
+		&variant.ProductId,
+		&variant.Sku,
+		&variant.Name,
+		&variant.Attributes,
+		&price,
+		&variant.Stock,
+		&variant.ImageIds,
+		&variant.CreatedAt,
+		&variant.UpdatedAt,
+		&variant.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if price.Valid {
+		variant.Price = &price.Float64
+	}
+
+	return &variant, nil
+}
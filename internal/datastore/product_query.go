@@ -0,0 +1,330 @@
+package datastore
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/lib/pq"
+)
+
+const productListColumns = `
+        id,
+        name,
+        price,
+        imageUrl,
+        condition,
+        tags,
+        isPurchaseable,
+        sellerId,
+        stock,
+        createdAt,
+        updatedAt,
+        deletedAt,
+        descriptions,
+        categoryId,
+        ratingAverage,
+        ratingCount,
+        status,
+        publishAt,
+        archiveAt,
+        sku`
+
+// ekspresi dan tipe tiap field sort yang diizinkan, field selain ini tidak akan pernah masuk ke query
+var productSortFields = map[string]sortKey{
+	"createdAt": {Expr: `createdAt`, Cast: `timestamp`},
+	"name":      {Expr: `name`, Cast: `text`},
+	"price":     {Expr: `price`, Cast: `numeric`},
+	"stock":     {Expr: `stock`, Cast: `integer`},
+	"rating":    {Expr: `ratingAverage`, Cast: `numeric`},
+	"relevance": {Cast: `real`}, // Expr diisi saat ada search
+}
+
+// builder kondisi WHERE, placeholder ($1, $2, ...) dibuat otomatis sesuai urutan param
+type whereBuilder struct {
+	conditions []string
+	params     []interface{}
+}
+
+// tambah param dan return placeholder-nya
+func (b *whereBuilder) arg(value interface{}) string {
+	b.params = append(b.params, value)
+
+	return `$` + strconv.Itoa(len(b.params))
+}
+
+func (b *whereBuilder) where(condition string) {
+	b.conditions = append(b.conditions, `(`+condition+`)`)
+}
+
+func (b *whereBuilder) clause() string {
+	if len(b.conditions) == 0 {
+		return `TRUE`
+	}
+
+	return strings.Join(b.conditions, ` AND `)
+}
+
+// query builder untuk list product. Semua filter bisa dipanggil terpisah (composable),
+// NewProductQuery menerapkan semua filter dari ListQueryValid sekaligus
+type ProductQuery struct {
+	where     whereBuilder
+	sorts     []types.SortField
+	searchArg string
+}
+
+func NewProductQuery(q types.ListQueryValid, userId string) *ProductQuery {
+
+	p := (&ProductQuery{}).ExcludeDeleted()
+
+	// seller boleh lihat semua status product miliknya, selain itu hanya yang published.
+	// userOnly menang atas sellerId, jadi sellerId diabaikan kalau userOnly
+	if q.UserOnly == "true" && userId != "" {
+		p.FilterSeller(userId).FilterStatus(q.Status)
+	} else {
+		p.FilterStatus("published").FilterSeller(q.SellerId)
+	}
+
+	p.FilterStorefront(q.Storefront).
+		FilterCondition(q.Condition).
+		FilterTags(q.Tags, q.TagMatch == "all").
+		FilterStock(q.Stock).
+		FilterPrice(q.MinPrice, q.MaxPrice).
+		FilterPurchaseable(q.IsPurchaseable).
+		FilterMinRating(q.MinRating).
+		FilterCategory(q.Category).
+		FilterAttributes(q.Attributes).
+		Search(q.Search).
+		OrderBy(q.Sorts)
+
+	return p
+}
+
+// product yang sudah di soft delete tidak pernah ikut di list
+func (p *ProductQuery) ExcludeDeleted() *ProductQuery {
+	p.where.where(`deletedAt IS NULL`)
+
+	return p
+}
+
+// status kosong/"any" tidak difilter
+func (p *ProductQuery) FilterStatus(status string) *ProductQuery {
+	if status != "" && status != "any" {
+		p.where.where(`status = ` + p.where.arg(status))
+	}
+
+	return p
+}
+
+func (p *ProductQuery) FilterSeller(sellerId string) *ProductQuery {
+	if sellerId != "" {
+		p.where.where(`sellerId = ` + p.where.arg(sellerId))
+	}
+
+	return p
+}
+
+// filter by slug storefront, slug yang tidak ada tidak mengembalikan product apapun
+func (p *ProductQuery) FilterStorefront(slug string) *ProductQuery {
+	if slug != "" {
+		p.where.where(`sellerId = (SELECT sellerId FROM storefronts WHERE slug = ` + p.where.arg(slug) + ` AND deletedAt IS NULL)`)
+	}
+
+	return p
+}
+
+// condition "new"|"second", selain itu ("any"/kosong) tidak difilter
+func (p *ProductQuery) FilterCondition(condition string) *ProductQuery {
+	if condition == "new" || condition == "second" {
+		p.where.where(`condition = ` + p.where.arg(condition))
+	}
+
+	return p
+}
+
+// matchAll true = product harus punya semua tags, false = minimal salah satu
+func (p *ProductQuery) FilterTags(tags []string, matchAll bool) *ProductQuery {
+	if len(tags) == 0 {
+		return p
+	}
+
+	if matchAll {
+		p.where.where(`tags @> ` + p.where.arg(pq.StringArray(tags)) + `::varchar[]`)
+	} else {
+		p.where.where(`tags && ` + p.where.arg(pq.StringArray(tags)) + `::varchar[]`)
+	}
+
+	return p
+}
+
+// stock "exclude" = hanya yang ada stock, "only" = hanya yang stock kosong, selain itu ("include") semua
+func (p *ProductQuery) FilterStock(stock string) *ProductQuery {
+	switch stock {
+	case "exclude":
+		p.where.where(`stock > 0`)
+	case "only":
+		p.where.where(`stock = 0`)
+	}
+
+	return p
+}
+
+// harga 0 berarti tidak dibatasi
+func (p *ProductQuery) FilterPrice(min, max float64) *ProductQuery {
+	if min > 0 {
+		p.where.where(`price >= ` + p.where.arg(min))
+	}
+
+	if max > 0 {
+		p.where.where(`price <= ` + p.where.arg(max))
+	}
+
+	return p
+}
+
+func (p *ProductQuery) FilterPurchaseable(isPurchaseable *bool) *ProductQuery {
+	if isPurchaseable != nil {
+		p.where.where(`isPurchaseable = ` + p.where.arg(*isPurchaseable))
+	}
+
+	return p
+}
+
+// rating 0 berarti tidak dibatasi, product tanpa review (rating 0) tidak ikut kalau difilter
+func (p *ProductQuery) FilterMinRating(minRating float64) *ProductQuery {
+	if minRating > 0 {
+		p.where.where(`ratingAverage >= ` + p.where.arg(minRating))
+	}
+
+	return p
+}
+
+// filter category beserta semua turunannya
+func (p *ProductQuery) FilterCategory(slug string) *ProductQuery {
+	if slug == "" {
+		return p
+	}
+
+	p.where.where(`categoryId IN (
+            WITH RECURSIVE tree AS (
+                SELECT id FROM categories WHERE slug = ` + p.where.arg(slug) + `
+                UNION ALL
+                SELECT categories.id FROM categories JOIN tree ON categories.parentId = tree.id
+            )
+            SELECT id FROM tree)`)
+
+	return p
+}
+
+// filter product yang punya variant tersedia dengan atribut tertentu
+func (p *ProductQuery) FilterAttributes(attributes map[string]string) *ProductQuery {
+	if len(attributes) == 0 {
+		return p
+	}
+
+	b, _ := json.Marshal(attributes)
+	p.where.where(`EXISTS (SELECT 1 FROM productVariants WHERE productVariants.productId = products.id AND productVariants.stock > 0 AND productVariants.attributes @> ` + p.where.arg(string(b)) + `)`)
+
+	return p
+}
+
+// full-text search (prefix match) di name, tags dan descriptions
+func (p *ProductQuery) Search(search string) *ProductQuery {
+	tsQuery := BuildPrefixTsQuery(search)
+	if tsQuery == "" {
+		return p
+	}
+
+	p.searchArg = p.where.arg(tsQuery)
+	p.where.where(`searchVector @@ to_tsquery('simple', ` + p.searchArg + `)`)
+
+	return p
+}
+
+func (p *ProductQuery) OrderBy(sorts []types.SortField) *ProductQuery {
+	p.sorts = sorts
+
+	return p
+}
+
+// sort key yang dipakai, field yang tidak di whitelist dibuang, id selalu jadi tie breaker
+func (p *ProductQuery) sortKeys() []sortKey {
+
+	var keys []sortKey
+	for _, sort := range p.sorts {
+		key, ok := productSortFields[sort.Field]
+		if !ok {
+			continue
+		}
+
+		if sort.Field == "relevance" {
+			if p.searchArg == "" {
+				continue
+			}
+			key.Expr = `ts_rank(searchVector, to_tsquery('simple', ` + p.searchArg + `))`
+		}
+
+		key.Desc = sort.Desc
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		keys = append(keys, sortKey{Expr: `createdAt`, Cast: `timestamp`, Desc: true})
+	}
+
+	return append(keys, sortKey{Expr: `id`, Cast: `uuid`, Desc: keys[0].Desc})
+}
+
+// kondisi WHERE saja, dipakai untuk count dan facet
+func (p *ProductQuery) WhereSQL() (string, []interface{}) {
+
+	params := make([]interface{}, len(p.where.params))
+	copy(params, p.where.params)
+
+	return p.where.clause(), params
+}
+
+func (p *ProductQuery) CountSQL() (string, []interface{}) {
+
+	where, params := p.WhereSQL()
+
+	return `SELECT COUNT(*) FROM products WHERE ` + where, params
+}
+
+// query list product lengkap dengan keyset cursor / offset, ambil limit+1 row untuk cek halaman berikutnya
+func (p *ProductQuery) ListSQL(limit, offset int, cursor *types.Cursor) (string, []interface{}, []sortKey) {
+
+	keys := p.sortKeys()
+	builder := whereBuilder{
+		conditions: append([]string{}, p.where.conditions...),
+		params:     append([]interface{}{}, p.where.params...),
+	}
+
+	backward := false
+	if validCursor(cursor, keys) {
+		condition, cursorParams, _ := generateKeysetCondition(keys, cursor, len(builder.params)+1)
+		builder.conditions = append(builder.conditions, condition)
+		builder.params = append(builder.params, cursorParams...)
+		backward = cursor.Backward
+	}
+
+	query := `
+    SELECT ` + productListColumns + generateSelectSortKeys(keys) + `
+    FROM products
+    WHERE ` + builder.clause() + generateOrderBy(keys, backward) + ` LIMIT ` + builder.arg(limit+1)
+
+	// offset hanya dipakai kalau tidak pakai cursor
+	if cursor == nil {
+		query += ` OFFSET ` + builder.arg(offset)
+	}
+
+	return query, builder.params, keys
+}
+
+// signature sort untuk cursor, contoh "price:asc,createdAt:desc"
+func (p *ProductQuery) SortSignature() string {
+
+	return helper.SortSignature(p.sorts)
+}
@@ -0,0 +1,247 @@
+package datastore
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/lib/pq"
+)
+
+func normalizeSQL(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+func TestProductQueryFilters(t *testing.T) {
+
+	t.Run("Should only show published products by default", func(t *testing.T) {
+		where, params := NewProductQuery(types.ListQueryValid{Condition: "any", Stock: "include"}, "").WhereSQL()
+
+		if where != "(deletedAt IS NULL) AND (status = $1)" {
+			t.Errorf("Expected (deletedAt IS NULL) AND (status = $1), but got=%s", where)
+		}
+
+		if len(params) != 1 || params[0] != "published" {
+			t.Errorf("Expected published param, but got=%v", params)
+		}
+	})
+
+	t.Run("Should ignore status filter unless listing own products", func(t *testing.T) {
+		q := types.ListQueryValid{UserOnly: "true", Status: "draft"}
+
+		where, _ := NewProductQuery(q, "").WhereSQL()
+		if where != "(deletedAt IS NULL) AND (status = $1)" {
+			t.Errorf("Expected published only without user, but got=%s", where)
+		}
+
+		where, params := NewProductQuery(q, "user-id").WhereSQL()
+		if where != "(deletedAt IS NULL) AND (sellerId = $1) AND (status = $2)" || params[1] != "draft" {
+			t.Errorf("Unexpected where, got=%s %v", where, params)
+		}
+	})
+
+	t.Run("Should match any tag with overlap operator", func(t *testing.T) {
+		where, params := (&ProductQuery{}).FilterTags([]string{"baju", "kaos"}, false).WhereSQL()
+
+		if where != "(tags && $1::varchar[])" {
+			t.Errorf("Unexpected where, got=%s", where)
+		}
+
+		tags, ok := params[0].(pq.StringArray)
+		if !ok || len(tags) != 2 {
+			t.Errorf("Expected tags param as array, but got=%v", params[0])
+		}
+	})
+
+	t.Run("Should match all tags with contains operator", func(t *testing.T) {
+		where, _ := (&ProductQuery{}).FilterTags([]string{"baju"}, true).WhereSQL()
+
+		if where != "(tags @> $1::varchar[])" {
+			t.Errorf("Unexpected where, got=%s", where)
+		}
+	})
+
+	t.Run("Should map stock mode", func(t *testing.T) {
+		cases := map[string]string{
+			"include": "TRUE",
+			"exclude": "(stock > 0)",
+			"only":    "(stock = 0)",
+		}
+
+		for mode, expected := range cases {
+			where, _ := (&ProductQuery{}).FilterStock(mode).WhereSQL()
+			if where != expected {
+				t.Errorf("Stock %s expected %s, but got=%s", mode, expected, where)
+			}
+		}
+	})
+
+	t.Run("Should skip condition any", func(t *testing.T) {
+		where, _ := (&ProductQuery{}).FilterCondition("any").WhereSQL()
+		if where != "TRUE" {
+			t.Errorf("Expected TRUE, but got=%s", where)
+		}
+
+		where, params := (&ProductQuery{}).FilterCondition("second").WhereSQL()
+		if where != "(condition = $1)" || params[0] != "second" {
+			t.Errorf("Unexpected where, got=%s %v", where, params)
+		}
+	})
+
+	t.Run("Should apply condition and stock modes from list query", func(t *testing.T) {
+		cases := []struct {
+			name      string
+			condition string
+			stock     string
+			expected  string
+		}{
+			{"any condition include stock", "any", "include", "(deletedAt IS NULL) AND (status = $1)"},
+			{"empty condition and stock", "", "", "(deletedAt IS NULL) AND (status = $1)"},
+			{"new condition exclude stock", "new", "exclude", "(deletedAt IS NULL) AND (status = $1) AND (condition = $2) AND (stock > 0)"},
+			{"second condition only empty stock", "second", "only", "(deletedAt IS NULL) AND (status = $1) AND (condition = $2) AND (stock = 0)"},
+		}
+
+		for _, c := range cases {
+			where, _ := NewProductQuery(types.ListQueryValid{Condition: c.condition, Stock: c.stock}, "").WhereSQL()
+			if where != c.expected {
+				t.Errorf("%s\nexpected=%s\n     got=%s", c.name, c.expected, where)
+			}
+		}
+	})
+
+	t.Run("Should filter by minimum rating", func(t *testing.T) {
+		where, params := (&ProductQuery{}).FilterMinRating(4).WhereSQL()
+
+		if where != "(ratingAverage >= $1)" || params[0] != 4.0 {
+			t.Errorf("Unexpected where, got=%s %v", where, params)
+		}
+	})
+
+	t.Run("Should filter by storefront slug", func(t *testing.T) {
+		where, params := (&ProductQuery{}).FilterStorefront("toko-budi").WhereSQL()
+
+		if where != "(sellerId = (SELECT sellerId FROM storefronts WHERE slug = $1 AND deletedAt IS NULL))" || params[0] != "toko-budi" {
+			t.Errorf("Unexpected where, got=%s %v", where, params)
+		}
+	})
+
+	t.Run("Should only filter own products when userOnly is combined with sellerId", func(t *testing.T) {
+		q := types.ListQueryValid{UserOnly: "true", SellerId: "other-seller-id"}
+
+		where, params := NewProductQuery(q, "user-id").WhereSQL()
+		if where != "(deletedAt IS NULL) AND (sellerId = $1)" || len(params) != 1 || params[0] != "user-id" {
+			t.Errorf("Expected single seller filter for user, got=%s %v", where, params)
+		}
+
+		where, params = NewProductQuery(q, "").WhereSQL()
+		if where != "(deletedAt IS NULL) AND (status = $1) AND (sellerId = $2)" || params[1] != "other-seller-id" {
+			t.Errorf("Expected seller filter without user, got=%s %v", where, params)
+		}
+	})
+
+	t.Run("Should number placeholders in order", func(t *testing.T) {
+		isPurchaseable := true
+		q := types.ListQueryValid{
+			UserOnly:       "true",
+			Condition:      "new",
+			Tags:           []string{"baju"},
+			TagMatch:       "all",
+			Stock:          "exclude",
+			IsPurchaseable: &isPurchaseable,
+			MinPrice:       1000,
+			MaxPrice:       5000,
+		}
+
+		where, params := NewProductQuery(q, "user-id").WhereSQL()
+		expected := "(deletedAt IS NULL) AND (sellerId = $1) AND (condition = $2) AND (tags @> $3::varchar[]) AND (stock > 0) AND (price >= $4) AND (price <= $5) AND (isPurchaseable = $6)"
+
+		if where != expected {
+			t.Errorf("Unexpected where\nexpected=%s\n     got=%s", expected, where)
+		}
+
+		if len(params) != 6 || params[0] != "user-id" || params[5] != true {
+			t.Errorf("Unexpected params, got=%v", params)
+		}
+	})
+}
+
+func TestProductQueryListSQL(t *testing.T) {
+
+	t.Run("Should order by whitelisted columns with id tie breaker", func(t *testing.T) {
+		sorts := []types.SortField{{Field: "price", Desc: false}, {Field: "createdAt", Desc: true}}
+		query, params, keys := (&ProductQuery{}).OrderBy(sorts).ListSQL(10, 20, nil)
+
+		if !strings.Contains(normalizeSQL(query), "WHERE TRUE ORDER BY price ASC, createdAt DESC, id ASC LIMIT $1 OFFSET $2") {
+			t.Errorf("Unexpected query, got=%s", normalizeSQL(query))
+		}
+
+		if len(keys) != 3 {
+			t.Errorf("Expected 3 sort keys, but got=%d", len(keys))
+		}
+
+		if params[0] != 11 || params[1] != 20 {
+			t.Errorf("Expected limit+1 and offset, but got=%v", params)
+		}
+	})
+
+	t.Run("Should drop unknown sort field", func(t *testing.T) {
+		sorts := []types.SortField{{Field: "price; DROP TABLE products", Desc: false}}
+		query, _, _ := (&ProductQuery{}).OrderBy(sorts).ListSQL(10, 0, nil)
+
+		if strings.Contains(query, "DROP") {
+			t.Errorf("Unknown sort field must not reach the query, got=%s", query)
+		}
+
+		if !strings.Contains(normalizeSQL(query), "ORDER BY createdAt DESC, id DESC") {
+			t.Errorf("Expected default order, got=%s", normalizeSQL(query))
+		}
+	})
+
+	t.Run("Should drop relevance without search", func(t *testing.T) {
+		sorts := []types.SortField{{Field: "relevance", Desc: true}}
+		_, _, keys := (&ProductQuery{}).OrderBy(sorts).ListSQL(10, 0, nil)
+
+		if keys[0].Expr != "createdAt" {
+			t.Errorf("Expected createdAt, but got=%s", keys[0].Expr)
+		}
+	})
+
+	t.Run("Should rank by search placeholder", func(t *testing.T) {
+		sorts := []types.SortField{{Field: "relevance", Desc: true}}
+		query, params, _ := (&ProductQuery{}).FilterCondition("new").Search("kaos").OrderBy(sorts).ListSQL(10, 0, nil)
+
+		if !strings.Contains(query, "ORDER BY ts_rank(searchVector, to_tsquery('simple', $2)) DESC") {
+			t.Errorf("Unexpected query, got=%s", normalizeSQL(query))
+		}
+
+		if params[1] != "kaos:*" {
+			t.Errorf("Expected tsquery param, but got=%v", params[1])
+		}
+	})
+
+	t.Run("Should use keyset condition instead of offset with cursor", func(t *testing.T) {
+		sorts := []types.SortField{{Field: "price", Desc: false}}
+		cursor := &types.Cursor{Order: "price:asc", Values: []string{"1000", "a3c3d7a4-6b39-4f4c-9a8b-2f53d5b7b6a1"}}
+		query, params, _ := (&ProductQuery{}).FilterStock("exclude").OrderBy(sorts).ListSQL(10, 5, cursor)
+		normalized := normalizeSQL(query)
+
+		if !strings.Contains(normalized, "WHERE (stock > 0) AND ((price > $1::numeric) OR (price = $1::numeric AND id > $2::uuid))") {
+			t.Errorf("Unexpected keyset condition, got=%s", normalized)
+		}
+
+		if strings.Contains(normalized, "OFFSET") {
+			t.Errorf("Offset must not be used with cursor, got=%s", normalized)
+		}
+
+		if len(params) != 3 || params[2] != 11 {
+			t.Errorf("Unexpected params, got=%v", params)
+		}
+	})
+
+	t.Run("Should build sort signature", func(t *testing.T) {
+		sorts := []types.SortField{{Field: "price", Desc: false}, {Field: "createdAt", Desc: true}}
+		if signature := (&ProductQuery{}).OrderBy(sorts).SortSignature(); signature != "price:asc,createdAt:desc" {
+			t.Errorf("Unexpected signature, got=%s", signature)
+		}
+	})
+}
@@ -0,0 +1,274 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+)
+
+const reviewColumns = `
+        reviews.id,
+        reviews.transactionId,
+        reviews.productId,
+        reviews.variantId,
+        reviews.buyerId,
+        reviews.sellerId,
+        reviews.rating,
+        reviews.body,
+        reviews.reply,
+        reviews.helpfulCount,
+        reviews.createdAt,
+        reviews.updatedAt,
+        reviews.deletedAt,
+        buyers.id,
+        buyers.name,
+        buyers.username`
+
+// urutan list review yang diizinkan
+var reviewOrders = map[string]string{
+	"recent":  `reviews.createdAt DESC, reviews.id DESC`,
+	"helpful": `reviews.helpfulCount DESC, reviews.createdAt DESC, reviews.id DESC`,
+	"rating":  `reviews.rating DESC, reviews.createdAt DESC, reviews.id DESC`,
+}
+
+func (s *Storage) CreateReview(id string, r *entities.Review) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        INSERT INTO reviews (
+            id,
+            transactionId,
+            productId,
+            variantId,
+            buyerId,
+            sellerId,
+            rating,
+            body
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		id,
+		r.TransactionId,
+		r.ProductId,
+		sql.NullString{String: r.VariantId, Valid: r.VariantId != ""},
+		r.BuyerId,
+		r.SellerId,
+		r.Rating,
+		r.Body,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := syncProductRating(tx, r.ProductId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) GetReview(id string) (*entities.Review, error) {
+
+	row := s.db.QueryRow(`
+        SELECT `+reviewColumns+`
+        FROM reviews
+        LEFT JOIN users AS buyers ON reviews.buyerId = buyers.id
+        WHERE reviews.id = $1`, id)
+	review, err := scanReview(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.Review{}, fmt.Errorf("Review did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.Review{}, fmt.Errorf("Something went wrong")
+	default:
+		return review, nil
+	}
+}
+
+func (s *Storage) ListProductReviews(productId string, q types.ListQueryReviewValid) (*[]entities.Review, error) {
+
+	returnReviews := []entities.Review{}
+	order, ok := reviewOrders[q.Order]
+	if !ok {
+		order = reviewOrders["recent"]
+	}
+
+	rows, err := s.db.Query(`
+        SELECT `+reviewColumns+`
+        FROM reviews
+        LEFT JOIN users AS buyers ON reviews.buyerId = buyers.id
+        WHERE reviews.productId = $1 AND ($2 = 0 OR reviews.rating = $2)
+        ORDER BY `+order+`
+        LIMIT $3 OFFSET $4`, productId, q.Rating, q.Limit, q.Offset)
+	if err != nil {
+		log.Println("err inside ListProductReviews", err)
+		return &returnReviews, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return &[]entities.Review{}, err
+		}
+
+		returnReviews = append(returnReviews, *review)
+	}
+
+	return &returnReviews, nil
+}
+
+func (s *Storage) UpdateReview(id string, rating int, body string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var productId string
+	err = tx.QueryRow(`
+        UPDATE reviews
+        SET rating = $1,
+            body = $2,
+            updatedAt = NOW()
+        WHERE id = $3
+        RETURNING productId`, rating, body, id).Scan(&productId)
+	if err != nil {
+		return err
+	}
+
+	if err := syncProductRating(tx, productId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) ReplyReview(id, reply string) error {
+
+	_, err := s.db.Exec(`
+        UPDATE reviews
+        SET reply = $1,
+            repliedAt = NOW(),
+            updatedAt = NOW()
+        WHERE id = $2`, reply, id)
+
+	return err
+}
+
+// satu user hanya bisa vote satu kali per review, vote ulang diabaikan
+func (s *Storage) VoteReview(reviewId, userId string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        INSERT INTO reviewVotes (reviewId, userId) VALUES ($1, $2)
+        ON CONFLICT (reviewId, userId) DO NOTHING`, reviewId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := syncReviewHelpfulCount(tx, reviewId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) UnvoteReview(reviewId, userId string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`DELETE FROM reviewVotes WHERE reviewId = $1 AND userId = $2`, reviewId, userId)
+	if err != nil {
+		return err
+	}
+
+	if err := syncReviewHelpfulCount(tx, reviewId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// rating product selalu dihitung ulang dari tabel reviews, jadi tidak bisa selisih
+func syncProductRating(tx *sql.Tx, productId string) error {
+
+	_, err := tx.Exec(`
+        UPDATE products
+        SET ratingAverage = COALESCE((SELECT ROUND(AVG(rating), 2) FROM reviews WHERE productId = $1), 0),
+            ratingCount = (SELECT COUNT(*) FROM reviews WHERE productId = $1)
+        WHERE id = $1`, productId)
+
+	return err
+}
+
+func syncReviewHelpfulCount(tx *sql.Tx, reviewId string) error {
+
+	_, err := tx.Exec(`
+        UPDATE reviews
+        SET helpfulCount = (SELECT COUNT(*) FROM reviewVotes WHERE reviewId = $1)
+        WHERE id = $1`, reviewId)
+
+	return err
+}
+
+func scanReview(row rowScanner) (*entities.Review, error) {
+
+	var review entities.Review
+	var variantId sql.NullString
+	var buyerId, buyerName, buyerUsername sql.NullString
+
+	err := row.Scan(
+		&review.ID,
+		&review.TransactionId,
+		&review.ProductId,
+		&variantId,
+		&review.BuyerId,
+		&review.SellerId,
+		&review.Rating,
+		&review.Body,
+		&review.Reply,
+		&review.HelpfulCount,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&review.DeletedAt,
+		&buyerId,
+		&buyerName,
+		&buyerUsername,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	review.VariantId = variantId.String
+	review.Buyer = entities.UserMinimal{
+		ID:       buyerId.String,
+		Name:     buyerName.String,
+		Username: buyerUsername.String,
+	}
+
+	return &review, nil
+}
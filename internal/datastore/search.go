@@ -0,0 +1,27 @@
+package datastore
+
+import (
+	"strings"
+	"unicode"
+)
+
+const MAXSEARCHTERMS = 10
+
+// ubah input user jadi tsquery prefix, contoh "Kue nas" -> "kue:* & nas:*".
+// semua karakter selain huruf/angka dibuang supaya input tidak bisa merusak syntax tsquery
+func BuildPrefixTsQuery(search string) string {
+
+	terms := strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	if len(terms) > MAXSEARCHTERMS {
+		terms = terms[:MAXSEARCHTERMS]
+	}
+
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+
+	return strings.Join(terms, " & ")
+}
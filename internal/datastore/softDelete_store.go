@@ -0,0 +1,271 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/lib/pq"
+)
+
+// hasil purge, ImageKeys = storage key gambar product yang barisnya sudah dihapus
+// (file di media store harus dihapus oleh pemanggil)
+type PurgeResult struct {
+	Products     int64    `json:"products"`
+	Users        int64    `json:"users"`
+	BankAccounts int64    `json:"bankAccounts"`
+	ImageKeys    []string `json:"-"`
+}
+
+// batas waktu soft delete yang masih bisa di restore, grace dalam detik ($n)
+func graceCutoff(index int) string {
+	return fmt.Sprintf(`NOW() - ($%d * INTERVAL '1 second')`, index)
+}
+
+// user yang sudah di soft delete, dipakai untuk restore akun
+func (s *Storage) GetDeletedUserByUsername(username string) (*entities.User, error) {
+
+	row := s.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE username = $1 AND deletedAt IS NOT NULL`, username)
+	user, err := scanUser(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.User{}, fmt.Errorf("User did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.User{}, fmt.Errorf("Something went wrong")
+	default:
+		return user, nil
+	}
+}
+
+// product hanya bisa di restore oleh seller aktif dan masih dalam grace period,
+// return sql.ErrNoRows kalau tidak ada yang bisa di restore
+func (s *Storage) RestoreProduct(id, sellerId string, grace time.Duration) error {
+
+	result, err := s.db.Exec(`
+        UPDATE products
+        SET deletedAt = NULL,
+            updatedAt = NOW()
+        WHERE id = $1 AND sellerId = $2
+            AND deletedAt >= `+graceCutoff(3)+`
+            AND EXISTS (SELECT 1 FROM users WHERE users.id = products.sellerId AND users.deletedAt IS NULL)`,
+		id, sellerId, grace.Seconds())
+
+	return checkRestored(result, err)
+}
+
+func (s *Storage) RestoreBankAccount(id, sellerId string, grace time.Duration) error {
+
+	result, err := s.db.Exec(`
+        UPDATE bankAccounts
+        SET deletedAt = NULL,
+            updatedAt = NOW()
+        WHERE id = $1 AND sellerId = $2
+            AND deletedAt >= `+graceCutoff(3),
+		id, sellerId, grace.Seconds())
+
+	return checkRestored(result, err)
+}
+
+// restore user beserta product dan bank account yang ikut terhapus bersamaan (deletedAt sama),
+// yang dihapus sendiri sebelumnya tetap terhapus
+func (s *Storage) RestoreUser(id string, grace time.Duration) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	err = tx.QueryRow(`
+        SELECT deletedAt FROM users
+        WHERE id = $1 AND deletedAt >= `+graceCutoff(2)+`
+        FOR UPDATE`, id, grace.Seconds()).Scan(&deletedAt)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        UPDATE products SET deletedAt = NULL, updatedAt = NOW()
+        WHERE sellerId = $1 AND deletedAt = $2`, id, deletedAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+        UPDATE bankAccounts SET deletedAt = NULL, updatedAt = NOW()
+        WHERE sellerId = $1 AND deletedAt = $2`, id, deletedAt); err != nil {
+		return err
+	}
+
+	// slug storefront yang sudah dipakai toko lain tidak ikut di restore
+	if _, err := tx.Exec(`
+        UPDATE storefronts SET deletedAt = NULL, updatedAt = NOW()
+        WHERE sellerId = $1 AND deletedAt = $2
+            AND NOT EXISTS (SELECT 1 FROM storefronts used WHERE used.slug = storefronts.slug AND used.deletedAt IS NULL)`, id, deletedAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE users SET deletedAt = NULL, updatedAt = NOW() WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// hapus permanen semua baris yang soft delete nya sudah lewat grace period.
+// product/user yang masih direferensikan transaksi tidak dihapus supaya riwayat transaksi tetap utuh
+func (s *Storage) PurgeDeleted(grace time.Duration) (*PurgeResult, error) {
+
+	result := &PurgeResult{ImageKeys: []string{}}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	defer tx.Rollback()
+
+	productIds, err := queryIds(tx, `
+        SELECT id FROM products
+        WHERE deletedAt < `+graceCutoff(1)+`
+            AND NOT EXISTS (SELECT 1 FROM transactions WHERE transactions.productId = products.id)
+        FOR UPDATE`, grace.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(productIds) > 0 {
+		rows, err := tx.Query(`DELETE FROM productImages WHERE productId = ANY($1::uuid[]) RETURNING storageKey`, pq.Array(productIds))
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var key string
+			if err := rows.Scan(&key); err != nil {
+				rows.Close()
+				return nil, err
+			}
+
+			result.ImageKeys = append(result.ImageKeys, key)
+		}
+		rows.Close()
+
+		for _, table := range []string{"productVariants", "productQuestions", "productHistory", "wishlistItems", "discounts", "stockMovements", "stockReservations", "warehouseStocks", "stockSubscriptions", "notifications"} {
+			if _, err := tx.Exec(`DELETE FROM `+table+` WHERE productId = ANY($1::uuid[])`, pq.Array(productIds)); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := tx.Exec(`DELETE FROM products WHERE id = ANY($1::uuid[])`, pq.Array(productIds))
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Products, err = res.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := tx.Exec(`DELETE FROM bankAccounts WHERE deletedAt < `+graceCutoff(1), grace.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	if result.BankAccounts, err = res.RowsAffected(); err != nil {
+		return nil, err
+	}
+
+	// user yang masih punya product (product dengan transaksi) juga tidak dihapus
+	userIds, err := queryIds(tx, `
+        SELECT id FROM users
+        WHERE deletedAt < `+graceCutoff(1)+`
+            AND NOT EXISTS (SELECT 1 FROM transactions WHERE transactions.buyerId = users.id OR transactions.sellerId = users.id)
+            AND NOT EXISTS (SELECT 1 FROM products WHERE products.sellerId = users.id)
+        FOR UPDATE`, grace.Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(userIds) > 0 {
+		for _, query := range []string{
+			`DELETE FROM wishlists WHERE userId = ANY($1::uuid[])`,
+			`DELETE FROM productQuestions WHERE askerId = ANY($1::uuid[])`,
+			`DELETE FROM reviewVotes WHERE userId = ANY($1::uuid[])`,
+			`DELETE FROM bankAccounts WHERE sellerId = ANY($1::uuid[])`,
+			`DELETE FROM coupons WHERE sellerId = ANY($1::uuid[])`,
+			`DELETE FROM warehouseStocks WHERE warehouseId IN (SELECT id FROM warehouses WHERE sellerId = ANY($1::uuid[]))`,
+			`DELETE FROM warehouses WHERE sellerId = ANY($1::uuid[])`,
+			`DELETE FROM stockSubscriptions WHERE userId = ANY($1::uuid[])`,
+			`DELETE FROM notifications WHERE userId = ANY($1::uuid[])`,
+			`DELETE FROM productImportJobs WHERE sellerId = ANY($1::uuid[])`,
+			`DELETE FROM storefronts WHERE sellerId = ANY($1::uuid[])`,
+			`DELETE FROM passwordResets WHERE userId = ANY($1::uuid[])`,
+			`DELETE FROM contactVerifications WHERE userId = ANY($1::uuid[])`,
+			`DELETE FROM recoveryCodes WHERE userId = ANY($1::uuid[])`,
+		} {
+			if _, err := tx.Exec(query, pq.Array(userIds)); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := tx.Exec(`DELETE FROM users WHERE id = ANY($1::uuid[])`, pq.Array(userIds))
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Users, err = res.RowsAffected(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func queryIds(tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+
+	ids := []string{}
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return ids, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return []string{}, err
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func checkRestored(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
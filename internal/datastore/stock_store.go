@@ -0,0 +1,553 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/google/uuid"
+)
+
+// error dari perubahan stock, dicek oleh pemanggil untuk menentukan response
+var (
+	ErrInsufficientStock   = fmt.Errorf("Insufficient stock")
+	ErrReservationReleased = fmt.Errorf("Stock reservation has expired")
+)
+
+// batas reservation kadaluarsa yang diproses sekali jalan
+const MAXEXPIREDRESERVATIONS = 100
+
+// ubah stock product/variant sebesar m.Delta dan catat di ledger,
+// stock tidak boleh jadi minus. return stock setelah perubahan
+func (s *Storage) AdjustStock(m *entities.StockMovement) (int, error) {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	stockAfter, err := applyUnitStockDelta(tx, m.WarehouseId, m.ProductId, m.VariantId, m.Delta)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, ErrInsufficientStock
+	case err != nil:
+		return 0, err
+	}
+
+	m.StockAfter = stockAfter
+	if err := insertStockMovement(tx, m); err != nil {
+		return 0, err
+	}
+
+	return stockAfter, tx.Commit()
+}
+
+// set stock ke angka absolut, delta dihitung dari stock saat ini (row di lock)
+// jadi tetap tercatat sebagai perubahan di ledger
+func (s *Storage) SetStock(m *entities.StockMovement, stock int) (int, error) {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	defer tx.Rollback()
+
+	var current int
+	switch {
+	case m.WarehouseId != "":
+		err = tx.QueryRow(`
+            SELECT stock FROM warehouseStocks
+            WHERE warehouseId = $1 AND productId = $2 AND variantId IS NOT DISTINCT FROM $3
+            FOR UPDATE`, m.WarehouseId, m.ProductId, sql.NullString{String: m.VariantId, Valid: m.VariantId != ""}).Scan(&current)
+
+		// warehouse yang belum pernah punya stock product ini
+		if err == sql.ErrNoRows {
+			err = nil
+		}
+	case m.VariantId != "":
+		err = tx.QueryRow(`SELECT stock FROM productVariants WHERE id = $1 AND productId = $2 FOR UPDATE`, m.VariantId, m.ProductId).Scan(&current)
+	default:
+		err = tx.QueryRow(`SELECT stock FROM products WHERE id = $1 FOR UPDATE`, m.ProductId).Scan(&current)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	m.Delta = stock - current
+	if m.Delta == 0 {
+		return current, nil
+	}
+
+	stockAfter, err := applyUnitStockDelta(tx, m.WarehouseId, m.ProductId, m.VariantId, m.Delta)
+	switch {
+	case err == sql.ErrNoRows:
+		return 0, ErrInsufficientStock
+	case err != nil:
+		return 0, err
+	}
+
+	m.StockAfter = stockAfter
+	if err := insertStockMovement(tx, m); err != nil {
+		return 0, err
+	}
+
+	return stockAfter, tx.Commit()
+}
+
+// tahan stock untuk transaksi, stock langsung dikurangi supaya tidak bisa dibeli orang lain
+func (s *Storage) ReserveStock(r *entities.StockReservation) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	stockAfter, err := applyUnitStockDelta(tx, r.WarehouseId, r.ProductId, r.VariantId, -r.Quantity)
+	switch {
+	case err == sql.ErrNoRows:
+		return ErrInsufficientStock
+	case err != nil:
+		return err
+	}
+
+	_, err = tx.Exec(`
+        INSERT INTO stockReservations (
+            id,
+            transactionId,
+            productId,
+            variantId,
+            warehouseId,
+            buyerId,
+            quantity,
+            expiresAt
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		uuid.NewString(),
+		r.TransactionId,
+		r.ProductId,
+		sql.NullString{String: r.VariantId, Valid: r.VariantId != ""},
+		sql.NullString{String: r.WarehouseId, Valid: r.WarehouseId != ""},
+		r.BuyerId,
+		r.Quantity,
+		r.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := insertStockMovement(tx, &entities.StockMovement{
+		ProductId:     r.ProductId,
+		VariantId:     r.VariantId,
+		WarehouseId:   r.WarehouseId,
+		Type:          "reservation",
+		Delta:         -r.Quantity,
+		StockAfter:    stockAfter,
+		Reason:        "checkout",
+		ActorId:       r.BuyerId,
+		TransactionId: r.TransactionId,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// transaksi diterima seller: reservation di-commit dan status transaksi diganti dalam satu
+// transaksi db, kalau status sudah bukan from (misal keburu ditolak) reservation tidak di-commit
+func (s *Storage) AcceptTransaction(transactionId, from, actorId string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	// lock reservation dulu baru transaksi, urutan lock sama dengan releaseReservation
+	reservation, err := lockReservation(tx, transactionId)
+	switch {
+	case err == sql.ErrNoRows:
+		reservation = nil
+	case err != nil:
+		return err
+	}
+
+	var status string
+	if err := tx.QueryRow(`SELECT status FROM transactions WHERE id = $1 FOR UPDATE`, transactionId).Scan(&status); err != nil {
+		return err
+	}
+
+	if status != from {
+		return ErrTransactionStatusChanged
+	}
+
+	// transaksi lama tanpa reservation langsung diganti status nya
+	if reservation != nil {
+		if err := commitReservation(tx, reservation, actorId); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(`UPDATE transactions SET status = 'diterima seller', updatedAt = NOW() WHERE id = $1`, transactionId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// reservation jadi penjualan, stock tidak berubah lagi karena sudah dikurangi saat reservation
+func commitReservation(tx *sql.Tx, reservation *entities.StockReservation, actorId string) error {
+
+	switch reservation.Status {
+	case "committed":
+		return nil
+	case "released":
+		return ErrReservationReleased
+	}
+
+	var err error
+
+	if _, err := tx.Exec(`UPDATE stockReservations SET status = 'committed', updatedAt = NOW() WHERE id = $1`, reservation.ID); err != nil {
+		return err
+	}
+
+	var stock int
+	switch {
+	case reservation.WarehouseId != "":
+		err = tx.QueryRow(`
+            SELECT stock FROM warehouseStocks
+            WHERE warehouseId = $1 AND productId = $2 AND variantId IS NOT DISTINCT FROM $3`,
+			reservation.WarehouseId, reservation.ProductId, sql.NullString{String: reservation.VariantId, Valid: reservation.VariantId != ""}).Scan(&stock)
+	case reservation.VariantId != "":
+		err = tx.QueryRow(`SELECT stock FROM productVariants WHERE id = $1`, reservation.VariantId).Scan(&stock)
+	default:
+		err = tx.QueryRow(`SELECT stock FROM products WHERE id = $1`, reservation.ProductId).Scan(&stock)
+	}
+
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	return insertStockMovement(tx, &entities.StockMovement{
+		ProductId:     reservation.ProductId,
+		VariantId:     reservation.VariantId,
+		WarehouseId:   reservation.WarehouseId,
+		Type:          "sale",
+		Delta:         0,
+		StockAfter:    stock,
+		Reason:        "transaction accepted",
+		ActorId:       actorId,
+		TransactionId: reservation.TransactionId,
+	})
+}
+
+// kembalikan stock reservation yang masih aktif (transaksi ditolak/gagal dibuat)
+func (s *Storage) ReleaseReservation(transactionId, actorId, reason string) error {
+
+	_, err := s.releaseReservation(transactionId, actorId, reason, false)
+
+	return err
+}
+
+// kembalikan stock semua reservation yang sudah lewat expiresAt dan tolak transaksinya
+// kalau masih menunggu. return id transaksi yang reservation nya dilepas
+func (s *Storage) ReleaseExpiredReservations() ([]string, error) {
+
+	transactionIds := []string{}
+	rows, err := s.db.Query(`
+        SELECT transactionId FROM stockReservations
+        WHERE status = 'active' AND expiresAt < NOW()
+        ORDER BY expiresAt
+        LIMIT $1`, MAXEXPIREDRESERVATIONS)
+	if err != nil {
+		return transactionIds, err
+	}
+
+	expiredIds := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return transactionIds, err
+		}
+
+		expiredIds = append(expiredIds, id)
+	}
+	rows.Close()
+
+	for _, id := range expiredIds {
+		released, err := s.releaseReservation(id, "", "reservation expired", true)
+		if err != nil {
+			log.Println("error when releasing expired reservation", id, err)
+			continue
+		}
+
+		if released {
+			transactionIds = append(transactionIds, id)
+		}
+	}
+
+	return transactionIds, nil
+}
+
+// ledger stock product terbaru dulu beserta total, movementType kosong = semua type
+func (s *Storage) ListStockMovements(productId, movementType string, limit, offset int) (*[]entities.StockMovement, int, error) {
+
+	returnMovements := []entities.StockMovement{}
+
+	var total int
+	err := s.db.QueryRow(`
+        SELECT COUNT(*) FROM stockMovements
+        WHERE productId = $1 AND ($2 = '' OR type = $2)`, productId, movementType).Scan(&total)
+	if err != nil {
+		log.Println("err when counting stock movements", err)
+		return &returnMovements, 0, err
+	}
+
+	rows, err := s.db.Query(`
+        SELECT
+            stockMovements.id,
+            stockMovements.productId,
+            stockMovements.variantId,
+            stockMovements.warehouseId,
+            stockMovements.type,
+            stockMovements.delta,
+            stockMovements.stockAfter,
+            stockMovements.reason,
+            stockMovements.transactionId,
+            stockMovements.createdAt,
+            users.id,
+            users.name,
+            users.username
+        FROM stockMovements
+        LEFT JOIN users ON stockMovements.actorId = users.id
+        WHERE stockMovements.productId = $1 AND ($2 = '' OR stockMovements.type = $2)
+        ORDER BY stockMovements.createdAt DESC, stockMovements.id DESC
+        LIMIT $3 OFFSET $4`, productId, movementType, limit, offset)
+	if err != nil {
+		log.Println("err inside ListStockMovements", err)
+		return &returnMovements, 0, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var movement entities.StockMovement
+		var variantId, warehouseId, transactionId, actorId, actorName, actorUsername sql.NullString
+
+		if err := rows.Scan(
+			&movement.ID,
+			&movement.ProductId,
+			&variantId,
+			&warehouseId,
+			&movement.Type,
+			&movement.Delta,
+			&movement.StockAfter,
+			&movement.Reason,
+			&transactionId,
+			&movement.CreatedAt,
+			&actorId,
+			&actorName,
+			&actorUsername,
+		); err != nil {
+			return &[]entities.StockMovement{}, 0, err
+		}
+
+		movement.VariantId = variantId.String
+		movement.WarehouseId = warehouseId.String
+		movement.TransactionId = transactionId.String
+		movement.ActorId = actorId.String
+		movement.Actor = entities.UserMinimal{
+			ID:       actorId.String,
+			Name:     actorName.String,
+			Username: actorUsername.String,
+		}
+
+		returnMovements = append(returnMovements, movement)
+	}
+
+	return &returnMovements, total, nil
+}
+
+func (s *Storage) releaseReservation(transactionId, actorId, reason string, cancelTransaction bool) (bool, error) {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return false, err
+	}
+
+	defer tx.Rollback()
+
+	reservation, err := lockReservation(tx, transactionId)
+	switch {
+	case err == sql.ErrNoRows:
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	if reservation.Status != "active" {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`UPDATE stockReservations SET status = 'released', updatedAt = NOW() WHERE id = $1`, reservation.ID); err != nil {
+		return false, err
+	}
+
+	// product/variant yang sudah dihapus tidak perlu dikembalikan stock nya
+	stockAfter, err := applyUnitStockDelta(tx, reservation.WarehouseId, reservation.ProductId, reservation.VariantId, reservation.Quantity)
+	switch {
+	case err == sql.ErrNoRows:
+	case err != nil:
+		return false, err
+	default:
+		if err := insertStockMovement(tx, &entities.StockMovement{
+			ProductId:     reservation.ProductId,
+			VariantId:     reservation.VariantId,
+			WarehouseId:   reservation.WarehouseId,
+			Type:          "release",
+			Delta:         reservation.Quantity,
+			StockAfter:    stockAfter,
+			Reason:        reason,
+			ActorId:       actorId,
+			TransactionId: transactionId,
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	if cancelTransaction {
+		if _, err := tx.Exec(`
+            UPDATE transactions
+            SET status = 'ditolak',
+                updatedAt = NOW()
+            WHERE id = $1 AND status = 'menunggu'`, transactionId); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+func lockReservation(tx *sql.Tx, transactionId string) (*entities.StockReservation, error) {
+
+	var reservation entities.StockReservation
+	var variantId, warehouseId sql.NullString
+
+	err := tx.QueryRow(`
+        SELECT id, transactionId, productId, variantId, warehouseId, buyerId, quantity, status, expiresAt
+        FROM stockReservations
+        WHERE transactionId = $1
+        FOR UPDATE`, transactionId).Scan(
+		&reservation.ID,
+		&reservation.TransactionId,
+		&reservation.ProductId,
+		&variantId,
+		&warehouseId,
+		&reservation.BuyerId,
+		&reservation.Quantity,
+		&reservation.Status,
+		&reservation.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation.VariantId = variantId.String
+	reservation.WarehouseId = warehouseId.String
+
+	return &reservation, nil
+}
+
+// ubah stock warehouse (kalau ada) sekaligus stock total, return stock warehouse
+// atau stock total kalau tanpa warehouse
+func applyUnitStockDelta(tx *sql.Tx, warehouseId, productId, variantId string, delta int) (int, error) {
+
+	if warehouseId == "" {
+		return applyStockDelta(tx, productId, variantId, delta)
+	}
+
+	warehouseStock, err := applyWarehouseStockDelta(tx, warehouseId, productId, variantId, delta)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := applyStockDelta(tx, productId, variantId, delta); err != nil {
+		return 0, err
+	}
+
+	return warehouseStock, nil
+}
+
+// stock total tidak boleh kurang dari jumlah stock di semua warehouse,
+// sisanya adalah stock yang belum dialokasikan ke warehouse.
+// return sql.ErrNoRows kalau product/variant tidak ada atau stock tidak cukup
+func applyStockDelta(tx *sql.Tx, productId, variantId string, delta int) (int, error) {
+
+	var stock int
+
+	if variantId == "" {
+		err := tx.QueryRow(`
+            UPDATE products
+            SET stock = stock + $1,
+                updatedAt = NOW()
+            WHERE id = $2 AND stock + $1 >= 0
+                AND stock + $1 >= (SELECT COALESCE(SUM(stock), 0) FROM warehouseStocks WHERE productId = $2 AND variantId IS NULL)
+            RETURNING stock`, delta, productId).Scan(&stock)
+
+		return stock, err
+	}
+
+	err := tx.QueryRow(`
+        UPDATE productVariants
+        SET stock = stock + $1,
+            updatedAt = NOW()
+        WHERE id = $2 AND productId = $3 AND stock + $1 >= 0
+            AND stock + $1 >= (SELECT COALESCE(SUM(stock), 0) FROM warehouseStocks WHERE variantId = $2)
+        RETURNING stock`, delta, variantId, productId).Scan(&stock)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := syncProductStockFromVariants(tx, productId); err != nil {
+		return 0, err
+	}
+
+	return stock, nil
+}
+
+func insertStockMovement(tx *sql.Tx, m *entities.StockMovement) error {
+
+	_, err := tx.Exec(`
+        INSERT INTO stockMovements (
+            id,
+            productId,
+            variantId,
+            warehouseId,
+            type,
+            delta,
+            stockAfter,
+            reason,
+            actorId,
+            transactionId
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)`,
+		uuid.NewString(),
+		m.ProductId,
+		sql.NullString{String: m.VariantId, Valid: m.VariantId != ""},
+		sql.NullString{String: m.WarehouseId, Valid: m.WarehouseId != ""},
+		m.Type,
+		m.Delta,
+		m.StockAfter,
+		m.Reason,
+		sql.NullString{String: m.ActorId, Valid: m.ActorId != ""},
+		sql.NullString{String: m.TransactionId, Valid: m.TransactionId != ""},
+	)
+
+	return err
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
 	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
@@ -16,17 +17,36 @@ type Store interface {
 	CreateUser(id string, u *entities.User) error
 	GetUserById(id string) (*entities.User, error)
 	GetUserByUsername(username string) (*entities.User, error)
+	GetUserStats(id string) (*entities.UserStats, error)
+	UpdateUserPassword(id, password string) (int, error)
+	CreatePasswordReset(userId, tokenHash string, expiresAt time.Time) error
+	ResetPassword(tokenHash, password string) (string, error)
+	UpdateUserContact(id, email, phone string) error
+	CreateContactVerification(v *entities.ContactVerification) error
+	GetLatestContactVerification(userId, channel string) (*entities.ContactVerification, error)
+	VerifyContact(userId, channel, codeHash string) error
+	SetTOTPSecret(id, secret string) error
+	EnableTOTP(id string, step int64, recoveryCodeHashes []string) error
+	DisableTOTP(id string) error
+	UseTOTPStep(id string, step int64) error
+	UseRecoveryCode(id, codeHash string) error
+	RecordTOTPFailure(id string) error
+	ReplaceRecoveryCodes(id string, recoveryCodeHashes []string) error
+	CountRecoveryCodes(id string) (int, error)
 	UpdateUser(id, name, username string) error
 	DeleteUser(id string) error
+	GetDeletedUserByUsername(username string) (*entities.User, error)
+	RestoreUser(id string, grace time.Duration) error
 
 	// product
 	CreateProduct(id, sellerId string, p *entities.Product) error
 	GetProductById(id string) (*entities.Product, error)
 	UpdateProduct(id string, p *entities.Product) error
-	UpdateStockProduct(id string, stock int) error
 	DeleteProduct(id string) error
 	GetProductSeller(id string) (string, error)
-	ListProducts(q types.ListQueryValid, userId string) (*[]entities.Product, error)
+	ListProducts(q types.ListQueryValid, userId string) (*[]entities.Product, *types.PageInfo, error)
+	ListProductFacets(q types.ListQueryValid, userId string) (*ProductFacets, error)
+	RestoreProduct(id, sellerId string, grace time.Duration) error
 
 	// bankAccount
 	CreateBankAccount(id, sellerId string, b *entities.BankAccount) error
@@ -34,12 +54,147 @@ type Store interface {
 	ListBankAccount(id string) (*[]entities.BankAccount, error)
 	DeleteBankAccount(id string) error
 	UpdateBankAccount(id string, p *entities.BankAccount) error
+	RestoreBankAccount(id, sellerId string, grace time.Duration) error
 
 	// transaction
 	CreateTransaction(id, buyerId, sellerId, productId string, total float64, t *entities.Transaction) error
 	GetTransaction(id string) (*TransactionReturn, error)
-	ListTransaction(q types.ListQueryTransactionValid, userId string) (*[]TransactionReturn, error)
-	UpdateStatusTransaction(id, status string) error
+	ListTransaction(q types.ListQueryTransactionValid, userId string) (*[]TransactionReturn, *types.PageInfo, error)
+	UpdateStatusTransaction(id, from, status string) error
+
+	// productImage
+	CreateProductImages(productId string, imgs []entities.ProductImage, maxImages int) error
+	GetProductImage(id string) (*entities.ProductImage, error)
+	ListProductImages(productId string) (*[]entities.ProductImage, error)
+	DeleteProductImage(id string) error
+	ReorderProductImages(productId string, imageIds []string) error
+	UpdateProductImageUrl(productId, imageUrl string) error
+
+	// productVariant
+	CreateProductVariant(id, productId string, v *entities.ProductVariant) error
+	GetProductVariant(id string) (*entities.ProductVariant, error)
+	ListProductVariants(productId string) (*[]entities.ProductVariant, error)
+	UpdateProductVariant(id string, v *entities.ProductVariant) error
+	DeleteProductVariant(id string) error
+
+	// category
+	CreateCategory(id string, c *entities.Category) error
+	GetCategoryById(id string) (*entities.Category, error)
+	GetCategoryBySlug(slug string) (*entities.Category, error)
+	ListCategories() (*[]entities.Category, error)
+	UpdateCategory(id string, c *entities.Category) error
+	DeleteCategory(id string) error
+	GetCategoryPath(id string) (*[]entities.CategoryMinimal, error)
+	CountCategoryUsage(id string) (int, int, error)
+
+	// review
+	CreateReview(id string, r *entities.Review) error
+	GetReview(id string) (*entities.Review, error)
+	ListProductReviews(productId string, q types.ListQueryReviewValid) (*[]entities.Review, error)
+	UpdateReview(id string, rating int, body string) error
+	ReplyReview(id, reply string) error
+	VoteReview(reviewId, userId string) error
+	UnvoteReview(reviewId, userId string) error
+
+	// wishlist
+	CreateWishlist(id, userId, name string) error
+	GetWishlist(id string) (*entities.Wishlist, error)
+	GetWishlistByShareToken(token string) (*entities.Wishlist, error)
+	ListWishlists(userId string) (*[]entities.Wishlist, error)
+	UpdateWishlist(id, name string) error
+	SetWishlistShareToken(id, token string) error
+	DeleteWishlist(id string) error
+	AddWishlistItem(id, wishlistId string, item *entities.WishlistItem) error
+	ListWishlistItems(wishlistId string) (*[]entities.WishlistItem, error)
+	DeleteWishlistItem(wishlistId, itemId string) error
+
+	// productQuestion
+	CreateProductQuestion(id string, q *entities.ProductQuestion) error
+	GetProductQuestion(id string) (*entities.ProductQuestion, error)
+	ListProductQuestions(productId string, limit, offset int, includeHidden bool) (*[]entities.ProductQuestion, int, error)
+	AnswerProductQuestion(id, answer string) error
+	SetProductQuestionHidden(id string, hidden bool) error
+	DeleteProductQuestion(id string) error
+
+	// productHistory
+	CreateProductHistory(productId, changedBy string, before, after *entities.Product) error
+	ListProductHistory(productId string, limit, offset int) (*[]entities.ProductHistory, int, error)
+	ListProductPriceHistory(productId string) (*[]entities.PricePoint, error)
+
+	// productStatus
+	UpdateProductStatus(id string, p *entities.Product) error
+	ApplyProductSchedule() (int64, int64, error)
+
+	// discount
+	CreateDiscount(id string, d *entities.Discount) error
+	GetDiscount(id string) (*entities.Discount, error)
+	ListProductDiscounts(productId string) (*[]entities.Discount, error)
+	DeleteDiscount(id string) error
+	CountOverlappingDiscounts(productId string, startsAt, endsAt time.Time) (int, error)
+	GetActiveDiscount(productId string) (*entities.Discount, error)
+	ListActiveDiscounts(productIds []string) (map[string]entities.Discount, error)
+	ClaimDiscount(discountId, buyerId, transactionId string, quantity int) error
+	ReleaseDiscountClaim(transactionId string) error
+
+	// stock
+	AdjustStock(m *entities.StockMovement) (int, error)
+	SetStock(m *entities.StockMovement, stock int) (int, error)
+	BatchUpdateProducts(sellerId string, items []entities.ProductBatchItem, atomic bool) ([]entities.ProductBatchResult, error)
+	ReserveStock(r *entities.StockReservation) error
+	AcceptTransaction(transactionId, from, actorId string) error
+	ReleaseReservation(transactionId, actorId, reason string) error
+	ReleaseExpiredReservations() ([]string, error)
+	ListStockMovements(productId, movementType string, limit, offset int) (*[]entities.StockMovement, int, error)
+
+	// warehouse
+	CreateWarehouse(id string, w *entities.Warehouse) error
+	GetWarehouse(id string) (*entities.Warehouse, error)
+	ListWarehouses(sellerId string) (*[]entities.Warehouse, error)
+	UpdateWarehouse(id string, w *entities.Warehouse) error
+	DeleteWarehouse(id string) error
+	ListWarehouseStocks(productId string) (*[]entities.WarehouseStock, error)
+	TransferStock(t *entities.StockTransfer) error
+	ChooseFulfilmentWarehouse(productId, variantId string, quantity int, latitude, longitude *float64) (string, error)
+
+	// notification
+	SetLowStockThreshold(productId string, threshold int) error
+	NotifyLowStock(productId string, quantity int) error
+	SubscribeStock(productId, userId string) error
+	UnsubscribeStock(productId, userId string) error
+	NotifyBackInStock(productId string) (int, error)
+	ListNotifications(userId string, unreadOnly bool, limit, offset int) (*[]entities.Notification, int, error)
+	MarkNotificationRead(id, userId string) error
+	MarkAllNotificationsRead(userId string) error
+
+	// product import/export
+	GetProductBySku(sellerId, sku string) (*entities.Product, error)
+	ListSellerProducts(sellerId string) (*[]entities.Product, error)
+	CreateProductImportJob(id string, job *entities.ProductImportJob) error
+	GetProductImportJob(id string) (*entities.ProductImportJob, error)
+	ClaimProductImportJob() (*entities.ProductImportJob, error)
+	FinishProductImportJob(job *entities.ProductImportJob) error
+
+	// storefront
+	CreateStorefront(id string, f *entities.Storefront) error
+	GetStorefrontBySeller(sellerId string) (*entities.Storefront, error)
+	GetStorefrontBySlug(slug string) (*entities.Storefront, error)
+	UpdateStorefront(id string, f *entities.Storefront) error
+	GetStorefrontStats(sellerId string) (*entities.StorefrontStats, error)
+
+	// coupon
+	CreateCoupon(id string, c *entities.Coupon) error
+	GetCoupon(id string) (*entities.Coupon, error)
+	GetCouponByCode(code string) (*entities.Coupon, error)
+	ListCoupons(sellerId string) (*[]entities.Coupon, error)
+	DeleteCoupon(id string) error
+	ClaimCoupon(couponId, userId, transactionId string, amount float64) error
+	ReleaseCouponClaim(transactionId string) error
+
+	// search
+	SuggestProducts(search string, limit int) (*[]ProductSuggestion, error)
+
+	// purge
+	PurgeDeleted(grace time.Duration) (*PurgeResult, error)
 }
 
 type TransactionReturn struct {
@@ -49,6 +204,11 @@ type TransactionReturn struct {
 	Buyer       entities.UserMinimal        `json:"buyer"`
 }
 
+type ProductSuggestion struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 type Storage struct {
 	db *sql.DB
 }
@@ -70,8 +230,15 @@ func (s *Storage) CreateTransaction(id, buyerId, sellerId, productId string, tot
     sellerId,
     quantity,
     notes,
-    total
-    ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8);`
+    total,
+    variantId,
+    discountId,
+    discountAmount,
+    couponId,
+    subtotal,
+    couponDiscount,
+    warehouseId
+    ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15);`
 
 	_, err := s.db.Exec(
 		query,
@@ -83,6 +250,13 @@ func (s *Storage) CreateTransaction(id, buyerId, sellerId, productId string, tot
 		t.Quantity,
 		t.Notes,
 		total,
+		sql.NullString{String: t.VariantId, Valid: t.VariantId != ""},
+		sql.NullString{String: t.DiscountId, Valid: t.DiscountId != ""},
+		t.DiscountAmount,
+		sql.NullString{String: t.CouponId, Valid: t.CouponId != ""},
+		t.Subtotal,
+		t.CouponDiscount,
+		sql.NullString{String: t.WarehouseId, Valid: t.WarehouseId != ""},
 	)
 	if err != nil {
 		return err
@@ -93,6 +267,7 @@ func (s *Storage) CreateTransaction(id, buyerId, sellerId, productId string, tot
 
 func (s *Storage) GetTransaction(id string) (*TransactionReturn, error) {
 	var transaction TransactionReturn
+	var variantId sql.NullString
 	query := `
        SELECT 
             transactions.id,
@@ -102,6 +277,11 @@ func (s *Storage) GetTransaction(id string) (*TransactionReturn, error) {
             transactions.notes,
             transactions.createdAt,
             transactions.updatedAt,
+            transactions.variantId,
+            transactions.discountAmount,
+            COALESCE(transactions.subtotal, transactions.total),
+            transactions.couponDiscount,
+            COALESCE(transactions.warehouseId::text, ''),
 
             products.id,
             products.name,
@@ -136,6 +316,11 @@ func (s *Storage) GetTransaction(id string) (*TransactionReturn, error) {
 		&transaction.Transaction.Notes,
 		&transaction.Transaction.CreatedAt,
 		&transaction.Transaction.UpdatedAt,
+		&variantId,
+		&transaction.Transaction.DiscountAmount,
+		&transaction.Transaction.Subtotal,
+		&transaction.Transaction.CouponDiscount,
+		&transaction.Transaction.WarehouseId,
 
 		&transaction.Product.ID,
 		&transaction.Product.Name,
@@ -157,6 +342,8 @@ func (s *Storage) GetTransaction(id string) (*TransactionReturn, error) {
 		return nil, err
 	}
 
+	transaction.Transaction.VariantId = variantId.String
+
 	switch {
 	case err == sql.ErrNoRows:
 		return &TransactionReturn{}, fmt.Errorf("Transaction did not exists")
@@ -168,21 +355,24 @@ func (s *Storage) GetTransaction(id string) (*TransactionReturn, error) {
 	}
 }
 
-func (s *Storage) ListTransaction(q types.ListQueryTransactionValid, userId string) (*[]TransactionReturn, error) {
-	baseQuery, params := GenerateQueryListTransaction(q, userId)
+func (s *Storage) ListTransaction(q types.ListQueryTransactionValid, userId string) (*[]TransactionReturn, *types.PageInfo, error) {
+	baseQuery, params, keys := GenerateQueryListTransaction(q, userId)
 	var returnTransaction []TransactionReturn
+	var sortValues [][]string
 	rows, err := s.db.Query(baseQuery, params...)
 
 	if err != nil {
 		log.Println("err inside ListTransaction", err)
-		return &[]TransactionReturn{}, err
+		return &[]TransactionReturn{}, &types.PageInfo{}, err
 	}
 
 	defer rows.Close()
 
 	for rows.Next() {
 		var transaction TransactionReturn
-		if err := rows.Scan(
+		var variantId sql.NullString
+		values := make([]string, len(keys))
+		dest := []interface{}{
 			&transaction.Transaction.ID,
 			&transaction.Transaction.Status,
 			&transaction.Transaction.Total,
@@ -190,6 +380,11 @@ func (s *Storage) ListTransaction(q types.ListQueryTransactionValid, userId stri
 			&transaction.Transaction.Notes,
 			&transaction.Transaction.CreatedAt,
 			&transaction.Transaction.UpdatedAt,
+			&variantId,
+			&transaction.Transaction.DiscountAmount,
+			&transaction.Transaction.Subtotal,
+			&transaction.Transaction.CouponDiscount,
+			&transaction.Transaction.WarehouseId,
 
 			&transaction.Product.ID,
 			&transaction.Product.Name,
@@ -206,33 +401,76 @@ func (s *Storage) ListTransaction(q types.ListQueryTransactionValid, userId stri
 			&transaction.Buyer.ID,
 			&transaction.Buyer.Name,
 			&transaction.Buyer.Username,
-		); err != nil {
-			return &[]TransactionReturn{}, nil
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
 		}
 
+		if err := rows.Scan(dest...); err != nil {
+			log.Println(err)
+			return &[]TransactionReturn{}, &types.PageInfo{}, nil
+		}
+
+		transaction.Transaction.VariantId = variantId.String
 		returnTransaction = append(returnTransaction, transaction)
+		sortValues = append(sortValues, values)
+	}
+
+	// query ambil limit+1 row untuk tahu masih ada halaman berikutnya atau tidak
+	hasMore := len(returnTransaction) > q.Limit
+	if hasMore {
+		returnTransaction = returnTransaction[:q.Limit]
+		sortValues = sortValues[:q.Limit]
+	}
+
+	if q.Cursor != nil && q.Cursor.Backward {
+		for i, j := 0, len(returnTransaction)-1; i < j; i, j = i+1, j-1 {
+			returnTransaction[i], returnTransaction[j] = returnTransaction[j], returnTransaction[i]
+		}
+		reverseSortValues(sortValues)
+	}
+
+	pageInfo := buildPageInfo(q.Order+":"+q.Sort, q.Cursor, hasMore, q.Offset > 0, sortValues)
+
+	if q.WithTotal {
+		where, whereParams, _ := generateWhereListTransaction(q, userId)
+		var total int
+		if err := s.db.QueryRow(`SELECT COUNT(*) FROM transactions WHERE `+where, whereParams...).Scan(&total); err != nil {
+			log.Println("err when counting transactions", err)
+			return &[]TransactionReturn{}, &types.PageInfo{}, err
+		}
+
+		pageInfo.Total = &total
 	}
 
 	if len(returnTransaction) == 0 {
-		return &[]TransactionReturn{}, nil
+		return &[]TransactionReturn{}, pageInfo, nil
 	}
 
-	return &returnTransaction, nil
+	return &returnTransaction, pageInfo, nil
 }
 
-func (s *Storage) UpdateStatusTransaction(id, status string) error {
+var ErrTransactionStatusChanged = fmt.Errorf("Transaction status has changed, please refresh")
+
+// status hanya diganti kalau masih sama dengan status asal (from), supaya dua update
+// bersamaan tidak bisa melompati transisi
+func (s *Storage) UpdateStatusTransaction(id, from, status string) error {
 	query := `
     UPDATE transactions 
     SET status = $1,
         updatedAt = NOW()
-    WHERE id = $2;
+    WHERE id = $2 AND status = $3;
     `
 
-	_, err := s.db.Exec(query, status, id)
+	res, err := s.db.Exec(query, status, id, from)
 	if err != nil {
 		return err
 	}
 
+	if affected, err := res.RowsAffected(); err != nil || affected < 1 {
+		return ErrTransactionStatusChanged
+	}
+
 	return nil
 }
 
@@ -255,7 +493,7 @@ func (s *Storage) UpdateBankAccount(id string, b *entities.BankAccount) error {
 
 func (s *Storage) DeleteBankAccount(id string) error {
 
-	query := `DELETE FROM bankAccounts WHERE id = $1`
+	query := `UPDATE bankAccounts SET deletedAt = NOW() WHERE id = $1 AND deletedAt IS NULL`
 	_, err := s.db.Exec(query, id)
 	if err != nil {
 		return err
@@ -278,7 +516,7 @@ func (s *Storage) ListBankAccount(id string) (*[]entities.BankAccount, error) {
         updatedAt,
         deletedAt 
     FROM bankAccounts 
-    WHERE sellerId = $1`
+    WHERE sellerId = $1 AND deletedAt IS NULL`
 
 	rows, err := s.db.Query(query, id)
 	if err != nil {
@@ -322,7 +560,7 @@ func (s *Storage) GetBankAccount(id string) (*entities.BankAccount, error) {
         createdAt,
         updatedAt,
         deletedAt 
-    FROM bankAccounts WHERE id = $1`
+    FROM bankAccounts WHERE id = $1 AND deletedAt IS NULL`
 
 	err := s.db.QueryRow(query, id).Scan(
 		&bankAccount.Id,
@@ -368,9 +606,27 @@ func (s *Storage) CreateBankAccount(id, sellerId string, b *entities.BankAccount
 	return nil
 }
 
+const userColumns = `
+        id,
+        name,
+        username,
+        hashPassword,
+        passwordChangedAt,
+        tokenVersion,
+        email,
+        phone,
+        emailVerifiedAt,
+        phoneVerifiedAt,
+        totpSecret,
+        totpEnabledAt,
+        totpLockedUntil,
+        createdAt,
+        updatedAt,
+        deletedAt`
+
 func (s *Storage) CreateUser(id string, u *entities.User) error {
 
-	hashedPassword := helper.GenerateHash(u.HashPassword)
+	hashedPassword := helper.GenerateHash(u.Password)
 
 	_, err := s.db.Exec(`
         INSERT INTO users (
@@ -394,26 +650,8 @@ func (s *Storage) CreateUser(id string, u *entities.User) error {
 
 func (s *Storage) GetUserById(id string) (*entities.User, error) {
 
-	var user entities.User
-	err := s.db.QueryRow(`
-        SELECT 
-            id,
-            name,
-            username,
-            hashPassword,
-            createdAt,
-            updatedAt,
-            deletedAt 
-        FROM users 
-        WHERE id = $1`, id).Scan(
-		&user.ID,
-		&user.Name,
-		&user.Username,
-		&user.HashPassword,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-		&user.DeletedAt,
-	)
+	row := s.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE id = $1 AND deletedAt IS NULL`, id)
+	user, err := scanUser(row)
 
 	switch {
 	case err == sql.ErrNoRows:
@@ -422,47 +660,56 @@ func (s *Storage) GetUserById(id string) (*entities.User, error) {
 		log.Println(err)
 		return &entities.User{}, fmt.Errorf("Something went wrong")
 	default:
-		return &user, nil
+		return user, nil
 	}
-
 }
 
 func (s *Storage) GetUserByUsername(username string) (*entities.User, error) {
 
+	row := s.db.QueryRow(`SELECT `+userColumns+` FROM users WHERE username = $1 AND deletedAt IS NULL`, username)
+	user, err := scanUser(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.User{}, fmt.Errorf("User did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.User{}, fmt.Errorf("Something went wrong")
+	default:
+		return user, nil
+	}
+}
+
+func scanUser(row rowScanner) (*entities.User, error) {
+
 	var user entities.User
-	err := s.db.QueryRow(`
-        SELECT 
-            id, 
-            name,
-            username,
-            hashPassword,
-            createdAt,
-            updatedAt,
-            deletedAt 
-        FROM users 
-        WHERE username = $1`, username).Scan(
+
+	err := row.Scan(
 		&user.ID,
 		&user.Name,
 		&user.Username,
 		&user.HashPassword,
+		&user.PasswordChangedAt,
+		&user.TokenVersion,
+		&user.Email,
+		&user.Phone,
+		&user.EmailVerifiedAt,
+		&user.PhoneVerifiedAt,
+		&user.TotpSecret,
+		&user.TotpEnabledAt,
+		&user.TotpLockedUntil,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 		&user.DeletedAt,
 	)
-
-	switch {
-	case err == sql.ErrNoRows:
-		return &entities.User{}, fmt.Errorf("User did not exists")
-	case err != nil:
-		log.Println(err)
-		return &entities.User{}, fmt.Errorf("Something went wrong")
-	default:
-		return &user, nil
+	if err != nil {
+		return nil, err
 	}
 
+	return &user, nil
 }
 
-func (s *Storage) UpdateUser(id, username, name string) error {
+func (s *Storage) UpdateUser(id, name, username string) error {
 
 	_, err := s.db.Exec(`
         UPDATE users 
@@ -480,11 +727,51 @@ func (s *Storage) UpdateUser(id, username, name string) error {
 	return nil
 }
 
+func (s *Storage) GetUserStats(id string) (*entities.UserStats, error) {
+
+	var stats entities.UserStats
+	err := s.db.QueryRow(`
+        SELECT
+            users.createdAt,
+            (SELECT COUNT(*) FROM products WHERE products.sellerId = users.id AND products.status = 'published' AND products.deletedAt IS NULL),
+            (SELECT COUNT(*) FROM transactions WHERE transactions.sellerId = users.id AND transactions.status = 'diterima'),
+            (SELECT COALESCE(AVG(rating), 0) FROM reviews WHERE reviews.sellerId = users.id AND reviews.deletedAt IS NULL),
+            (SELECT COUNT(*) FROM reviews WHERE reviews.sellerId = users.id AND reviews.deletedAt IS NULL)
+        FROM users
+        WHERE id = $1 AND deletedAt IS NULL`, id).Scan(
+		&stats.JoinedAt,
+		&stats.ProductCount,
+		&stats.CompletedSales,
+		&stats.RatingAverage,
+		&stats.RatingCount,
+	)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.UserStats{}, fmt.Errorf("User did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.UserStats{}, fmt.Errorf("Something went wrong")
+	default:
+		return &stats, nil
+	}
+}
+
+// soft delete, product dan bank account milik user ikut di soft delete dengan timestamp yang sama
+// supaya bisa di restore bareng (NOW() dalam satu transaksi selalu sama)
 func (s *Storage) DeleteUser(id string) error {
 
-	res, err := s.db.Exec(`
-        DELETE FROM users
-        WHERE id = $1;
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+        UPDATE users
+        SET deletedAt = NOW()
+        WHERE id = $1 AND deletedAt IS NULL;
         `, id)
 
 	if err != nil {
@@ -499,12 +786,33 @@ func (s *Storage) DeleteUser(id string) error {
 		return fmt.Errorf("User didnot exists")
 	}
 
-	return nil
+	if _, err := tx.Exec(`UPDATE products SET deletedAt = NOW() WHERE sellerId = $1 AND deletedAt IS NULL`, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE bankAccounts SET deletedAt = NOW() WHERE sellerId = $1 AND deletedAt IS NULL`, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE storefronts SET deletedAt = NOW() WHERE sellerId = $1 AND deletedAt IS NULL`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
+// product baru milik seller yang sedang vacation tidak bisa dibeli sampai vacation selesai
 func (s *Storage) CreateProduct(id, sellerId string, p *entities.Product) error {
 	tagArray := "{" + helper.ArrayToString(p.Tags) + "}"
-	_, err := s.db.Exec(`
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
         INSERT INTO products (
             id,
             name,
@@ -514,9 +822,16 @@ func (s *Storage) CreateProduct(id, sellerId string, p *entities.Product) error
             tags,
             isPurchaseable, 
             sellerId,
-            stock
+            stock,
+            descriptions,
+            categoryId,
+            status,
+            publishAt,
+            archiveAt,
+            sku,
+            vacationPaused
         )
-        VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+        VALUES ($1,$2,$3,$4,$5,$6,$7 AND NOT `+sellerOnVacation(`$8`)+`,$8,$9,$10,$11,COALESCE(NULLIF($12, ''), 'published'),$13,$14,$15,$7 AND `+sellerOnVacation(`$8`)+`)
         `,
 		id,
 		p.Name,
@@ -528,31 +843,54 @@ func (s *Storage) CreateProduct(id, sellerId string, p *entities.Product) error
 		sellerId,
 		p.Stock,
 		p.Descriptions,
+		sql.NullString{String: p.CategoryId, Valid: p.CategoryId != ""},
+		p.Status,
+		p.PublishAt,
+		p.ArchiveAt,
+		p.Sku,
 	)
 
 	if err != nil {
 		return err
 	}
 
-	return nil
+	if p.Stock != 0 {
+		if err := insertStockMovement(tx, &entities.StockMovement{
+			ProductId:  id,
+			Type:       "restock",
+			Delta:      p.Stock,
+			StockAfter: p.Stock,
+			Reason:     "initial stock",
+			ActorId:    sellerId,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
 }
 
-func (s *Storage) ListProducts(q types.ListQueryValid, userId string) (*[]entities.Product, error) {
+func (s *Storage) ListProducts(q types.ListQueryValid, userId string) (*[]entities.Product, *types.PageInfo, error) {
 
-	baseQuery, params := GenerateQueryListProduct(q, userId)
+	productQuery := NewProductQuery(q, userId)
+	baseQuery, params, keys := productQuery.ListSQL(q.Limit, q.Offset, q.Cursor)
 	var returnProducts []entities.Product
+	var sortValues [][]string
 	rows, err := s.db.Query(baseQuery, params...)
 
 	if err != nil {
 		log.Println("err inside ListProducts", err)
-		return &[]entities.Product{}, err
+		return &[]entities.Product{}, &types.PageInfo{}, err
 	}
 
 	defer rows.Close()
 
 	for rows.Next() {
 		var product entities.Product
-		if err := rows.Scan(
+		var categoryId sql.NullString
+		var publishAt, archiveAt sql.NullTime
+		values := make([]string, len(keys))
+		dest := []interface{}{
 			&product.ID,
 			&product.Name,
 			&product.Price,
@@ -566,25 +904,69 @@ func (s *Storage) ListProducts(q types.ListQueryValid, userId string) (*[]entiti
 			&product.UpdatedAt,
 			&product.DeletedAt,
 			&product.Descriptions,
-		); err != nil {
+			&categoryId,
+			&product.RatingAverage,
+			&product.RatingCount,
+			&product.Status,
+			&publishAt,
+			&archiveAt,
+			&product.Sku,
+		}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+
+		if err := rows.Scan(dest...); err != nil {
 
 			log.Println(err)
-			return &[]entities.Product{}, nil
+			return &[]entities.Product{}, &types.PageInfo{}, nil
 		}
 
+		product.CategoryId = categoryId.String
+		product.PublishAt, product.ArchiveAt = nullTimePtr(publishAt), nullTimePtr(archiveAt)
 		returnProducts = append(returnProducts, product)
+		sortValues = append(sortValues, values)
+	}
+
+	// query ambil limit+1 row untuk tahu masih ada halaman berikutnya atau tidak
+	hasMore := len(returnProducts) > q.Limit
+	if hasMore {
+		returnProducts = returnProducts[:q.Limit]
+		sortValues = sortValues[:q.Limit]
+	}
+
+	if q.Cursor != nil && q.Cursor.Backward {
+		for i, j := 0, len(returnProducts)-1; i < j; i, j = i+1, j-1 {
+			returnProducts[i], returnProducts[j] = returnProducts[j], returnProducts[i]
+		}
+		reverseSortValues(sortValues)
+	}
+
+	pageInfo := buildPageInfo(productQuery.SortSignature(), q.Cursor, hasMore, q.Offset > 0, sortValues)
+
+	if q.WithTotal {
+		countQuery, countParams := productQuery.CountSQL()
+		var total int
+		if err := s.db.QueryRow(countQuery, countParams...).Scan(&total); err != nil {
+			log.Println("err when counting products", err)
+			return &[]entities.Product{}, &types.PageInfo{}, err
+		}
+
+		pageInfo.Total = &total
 	}
 
 	if len(returnProducts) == 0 {
-		return &[]entities.Product{}, err
+		return &[]entities.Product{}, pageInfo, nil
 	}
 
-	return &returnProducts, nil
+	return &returnProducts, pageInfo, nil
 }
 
 func (s *Storage) GetProductById(id string) (*entities.Product, error) {
 
 	var product entities.Product
+	var categoryId sql.NullString
+	var publishAt, archiveAt sql.NullTime
 
 	err := s.db.QueryRow(`
         SELECT 
@@ -598,11 +980,18 @@ func (s *Storage) GetProductById(id string) (*entities.Product, error) {
             sellerId,
             stock,
             descriptions,
+            categoryId,
+            ratingAverage,
+            ratingCount,
+            status,
+            publishAt,
+            archiveAt,
+            sku,
             createdAt,
             updatedAt,
             deletedAt
         FROM products 
-        WHERE id = $1`, id).Scan(
+        WHERE id = $1 AND deletedAt IS NULL`, id).Scan(
 		&product.ID,
 		&product.Name,
 		&product.Price,
@@ -613,11 +1002,21 @@ func (s *Storage) GetProductById(id string) (*entities.Product, error) {
 		&product.SellerId,
 		&product.Stock,
 		&product.Descriptions,
+		&categoryId,
+		&product.RatingAverage,
+		&product.RatingCount,
+		&product.Status,
+		&publishAt,
+		&archiveAt,
+		&product.Sku,
 		&product.CreatedAt,
 		&product.UpdatedAt,
 		&product.DeletedAt,
 	)
 
+	product.CategoryId = categoryId.String
+	product.PublishAt, product.ArchiveAt = nullTimePtr(publishAt), nullTimePtr(archiveAt)
+
 	switch {
 	case err == sql.ErrNoRows:
 		return &entities.Product{}, fmt.Errorf("Product did not exists")
@@ -630,6 +1029,9 @@ func (s *Storage) GetProductById(id string) (*entities.Product, error) {
 
 }
 
+// selama seller vacation isPurchaseable tidak ikut diubah, nilai dari seller disimpan di vacationPaused
+// supaya product yang sengaja dimatikan seller tidak dihidupkan lagi saat vacation selesai.
+// imageUrl tidak diubah di sini, kolom itu hanya diisi dari usecase gambar product
 func (s *Storage) UpdateProduct(id string, p *entities.Product) error {
 
 	tagArray := "{" + helper.ArrayToString(p.Tags) + "}"
@@ -638,20 +1040,23 @@ func (s *Storage) UpdateProduct(id string, p *entities.Product) error {
         UPDATE products
         SET name = $1,
             price = $2,
-            imageUrl = $3,
-            stock = $4,
-            condition = $5,
-            tags = $6,
-            isPurchaseable = $7,
+            condition = $3,
+            tags = $4,
+            isPurchaseable = CASE WHEN `+sellerOnVacation(`products.sellerId`)+` THEN isPurchaseable ELSE $5 END,
+            vacationPaused = CASE WHEN `+sellerOnVacation(`products.sellerId`)+` THEN $5 ELSE vacationPaused END,
+            descriptions = $6,
+            categoryId = $7,
+            sku = $8,
             updatedAt = NOW()
         WHERE id = $9`,
 		p.Name,
 		p.Price,
-		p.ImageUrl,
-		p.Stock,
 		p.Condition,
 		tagArray,
 		p.IsPurchaseable,
+		p.Descriptions,
+		sql.NullString{String: p.CategoryId, Valid: p.CategoryId != ""},
+		p.Sku,
 		id)
 
 	if err != nil {
@@ -664,7 +1069,7 @@ func (s *Storage) UpdateProduct(id string, p *entities.Product) error {
 
 func (s *Storage) DeleteProduct(id string) error {
 
-	_, err := s.db.Exec(`DELETE FROM products WHERE id = $1;`, id)
+	_, err := s.db.Exec(`UPDATE products SET deletedAt = NOW() WHERE id = $1 AND deletedAt IS NULL;`, id)
 	if err != nil {
 
 		return err
@@ -677,7 +1082,7 @@ func (s *Storage) GetProductSeller(id string) (string, error) {
 
 	var sellerId string
 
-	if err := s.db.QueryRow(`SELECT sellerId FROM products WHERE id = $1`, id).Scan(&sellerId); err != nil {
+	if err := s.db.QueryRow(`SELECT sellerId FROM products WHERE id = $1 AND deletedAt IS NULL`, id).Scan(&sellerId); err != nil {
 
 		return "", err
 	}
@@ -685,22 +1090,13 @@ func (s *Storage) GetProductSeller(id string) (string, error) {
 	return sellerId, nil
 }
 
-func (s *Storage) UpdateStockProduct(id string, stock int) error {
+func GenerateQueryListTransaction(q types.ListQueryTransactionValid, userId string) (string, []interface{}, []sortKey) {
 
-	query := `UPDATE products 
-    SET stock = $1,
-        updatedAt = NOW()
-    WHERE id = $2`
-	_, err := s.db.Exec(query, stock, id)
-	if err != nil {
-		return err
+	keys := []sortKey{
+		{Expr: `transactions.` + q.Order, Cast: transactionSortCasts[q.Order], Desc: q.Sort != "asc"},
+		{Expr: `transactions.id`, Cast: `uuid`, Desc: q.Sort != "asc"},
 	}
 
-	return nil
-}
-
-func GenerateQueryListTransaction(q types.ListQueryTransactionValid, userId string) (string, []interface{}) {
-
 	baseQuery := `
     SELECT 
         transactions.id,
@@ -710,6 +1106,11 @@ func GenerateQueryListTransaction(q types.ListQueryTransactionValid, userId stri
         transactions.notes,
         transactions.createdAt,
         transactions.updatedAt,
+        transactions.variantId,
+        transactions.discountAmount,
+        COALESCE(transactions.subtotal, transactions.total),
+        transactions.couponDiscount,
+        COALESCE(transactions.warehouseId::text, ''),
 
         products.id,
         products.name,
@@ -725,7 +1126,7 @@ func GenerateQueryListTransaction(q types.ListQueryTransactionValid, userId stri
 
         buyers.id,
         buyers.name,
-        buyers.username
+        buyers.username` + generateSelectSortKeys(keys) + `
     FROM transactions 
     LEFT JOIN 
         products ON transactions.productId = products.id
@@ -735,121 +1136,95 @@ func GenerateQueryListTransaction(q types.ListQueryTransactionValid, userId stri
         users AS buyers ON transactions.buyerId = buyers.id
     WHERE `
 
-	queryIndex := 1
-	var params []interface{}
-
-	if q.Seller == true {
-		baseQuery += `(transactions.sellerId = $` + strconv.Itoa(queryIndex) + `) AND `
-		params = append(params, userId)
-		queryIndex += 1
-	} else {
-		baseQuery += `(transactions.buyerId = $` + strconv.Itoa(queryIndex) + `) AND `
-		params = append(params, userId)
-		queryIndex += 1
-	}
+	where, params, queryIndex := generateWhereListTransaction(q, userId)
+	baseQuery += where
 
-	if q.Search != "" {
-		baseQuery += `(transactions.id = $` + strconv.Itoa(queryIndex) + `) AND `
-		params = append(params, q.Search)
-		queryIndex += 1
+	backward := false
+	if validCursor(q.Cursor, keys) {
+		condition, cursorParams, nextIndex := generateKeysetCondition(keys, q.Cursor, queryIndex)
+		baseQuery += ` AND ` + condition
+		params = append(params, cursorParams...)
+		queryIndex = nextIndex
+		backward = q.Cursor.Backward
 	}
 
-	baseQuery = baseQuery[:len(baseQuery)-5]
-	baseQuery += ` ORDER BY ` + q.Order
-
-	if q.Sort == "asc" {
-		baseQuery += ` ASC `
-	} else {
-		baseQuery += ` DESC `
-	}
+	baseQuery += generateOrderBy(keys, backward)
 
 	baseQuery += ` LIMIT $` + strconv.Itoa(queryIndex)
 	queryIndex += 1
-	params = append(params, q.Limit)
+	params = append(params, q.Limit+1)
+
+	// offset hanya dipakai kalau tidak pakai cursor
+	if q.Cursor == nil {
+		baseQuery += ` OFFSET $` + strconv.Itoa(queryIndex)
+		params = append(params, q.Offset)
+	}
 
-	baseQuery += ` OFFSET $` + strconv.Itoa(queryIndex)
-	params = append(params, q.Offset)
 	baseQuery += `;`
 
-	return baseQuery, params
+	return baseQuery, params, keys
 }
 
-func GenerateQueryListProduct(q types.ListQueryValid, userId string) (string, []interface{}) {
+var transactionSortCasts = map[string]string{
+	"createdAt": "timestamp",
+	"total":     "numeric",
+}
 
-	baseQuery := `
-    SELECT 
-        id,
-        name,
-        price,
-        imageUrl,
-        condition,
-        tags,
-        isPurchaseable,
-        sellerId,
-        stock,
-        createdAt,
-        updatedAt,
-        deletedAt,
-        descriptions 
-    FROM products WHERE `
+func generateWhereListTransaction(q types.ListQueryTransactionValid, userId string) (string, []interface{}, int) {
+
+	baseQuery := ""
 	queryIndex := 1
 	var params []interface{}
 
-	if q.UserOnly == "true" && userId != "" {
-		baseQuery += `(sellerId = $` + strconv.Itoa(queryIndex) + `) AND `
+	if q.Seller == true {
+		baseQuery += `(transactions.sellerId = $` + strconv.Itoa(queryIndex) + `) AND `
+		params = append(params, userId)
+		queryIndex += 1
+	} else {
+		baseQuery += `(transactions.buyerId = $` + strconv.Itoa(queryIndex) + `) AND `
 		params = append(params, userId)
 		queryIndex += 1
 	}
 
-	baseQuery += `(condition = $` + strconv.Itoa(queryIndex) + `) AND `
-	params = append(params, q.Condition)
-	queryIndex += 1
-
-	if len(q.Tags) > 0 {
-		baseQuery += `(tags IN($` + strconv.Itoa(queryIndex) + `)) AND `
-		params = append(params, "{"+helper.ArrayToString(q.Tags)+"}")
+	if q.Search != "" {
+		baseQuery += `(transactions.id = $` + strconv.Itoa(queryIndex) + `) AND `
+		params = append(params, q.Search)
 		queryIndex += 1
 	}
 
-	if q.ShowEmptyStock == "false" {
-		baseQuery += `(stock > 0) AND `
-	} else {
-		baseQuery += `(stock = 0) AND `
-	}
+	return baseQuery[:len(baseQuery)-5], params, queryIndex
+}
 
-	baseQuery += `(price >= $` + strconv.Itoa(queryIndex) + `) AND `
-	params = append(params, q.MinPrice)
-	queryIndex += 1
+// autocomplete nama product berdasarkan prefix, diurutkan berdasarkan relevansi
+func (s *Storage) SuggestProducts(search string, limit int) (*[]ProductSuggestion, error) {
 
-	if q.MaxPrice > 0 {
-		baseQuery += `(price <= $` + strconv.Itoa(queryIndex) + `) AND `
-		params = append(params, q.MaxPrice)
-		queryIndex += 1
+	suggestions := []ProductSuggestion{}
+	tsQuery := BuildPrefixTsQuery(search)
+	if tsQuery == "" {
+		return &suggestions, nil
 	}
 
-	if q.Search != "" {
-		baseQuery += `(name LIKE $` + strconv.Itoa(queryIndex) + `) AND `
-		params = append(params, "%"+q.Search+"%")
-		queryIndex += 1
+	rows, err := s.db.Query(`
+        SELECT id, name 
+        FROM products 
+        WHERE searchVector @@ to_tsquery('simple', $1) AND deletedAt IS NULL AND status = 'published'
+        ORDER BY ts_rank(searchVector, to_tsquery('simple', $1)) DESC, name ASC
+        LIMIT $2`, tsQuery, limit)
+	if err != nil {
+		log.Println("err inside SuggestProducts", err)
+		return &suggestions, err
 	}
 
-	baseQuery = baseQuery[:len(baseQuery)-5]
+	defer rows.Close()
 
-	baseQuery += ` ORDER BY ` + q.Order
+	for rows.Next() {
+		var suggestion ProductSuggestion
+		if err := rows.Scan(&suggestion.ID, &suggestion.Name); err != nil {
+			return &[]ProductSuggestion{}, err
+		}
 
-	if q.Sort == "asc" {
-		baseQuery += ` ASC `
-	} else {
-		baseQuery += ` DESC `
+		suggestions = append(suggestions, suggestion)
 	}
 
-	baseQuery += ` LIMIT $` + strconv.Itoa(queryIndex)
-	queryIndex += 1
-	params = append(params, q.Limit)
-
-	baseQuery += ` OFFSET $` + strconv.Itoa(queryIndex) + `;`
-	params = append(params, q.Offset)
-	baseQuery += `;`
-
-	return baseQuery, params
+	return &suggestions, nil
 }
@@ -0,0 +1,266 @@
+package datastore
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+var ErrStorefrontNotFound = fmt.Errorf("Storefront did not exists")
+
+const storefrontColumns = `
+        id,
+        sellerId,
+        name,
+        slug,
+        description,
+        logoUrl,
+        bannerUrl,
+        location,
+        openingHours,
+        vacationMode,
+        vacationMessage,
+        createdAt,
+        updatedAt,
+        deletedAt`
+
+// kondisi sql "seller sedang vacation", sellerExpr kolom/placeholder sellerId
+func sellerOnVacation(sellerExpr string) string {
+
+	return `EXISTS (SELECT 1 FROM storefronts WHERE storefronts.sellerId = ` + sellerExpr + ` AND storefronts.vacationMode AND storefronts.deletedAt IS NULL)`
+}
+
+func (s *Storage) CreateStorefront(id string, f *entities.Storefront) error {
+
+	openingHours, err := json.Marshal(f.OpeningHours)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        INSERT INTO storefronts (
+            id,
+            sellerId,
+            name,
+            slug,
+            description,
+            logoUrl,
+            bannerUrl,
+            location,
+            openingHours,
+            vacationMode,
+            vacationMessage
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11)`,
+		id,
+		f.SellerId,
+		f.Name,
+		f.Slug,
+		f.Description,
+		f.LogoUrl,
+		f.BannerUrl,
+		f.Location,
+		string(openingHours),
+		f.VacationMode,
+		f.VacationMessage,
+	)
+	if err != nil {
+		return err
+	}
+
+	if f.VacationMode {
+		if err := setProductsVacation(tx, f.SellerId, true); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) GetStorefrontBySeller(sellerId string) (*entities.Storefront, error) {
+
+	row := s.db.QueryRow(`SELECT `+storefrontColumns+` FROM storefronts WHERE sellerId = $1 AND deletedAt IS NULL`, sellerId)
+	storefront, err := scanStorefront(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.Storefront{}, ErrStorefrontNotFound
+	case err != nil:
+		log.Println(err)
+		return &entities.Storefront{}, fmt.Errorf("Something went wrong")
+	default:
+		return storefront, nil
+	}
+}
+
+func (s *Storage) GetStorefrontBySlug(slug string) (*entities.Storefront, error) {
+
+	row := s.db.QueryRow(`SELECT `+storefrontColumns+` FROM storefronts WHERE slug = $1 AND deletedAt IS NULL`, slug)
+	storefront, err := scanStorefront(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.Storefront{}, ErrStorefrontNotFound
+	case err != nil:
+		log.Println(err)
+		return &entities.Storefront{}, fmt.Errorf("Something went wrong")
+	default:
+		return storefront, nil
+	}
+}
+
+// kalau vacationMode berubah, isPurchaseable semua product seller ikut diubah dalam transaksi yang sama
+func (s *Storage) UpdateStorefront(id string, f *entities.Storefront) error {
+
+	openingHours, err := json.Marshal(f.OpeningHours)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	var vacationMode bool
+	if err := tx.QueryRow(`SELECT vacationMode FROM storefronts WHERE id = $1 FOR UPDATE`, id).Scan(&vacationMode); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`
+        UPDATE storefronts
+        SET name = $1,
+            slug = $2,
+            description = $3,
+            logoUrl = $4,
+            bannerUrl = $5,
+            location = $6,
+            openingHours = $7,
+            vacationMode = $8,
+            vacationMessage = $9,
+            updatedAt = NOW()
+        WHERE id = $10`,
+		f.Name,
+		f.Slug,
+		f.Description,
+		f.LogoUrl,
+		f.BannerUrl,
+		f.Location,
+		string(openingHours),
+		f.VacationMode,
+		f.VacationMessage,
+		id,
+	)
+	if err != nil {
+		return err
+	}
+
+	if vacationMode != f.VacationMode {
+		if err := setProductsVacation(tx, f.SellerId, f.VacationMode); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ringkasan product published dan rating dari semua review product seller
+func (s *Storage) GetStorefrontStats(sellerId string) (*entities.StorefrontStats, error) {
+
+	stats := entities.StorefrontStats{RatingCounts: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+
+	err := s.db.QueryRow(`
+        SELECT COUNT(*)
+        FROM products
+        WHERE sellerId = $1 AND status = 'published' AND deletedAt IS NULL`, sellerId).Scan(&stats.ProductCount)
+	if err != nil {
+		return &stats, err
+	}
+
+	rows, err := s.db.Query(`
+        SELECT reviews.rating, COUNT(*)
+        FROM reviews
+        JOIN products ON reviews.productId = products.id
+        WHERE products.sellerId = $1 AND products.deletedAt IS NULL AND reviews.deletedAt IS NULL
+        GROUP BY reviews.rating`, sellerId)
+	if err != nil {
+		return &stats, err
+	}
+
+	defer rows.Close()
+
+	total := 0
+	for rows.Next() {
+		var rating, count int
+		if err := rows.Scan(&rating, &count); err != nil {
+			return &stats, err
+		}
+
+		stats.RatingCounts[rating] = count
+		stats.RatingCount += count
+		total += rating * count
+	}
+
+	if stats.RatingCount > 0 {
+		stats.RatingAverage = float64(total) / float64(stats.RatingCount)
+	}
+
+	return &stats, nil
+}
+
+// vacation on: product yang bisa dibeli ditandai vacationPaused lalu dimatikan,
+// vacation off: hanya product yang ditandai yang dikembalikan
+func setProductsVacation(tx *sql.Tx, sellerId string, vacation bool) error {
+
+	query := `UPDATE products SET isPurchaseable = FALSE, vacationPaused = TRUE WHERE sellerId = $1 AND isPurchaseable`
+	if !vacation {
+		query = `UPDATE products SET isPurchaseable = TRUE, vacationPaused = FALSE WHERE sellerId = $1 AND vacationPaused`
+	}
+
+	_, err := tx.Exec(query, sellerId)
+
+	return err
+}
+
+func scanStorefront(row rowScanner) (*entities.Storefront, error) {
+
+	var storefront entities.Storefront
+	var openingHours []byte
+
+	err := row.Scan(
+		&storefront.ID,
+		&storefront.SellerId,
+		&storefront.Name,
+		&storefront.Slug,
+		&storefront.Description,
+		&storefront.LogoUrl,
+		&storefront.BannerUrl,
+		&storefront.Location,
+		&openingHours,
+		&storefront.VacationMode,
+		&storefront.VacationMessage,
+		&storefront.CreatedAt,
+		&storefront.UpdatedAt,
+		&storefront.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(openingHours, &storefront.OpeningHours); err != nil {
+		return nil, err
+	}
+
+	return &storefront, nil
+}
@@ -0,0 +1,201 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// kode 2fa salah sebanyak ini berturut-turut, verifikasi dikunci sementara
+	MAXTOTPATTEMPTS = 5
+	TOTPLOCKOUT     = 15 * time.Minute
+)
+
+var (
+	ErrTOTPInvalid        = fmt.Errorf("Invalid two-factor code")
+	ErrTOTPAlreadyEnabled = fmt.Errorf("Two-factor authentication already enabled")
+)
+
+// secret baru untuk enrol, hanya bisa selama 2fa belum aktif. secret lama yang belum dikonfirmasi ditimpa
+func (s *Storage) SetTOTPSecret(id, secret string) error {
+
+	res, err := s.db.Exec(`
+        UPDATE users
+        SET totpSecret = $1,
+            totpLastStep = 0,
+            updatedAt = NOW()
+        WHERE id = $2 AND deletedAt IS NULL AND totpEnabledAt IS NULL`, secret, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected < 1 {
+		return ErrTOTPAlreadyEnabled
+	}
+
+	return nil
+}
+
+// aktifkan 2fa setelah kode pertama dari authenticator cocok, step kode tsb langsung ditandai terpakai
+func (s *Storage) EnableTOTP(id string, step int64, recoveryCodeHashes []string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+        UPDATE users
+        SET totpEnabledAt = NOW(),
+            totpLastStep = $1,
+            totpFailures = 0,
+            totpLockedUntil = NULL,
+            updatedAt = NOW()
+        WHERE id = $2 AND deletedAt IS NULL AND totpEnabledAt IS NULL AND totpSecret <> ''`, step, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected < 1 {
+		return ErrTOTPAlreadyEnabled
+	}
+
+	if err := replaceRecoveryCodes(tx, id, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) DisableTOTP(id string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+        UPDATE users
+        SET totpSecret = '',
+            totpEnabledAt = NULL,
+            totpLastStep = 0,
+            totpFailures = 0,
+            totpLockedUntil = NULL,
+            updatedAt = NOW()
+        WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM recoveryCodes WHERE userId = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// kode TOTP hanya boleh dipakai sekali, step yang sama atau lebih lama ditolak
+func (s *Storage) UseTOTPStep(id string, step int64) error {
+
+	res, err := s.db.Exec(`
+        UPDATE users
+        SET totpLastStep = $1,
+            totpFailures = 0,
+            totpLockedUntil = NULL
+        WHERE id = $2 AND totpLastStep < $1`, step, id)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected < 1 {
+		return ErrTOTPInvalid
+	}
+
+	return nil
+}
+
+// recovery code sekali pakai
+func (s *Storage) UseRecoveryCode(id, codeHash string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`UPDATE recoveryCodes SET usedAt = NOW() WHERE userId = $1 AND codeHash = $2 AND usedAt IS NULL`, id, codeHash)
+	if err != nil {
+		return err
+	}
+
+	if affected, err := res.RowsAffected(); err != nil || affected < 1 {
+		return ErrTOTPInvalid
+	}
+
+	if _, err := tx.Exec(`UPDATE users SET totpFailures = 0, totpLockedUntil = NULL WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// kode salah ke-MAXTOTPATTEMPTS mengunci verifikasi selama TOTPLOCKOUT dan counter mulai dari 0 lagi
+func (s *Storage) RecordTOTPFailure(id string) error {
+
+	_, err := s.db.Exec(`
+        UPDATE users
+        SET totpLockedUntil = CASE WHEN totpFailures + 1 >= $1 THEN NOW() + ($2 * INTERVAL '1 second') ELSE totpLockedUntil END,
+            totpFailures = CASE WHEN totpFailures + 1 >= $1 THEN 0 ELSE totpFailures + 1 END
+        WHERE id = $3`, MAXTOTPATTEMPTS, int(TOTPLOCKOUT.Seconds()), id)
+
+	return err
+}
+
+// recovery code lama (terpakai atau belum) diganti semua
+func (s *Storage) ReplaceRecoveryCodes(id string, recoveryCodeHashes []string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if err := replaceRecoveryCodes(tx, id, recoveryCodeHashes); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// jumlah recovery code yang belum dipakai
+func (s *Storage) CountRecoveryCodes(id string) (int, error) {
+
+	var count int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM recoveryCodes WHERE userId = $1 AND usedAt IS NULL`, id).Scan(&count)
+
+	return count, err
+}
+
+func replaceRecoveryCodes(tx *sql.Tx, id string, recoveryCodeHashes []string) error {
+
+	if _, err := tx.Exec(`DELETE FROM recoveryCodes WHERE userId = $1`, id); err != nil {
+		return err
+	}
+
+	for _, codeHash := range recoveryCodeHashes {
+		if _, err := tx.Exec(`INSERT INTO recoveryCodes (id, userId, codeHash) VALUES ($1, $2, $3)`, uuid.NewString(), id, codeHash); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
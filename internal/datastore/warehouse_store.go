@@ -0,0 +1,359 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/google/uuid"
+)
+
+var ErrWarehouseNotEmpty = fmt.Errorf("Warehouse still has stock, transfer it first")
+
+const warehouseColumns = `
+        id,
+        sellerId,
+        name,
+        address,
+        city,
+        latitude,
+        longitude,
+        priority,
+        isActive,
+        createdAt,
+        updatedAt,
+        deletedAt`
+
+func (s *Storage) CreateWarehouse(id string, w *entities.Warehouse) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO warehouses (
+            id,
+            sellerId,
+            name,
+            address,
+            city,
+            latitude,
+            longitude,
+            priority,
+            isActive
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9)`,
+		id,
+		w.SellerId,
+		w.Name,
+		w.Address,
+		w.City,
+		w.Latitude,
+		w.Longitude,
+		w.Priority,
+		w.IsActive,
+	)
+
+	return err
+}
+
+func (s *Storage) GetWarehouse(id string) (*entities.Warehouse, error) {
+
+	row := s.db.QueryRow(`SELECT `+warehouseColumns+` FROM warehouses WHERE id = $1 AND deletedAt IS NULL`, id)
+	warehouse, err := scanWarehouse(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.Warehouse{}, fmt.Errorf("Warehouse did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.Warehouse{}, fmt.Errorf("Something went wrong")
+	default:
+		return warehouse, nil
+	}
+}
+
+func (s *Storage) ListWarehouses(sellerId string) (*[]entities.Warehouse, error) {
+
+	returnWarehouses := []entities.Warehouse{}
+	rows, err := s.db.Query(`
+        SELECT `+warehouseColumns+`
+        FROM warehouses
+        WHERE sellerId = $1 AND deletedAt IS NULL
+        ORDER BY priority ASC, createdAt ASC`, sellerId)
+	if err != nil {
+		log.Println("err inside ListWarehouses", err)
+		return &returnWarehouses, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		warehouse, err := scanWarehouse(rows)
+		if err != nil {
+			return &[]entities.Warehouse{}, err
+		}
+
+		returnWarehouses = append(returnWarehouses, *warehouse)
+	}
+
+	return &returnWarehouses, nil
+}
+
+func (s *Storage) UpdateWarehouse(id string, w *entities.Warehouse) error {
+
+	_, err := s.db.Exec(`
+        UPDATE warehouses
+        SET name = $1,
+            address = $2,
+            city = $3,
+            latitude = $4,
+            longitude = $5,
+            priority = $6,
+            isActive = $7,
+            updatedAt = NOW()
+        WHERE id = $8`,
+		w.Name,
+		w.Address,
+		w.City,
+		w.Latitude,
+		w.Longitude,
+		w.Priority,
+		w.IsActive,
+		id,
+	)
+
+	return err
+}
+
+// warehouse hanya bisa dihapus kalau stock nya sudah kosong (sudah ditransfer),
+// ledger yang mereferensikan warehouse tetap utuh karena hanya soft delete
+func (s *Storage) DeleteWarehouse(id string) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`SELECT id FROM warehouses WHERE id = $1 FOR UPDATE`, id); err != nil {
+		return err
+	}
+
+	var stock int
+	if err := tx.QueryRow(`SELECT COALESCE(SUM(stock), 0) FROM warehouseStocks WHERE warehouseId = $1`, id).Scan(&stock); err != nil {
+		return err
+	}
+
+	if stock > 0 {
+		return ErrWarehouseNotEmpty
+	}
+
+	if _, err := tx.Exec(`DELETE FROM warehouseStocks WHERE warehouseId = $1`, id); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE warehouses SET deletedAt = NOW(), updatedAt = NOW() WHERE id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// stock product (dan variantnya) per warehouse
+func (s *Storage) ListWarehouseStocks(productId string) (*[]entities.WarehouseStock, error) {
+
+	returnStocks := []entities.WarehouseStock{}
+	rows, err := s.db.Query(`
+        SELECT
+            warehouseStocks.warehouseId,
+            warehouses.name,
+            warehouseStocks.productId,
+            warehouseStocks.variantId,
+            warehouseStocks.stock
+        FROM warehouseStocks
+        JOIN warehouses ON warehouseStocks.warehouseId = warehouses.id
+        WHERE warehouseStocks.productId = $1 AND warehouses.deletedAt IS NULL
+        ORDER BY warehouses.priority ASC, warehouses.name ASC`, productId)
+	if err != nil {
+		log.Println("err inside ListWarehouseStocks", err)
+		return &returnStocks, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var stock entities.WarehouseStock
+		var variantId sql.NullString
+
+		if err := rows.Scan(
+			&stock.WarehouseId,
+			&stock.WarehouseName,
+			&stock.ProductId,
+			&variantId,
+			&stock.Stock,
+		); err != nil {
+			return &[]entities.WarehouseStock{}, err
+		}
+
+		stock.VariantId = variantId.String
+		returnStocks = append(returnStocks, stock)
+	}
+
+	return &returnStocks, nil
+}
+
+// pindah stock antar warehouse, stock total product tidak berubah.
+// dicatat sebagai 2 movement transfer (keluar dan masuk), warehouse kosong = stock yang belum dialokasikan
+func (s *Storage) TransferStock(t *entities.StockTransfer) error {
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback()
+
+	for _, side := range []struct {
+		warehouseId string
+		delta       int
+	}{
+		{t.FromWarehouseId, -t.Quantity},
+		{t.ToWarehouseId, t.Quantity},
+	} {
+		if side.warehouseId == "" {
+			continue
+		}
+
+		stockAfter, err := applyWarehouseStockDelta(tx, side.warehouseId, t.ProductId, t.VariantId, side.delta)
+		switch {
+		case err == sql.ErrNoRows:
+			return ErrInsufficientStock
+		case err != nil:
+			return err
+		}
+
+		if err := insertStockMovement(tx, &entities.StockMovement{
+			ProductId:   t.ProductId,
+			VariantId:   t.VariantId,
+			WarehouseId: side.warehouseId,
+			Type:        "transfer",
+			Delta:       side.delta,
+			StockAfter:  stockAfter,
+			Reason:      t.Reason,
+			ActorId:     t.ActorId,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// alokasi dari stock yang belum dialokasikan tidak boleh melebihi stock total
+	if t.FromWarehouseId == "" {
+		var allocated bool
+		err := tx.QueryRow(`
+            SELECT
+                (CASE WHEN $2::uuid IS NULL
+                    THEN (SELECT stock FROM products WHERE id = $1)
+                    ELSE (SELECT stock FROM productVariants WHERE id = $2)
+                END) >= (SELECT COALESCE(SUM(stock), 0) FROM warehouseStocks WHERE productId = $1 AND variantId IS NOT DISTINCT FROM $2)`,
+			t.ProductId, sql.NullString{String: t.VariantId, Valid: t.VariantId != ""}).Scan(&allocated)
+		if err != nil {
+			return err
+		}
+
+		if !allocated {
+			return ErrInsufficientStock
+		}
+	}
+
+	return tx.Commit()
+}
+
+// warehouse aktif yang stock nya cukup, yang terdekat dengan lokasi buyer dulu (kalau ada)
+// lalu berdasarkan prioritas. return "" kalau tidak ada warehouse yang cukup
+func (s *Storage) ChooseFulfilmentWarehouse(productId, variantId string, quantity int, latitude, longitude *float64) (string, error) {
+
+	var warehouseId string
+	err := s.db.QueryRow(`
+        SELECT warehouses.id
+        FROM warehouseStocks
+        JOIN warehouses ON warehouseStocks.warehouseId = warehouses.id
+        WHERE warehouseStocks.productId = $1
+            AND warehouseStocks.variantId IS NOT DISTINCT FROM $2
+            AND warehouseStocks.stock >= $3
+            AND warehouses.isActive = TRUE
+            AND warehouses.deletedAt IS NULL
+        ORDER BY
+            POWER(warehouses.latitude - $4, 2) + POWER((warehouses.longitude - $5) * COS(RADIANS($4)), 2) ASC NULLS LAST,
+            warehouses.priority ASC,
+            warehouseStocks.stock DESC
+        LIMIT 1`,
+		productId,
+		sql.NullString{String: variantId, Valid: variantId != ""},
+		quantity,
+		latitude,
+		longitude,
+	).Scan(&warehouseId)
+
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+
+	return warehouseId, err
+}
+
+// ubah stock satu warehouse saja (stock total tidak ikut), row dibuat kalau belum ada.
+// return sql.ErrNoRows kalau stock warehouse tidak cukup
+func applyWarehouseStockDelta(tx *sql.Tx, warehouseId, productId, variantId string, delta int) (int, error) {
+
+	var stock int
+	nullVariantId := sql.NullString{String: variantId, Valid: variantId != ""}
+
+	if delta >= 0 {
+		err := tx.QueryRow(`
+            INSERT INTO warehouseStocks (id, warehouseId, productId, variantId, stock)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (warehouseId, productId, COALESCE(variantId, '00000000-0000-0000-0000-000000000000'))
+            DO UPDATE SET stock = warehouseStocks.stock + EXCLUDED.stock, updatedAt = NOW()
+            RETURNING stock`, uuid.NewString(), warehouseId, productId, nullVariantId, delta).Scan(&stock)
+
+		return stock, err
+	}
+
+	err := tx.QueryRow(`
+        UPDATE warehouseStocks
+        SET stock = stock + $1,
+            updatedAt = NOW()
+        WHERE warehouseId = $2 AND productId = $3 AND variantId IS NOT DISTINCT FROM $4 AND stock + $1 >= 0
+        RETURNING stock`, delta, warehouseId, productId, nullVariantId).Scan(&stock)
+
+	return stock, err
+}
+
+func scanWarehouse(row rowScanner) (*entities.Warehouse, error) {
+
+	var warehouse entities.Warehouse
+	var latitude, longitude sql.NullFloat64
+
+	err := row.Scan(
+		&warehouse.ID,
+		&warehouse.SellerId,
+		&warehouse.Name,
+		&warehouse.Address,
+		&warehouse.City,
+		&latitude,
+		&longitude,
+		&warehouse.Priority,
+		&warehouse.IsActive,
+		&warehouse.CreatedAt,
+		&warehouse.UpdatedAt,
+		&warehouse.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if latitude.Valid && longitude.Valid {
+		warehouse.Latitude = &latitude.Float64
+		warehouse.Longitude = &longitude.Float64
+	}
+
+	return &warehouse, nil
+}
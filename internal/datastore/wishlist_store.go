@@ -0,0 +1,261 @@
+package datastore
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+const wishlistColumns = `
+        wishlists.id,
+        wishlists.userId,
+        wishlists.name,
+        wishlists.shareToken,
+        (SELECT COUNT(*) FROM wishlistItems WHERE wishlistItems.wishlistId = wishlists.id),
+        wishlists.createdAt,
+        wishlists.updatedAt,
+        wishlists.deletedAt`
+
+func (s *Storage) CreateWishlist(id, userId, name string) error {
+
+	_, err := s.db.Exec(`INSERT INTO wishlists (id, userId, name) VALUES ($1,$2,$3)`, id, userId, name)
+
+	return err
+}
+
+func (s *Storage) GetWishlist(id string) (*entities.Wishlist, error) {
+
+	row := s.db.QueryRow(`SELECT `+wishlistColumns+` FROM wishlists WHERE id = $1`, id)
+
+	return handleWishlistRow(row)
+}
+
+func (s *Storage) GetWishlistByShareToken(token string) (*entities.Wishlist, error) {
+
+	row := s.db.QueryRow(`SELECT `+wishlistColumns+` FROM wishlists WHERE shareToken = $1`, token)
+
+	return handleWishlistRow(row)
+}
+
+func (s *Storage) ListWishlists(userId string) (*[]entities.Wishlist, error) {
+
+	returnWishlists := []entities.Wishlist{}
+	rows, err := s.db.Query(`
+        SELECT `+wishlistColumns+`
+        FROM wishlists
+        WHERE userId = $1
+        ORDER BY createdAt ASC`, userId)
+	if err != nil {
+		log.Println("err inside ListWishlists", err)
+		return &returnWishlists, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		wishlist, err := scanWishlist(rows)
+		if err != nil {
+			return &[]entities.Wishlist{}, err
+		}
+
+		returnWishlists = append(returnWishlists, *wishlist)
+	}
+
+	return &returnWishlists, nil
+}
+
+func (s *Storage) UpdateWishlist(id, name string) error {
+
+	_, err := s.db.Exec(`UPDATE wishlists SET name = $1, updatedAt = NOW() WHERE id = $2`, name, id)
+
+	return err
+}
+
+// token kosong = berhenti membagikan wishlist
+func (s *Storage) SetWishlistShareToken(id, token string) error {
+
+	_, err := s.db.Exec(`
+        UPDATE wishlists
+        SET shareToken = $1,
+            updatedAt = NOW()
+        WHERE id = $2`, sql.NullString{String: token, Valid: token != ""}, id)
+
+	return err
+}
+
+// item ikut terhapus (ON DELETE CASCADE)
+func (s *Storage) DeleteWishlist(id string) error {
+
+	_, err := s.db.Exec(`DELETE FROM wishlists WHERE id = $1`, id)
+
+	return err
+}
+
+func (s *Storage) AddWishlistItem(id, wishlistId string, item *entities.WishlistItem) error {
+
+	_, err := s.db.Exec(`
+        INSERT INTO wishlistItems (
+            id,
+            wishlistId,
+            productId,
+            variantId,
+            quantity,
+            priceAtAdd,
+            stockAtAdd
+        ) VALUES ($1,$2,$3,$4,$5,$6,$7)`,
+		id,
+		wishlistId,
+		item.ProductId,
+		sql.NullString{String: item.VariantId, Valid: item.VariantId != ""},
+		item.Quantity,
+		item.PriceAtAdd,
+		item.StockAtAdd,
+	)
+
+	return err
+}
+
+// item beserta harga dan stock terkini (harga/stock variant kalau item punya variant),
+// item dari product yang sudah dihapus tidak ditampilkan
+func (s *Storage) ListWishlistItems(wishlistId string) (*[]entities.WishlistItem, error) {
+
+	returnItems := []entities.WishlistItem{}
+	rows, err := s.db.Query(`
+        SELECT
+            wishlistItems.id,
+            wishlistItems.wishlistId,
+            wishlistItems.productId,
+            wishlistItems.variantId,
+            wishlistItems.quantity,
+            wishlistItems.priceAtAdd,
+            wishlistItems.stockAtAdd,
+            COALESCE(productVariants.price, products.price),
+            COALESCE(productVariants.stock, products.stock),
+            products.id,
+            products.name,
+            products.price,
+            products.imageUrl,
+            products.stock,
+            products.condition,
+            products.tags,
+            products.isPurchaseable,
+            products.descriptions,
+            products.status,
+            wishlistItems.createdAt,
+            wishlistItems.updatedAt,
+            wishlistItems.deletedAt
+        FROM wishlistItems
+        JOIN products ON products.id = wishlistItems.productId
+        LEFT JOIN productVariants ON productVariants.id = wishlistItems.variantId
+        WHERE wishlistItems.wishlistId = $1 AND products.deletedAt IS NULL
+        ORDER BY wishlistItems.createdAt ASC`, wishlistId)
+	if err != nil {
+		log.Println("err inside ListWishlistItems", err)
+		return &returnItems, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var item entities.WishlistItem
+		var variantId sql.NullString
+		var status string
+
+		err := rows.Scan(
+			&item.ID,
+			&item.WishlistId,
+			&item.ProductId,
+			&variantId,
+			&item.Quantity,
+			&item.PriceAtAdd,
+			&item.StockAtAdd,
+			&item.CurrentPrice,
+			&item.CurrentStock,
+			&item.Product.ID,
+			&item.Product.Name,
+			&item.Product.Price,
+			&item.Product.ImageUrl,
+			&item.Product.Stock,
+			&item.Product.Condition,
+			&item.Product.Tags,
+			&item.Product.IsPurchaseable,
+			&item.Product.Descriptions,
+			&status,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&item.DeletedAt,
+		)
+		if err != nil {
+			return &[]entities.WishlistItem{}, err
+		}
+
+		item.VariantId = variantId.String
+		item.PriceChange = item.CurrentPrice - item.PriceAtAdd
+		item.BackInStock = item.StockAtAdd == 0 && item.CurrentStock > 0
+		item.Available = status == "published" && item.Product.IsPurchaseable && item.CurrentStock >= item.Quantity
+		returnItems = append(returnItems, item)
+	}
+
+	return &returnItems, nil
+}
+
+// return sql.ErrNoRows kalau item tidak ada di wishlist tersebut
+func (s *Storage) DeleteWishlistItem(wishlistId, itemId string) error {
+
+	result, err := s.db.Exec(`DELETE FROM wishlistItems WHERE id = $1 AND wishlistId = $2`, itemId, wishlistId)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func handleWishlistRow(row rowScanner) (*entities.Wishlist, error) {
+
+	wishlist, err := scanWishlist(row)
+
+	switch {
+	case err == sql.ErrNoRows:
+		return &entities.Wishlist{}, fmt.Errorf("Wishlist did not exists")
+	case err != nil:
+		log.Println(err)
+		return &entities.Wishlist{}, fmt.Errorf("Something went wrong")
+	default:
+		return wishlist, nil
+	}
+}
+
+func scanWishlist(row rowScanner) (*entities.Wishlist, error) {
+
+	var wishlist entities.Wishlist
+	var shareToken sql.NullString
+
+	err := row.Scan(
+		&wishlist.ID,
+		&wishlist.UserId,
+		&wishlist.Name,
+		&shareToken,
+		&wishlist.ItemCount,
+		&wishlist.CreatedAt,
+		&wishlist.UpdatedAt,
+		&wishlist.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	wishlist.ShareToken = shareToken.String
+
+	return &wishlist, nil
+}
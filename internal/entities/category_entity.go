@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Category struct {
+	ID       string `json:"id"`
+	ParentId string `json:"parentId"` // kosong = root category
+	Name     string `json:"name"`
+	Slug     string `json:"slug"`
+	Position int    `json:"position"`
+
+	Children []Category        `json:"children,omitempty"`
+	Path     []CategoryMinimal `json:"path,omitempty"` // breadcrumb dari root sampai category ini
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+type CategoryMinimal struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
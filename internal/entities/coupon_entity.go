@@ -0,0 +1,47 @@
+package entities
+
+import (
+	"database/sql"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type Coupon struct {
+	ID           string         `json:"id"`
+	Code         string         `json:"code"`
+	SellerId     string         `json:"sellerId"` // kosong = coupon platform, berlaku untuk semua seller
+	Type         string         `json:"type"`     // "percentage"|"fixed"
+	Value        float64        `json:"value"`
+	MaxDiscount  float64        `json:"maxDiscount"` // batas potongan coupon percentage, 0 = tanpa batas
+	MinSpend     float64        `json:"minSpend"`
+	ProductIds   pq.StringArray `json:"productIds"`  // kosong = semua product
+	CategoryIds  pq.StringArray `json:"categoryIds"` // termasuk sub category, kosong = semua category
+	UsageLimit   int            `json:"usageLimit"`  // total pemakaian, 0 = tanpa batas
+	PerUserLimit int            `json:"perUserLimit"`
+	UsedCount    int            `json:"usedCount"`
+	StartsAt     time.Time      `json:"startsAt"`
+	ExpiresAt    time.Time      `json:"expiresAt"`
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+// potongan coupon untuk subtotal, dibulatkan 2 desimal dan tidak lebih dari subtotal
+func (c *Coupon) DiscountFor(subtotal float64) float64 {
+
+	discount := 0.0
+	switch c.Type {
+	case "percentage":
+		discount = subtotal * c.Value / 100
+		if c.MaxDiscount > 0 {
+			discount = math.Min(discount, c.MaxDiscount)
+		}
+	case "fixed":
+		discount = c.Value
+	}
+
+	return math.Min(subtotal, math.Round(discount*100)/100)
+}
@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"database/sql"
+	"math"
+	"time"
+)
+
+type Discount struct {
+	ID            string    `json:"id"`
+	ProductId     string    `json:"productId"`
+	SellerId      string    `json:"-"`
+	Name          string    `json:"name"`
+	Type          string    `json:"type"` // "percentage"|"fixed"
+	Value         float64   `json:"value"`
+	StartsAt      time.Time `json:"startsAt"`
+	EndsAt        time.Time `json:"endsAt"`
+	QuantityLimit int       `json:"quantityLimit"` // jumlah unit yang bisa dibeli dengan diskon, 0 = tanpa batas
+	QuantitySold  int       `json:"quantitySold"`
+	PerBuyerLimit int       `json:"perBuyerLimit"` // maksimal unit per buyer, 0 = tanpa batas
+	IsFlashSale   bool      `json:"isFlashSale"`
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+// harga setelah diskon dibulatkan 2 desimal, tidak pernah dibawah 0
+func (d *Discount) Apply(price float64) float64 {
+
+	discounted := price
+	switch d.Type {
+	case "percentage":
+		discounted = price * (100 - d.Value) / 100
+	case "fixed":
+		discounted = price - d.Value
+	}
+
+	return math.Max(0, math.Round(discounted*100)/100)
+}
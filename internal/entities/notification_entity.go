@@ -0,0 +1,24 @@
+package entities
+
+import "time"
+
+type Notification struct {
+	ID        string     `json:"id"`
+	UserId    string     `json:"-"`
+	Type      string     `json:"type"` // enum (low_stock, back_in_stock)
+	Message   string     `json:"message"`
+	ProductId string     `json:"productId,omitempty"`
+	ReadAt    *time.Time `json:"readAt"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// buyer yang minta dikabari saat product kembali tersedia, notifiedAt terisi setelah dikabari
+type StockSubscription struct {
+	ID         string     `json:"id"`
+	ProductId  string     `json:"productId"`
+	UserId     string     `json:"-"`
+	NotifiedAt *time.Time `json:"notifiedAt"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
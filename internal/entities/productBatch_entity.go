@@ -0,0 +1,23 @@
+package entities
+
+// satu perubahan di batch update, stock absolut (stock) atau relatif (delta) dan/atau harga.
+// variantId terisi = yang diubah stock/harga variant
+type ProductBatchItem struct {
+	ProductId string   `json:"productId"`
+	VariantId string   `json:"variantId"`
+	Stock     *int     `json:"stock"`
+	Delta     *int     `json:"delta"`
+	Price     *float64 `json:"price"`
+	Reason    string   `json:"reason"`
+}
+
+type ProductBatchResult struct {
+	Index         int      `json:"index"` // posisi item di request
+	ProductId     string   `json:"productId"`
+	VariantId     string   `json:"variantId,omitempty"`
+	Success       bool     `json:"success"`
+	Stock         *int     `json:"stock,omitempty"` // stock setelah perubahan
+	Price         *float64 `json:"price,omitempty"`
+	Error         string   `json:"error,omitempty"`
+	PreviousStock int      `json:"-"`
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// field product yang dicatat di setiap versi
+type ProductSnapshot struct {
+	Name           string             `json:"name"`
+	Price          float64            `json:"price"`
+	ImageUrl       string             `json:"imageUrl"`
+	Stock          int                `json:"stock"`
+	Condition      string             `json:"condition"`
+	Tags           pq.StringArray     `json:"tags"`
+	IsPurchaseable bool               `json:"isPurchaseable"`
+	Descriptions   string             `json:"descriptions"`
+	CategoryId     string             `json:"categoryId"`
+	Status         string             `json:"status"`
+	Sku            string             `json:"sku"`
+	VariantPrices  map[string]float64 `json:"variantPrices"` // key = id variant, hanya variant yang punya price override
+}
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+type ProductHistory struct {
+	ID        string                 `json:"id"`
+	ProductId string                 `json:"productId"`
+	Version   int                    `json:"version"`
+	ChangedBy UserMinimal            `json:"changedBy"` // kosong untuk versi awal product lama (sebelum ada history)
+	Snapshot  ProductSnapshot        `json:"snapshot"`
+	Diff      map[string]FieldChange `json:"diff"` // key = nama field (json), kosong untuk versi pertama
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type PricePoint struct {
+	Price         float64            `json:"price"`
+	VariantPrices map[string]float64 `json:"variantPrices"`
+	ChangedAt     time.Time          `json:"changedAt"`
+}
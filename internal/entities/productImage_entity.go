@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProductImage struct {
+	ID          string            `json:"id"`
+	ProductId   string            `json:"productId"`
+	Position    int               `json:"position"`
+	ContentType string            `json:"contentType"`
+	StorageKey  string            `json:"-"`
+	Url         string            `json:"url"`
+	Thumbnails  map[string]string `json:"thumbnails"`
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
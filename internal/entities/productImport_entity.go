@@ -0,0 +1,28 @@
+package entities
+
+import "time"
+
+// import product dari file csv/jsonl, diproses di background oleh job import
+type ProductImportJob struct {
+	ID           string               `json:"id"`
+	SellerId     string               `json:"-"`
+	Format       string               `json:"format"` // enum (csv, jsonl)
+	Status       string               `json:"status"` // enum (pending, processing, completed, failed)
+	TotalRows    int                  `json:"totalRows"`
+	CreatedCount int                  `json:"createdCount"`
+	UpdatedCount int                  `json:"updatedCount"`
+	FailedCount  int                  `json:"failedCount"`
+	Errors       []ProductImportError `json:"errors"`
+	Payload      string               `json:"-"` // isi file, dikosongkan setelah selesai diproses
+
+	CreatedAt  time.Time  `json:"createdAt"`
+	StartedAt  *time.Time `json:"startedAt"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
+
+// row yang gagal diimport, row dihitung dari 1 (header csv tidak dihitung)
+type ProductImportError struct {
+	Row   int    `json:"row"`
+	Sku   string `json:"sku,omitempty"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,21 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProductQuestion struct {
+	ID         string      `json:"id"`
+	ProductId  string      `json:"productId"`
+	AskerId    string      `json:"-"`
+	Asker      UserMinimal `json:"asker"`
+	Question   string      `json:"question"`
+	Answer     string      `json:"answer"` // kosong kalau belum dijawab seller
+	AnsweredAt *time.Time  `json:"answeredAt,omitempty"`
+	IsHidden   bool        `json:"isHidden"` // disembunyikan seller/admin, tidak tampil di publik
+
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
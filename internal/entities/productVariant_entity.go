@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type ProductVariant struct {
+	ID         string            `json:"id"`
+	ProductId  string            `json:"productId"`
+	Sku        string            `json:"sku"`
+	Name       string            `json:"name"`
+	Attributes VariantAttributes `json:"attributes"`
+	Price      *float64          `json:"price"` // null = pakai harga product
+	Stock      int               `json:"stock"`
+	ImageIds   pq.StringArray    `json:"imageIds"`
+
+	DiscountedPrice *float64 `json:"discountedPrice,omitempty"` // harga variant setelah diskon product yang sedang aktif
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+// harga yang dipakai saat checkout, price override variant atau harga product
+func (v *ProductVariant) EffectivePrice(productPrice float64) float64 {
+	if v.Price != nil {
+		return *v.Price
+	}
+
+	return productPrice
+}
+
+// atribut variant, contoh: {"size": "XL", "color": "merah"}, disimpan sebagai JSONB
+type VariantAttributes map[string]string
+
+func (a VariantAttributes) Value() (driver.Value, error) {
+	if a == nil {
+		return []byte("{}"), nil
+	}
+
+	return json.Marshal(a)
+}
+
+func (a *VariantAttributes) Scan(src interface{}) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	case nil:
+		*a = VariantAttributes{}
+		return nil
+	default:
+		return fmt.Errorf("Unsupported type for VariantAttributes: %T", src)
+	}
+
+	return json.Unmarshal(data, a)
+}
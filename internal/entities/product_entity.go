@@ -9,6 +9,7 @@ import (
 
 type Product struct {
 	ID             string         `json:"id"`
+	Sku            string         `json:"sku,omitempty"` // sku product dari seller, unik per seller
 	Name           string         `json:"name"`
 	Price          float64        `json:"price"`
 	ImageUrl       string         `json:"imageUrl"`
@@ -18,6 +19,17 @@ type Product struct {
 	IsPurchaseable bool           `json:"isPurchaseable"`
 	SellerId       string         `json:"sellerId"`
 	Descriptions   string         `json:"descriptions"`
+	CategoryId     string         `json:"categoryId"`
+	RatingAverage  float64        `json:"ratingAverage"`
+	RatingCount    int            `json:"ratingCount"`
+	Status         string         `json:"status"`              // "draft"|"scheduled"|"published"|"archived"
+	PublishAt      *time.Time     `json:"publishAt,omitempty"` // status scheduled dipublish otomatis pada waktu ini
+	ArchiveAt      *time.Time     `json:"archiveAt,omitempty"` // product published diarsip otomatis pada waktu ini
+	OriginalPrice  float64        `json:"originalPrice"`
+	EffectivePrice float64        `json:"effectivePrice"`     // harga setelah diskon aktif, sama dengan price kalau tidak ada diskon
+	Discount       *Discount      `json:"discount,omitempty"` // diskon yang sedang aktif
+
+	Variants []ProductVariant `json:"variants,omitempty"`
 
 	CreatedAt time.Time    `json:"-"`
 	UpdatedAt time.Time    `json:"-"`
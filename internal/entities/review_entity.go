@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Review struct {
+	ID            string      `json:"id"`
+	TransactionId string      `json:"transactionId"`
+	ProductId     string      `json:"productId"`
+	VariantId     string      `json:"variantId,omitempty"`
+	BuyerId       string      `json:"-"`
+	SellerId      string      `json:"-"`
+	Rating        int         `json:"rating"` // 1-5
+	Body          string      `json:"body"`
+	Reply         string      `json:"reply"` // balasan seller, kosong kalau belum dibalas
+	HelpfulCount  int         `json:"helpfulCount"`
+	Buyer         UserMinimal `json:"buyer"`
+
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
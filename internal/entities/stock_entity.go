@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// satu baris ledger stock, tidak pernah diubah atau dihapus.
+// stockAfter = stock warehouse kalau warehouseId terisi, selain itu stock total product/variant
+type StockMovement struct {
+	ID            string      `json:"id"`
+	ProductId     string      `json:"productId"`
+	VariantId     string      `json:"variantId,omitempty"`
+	WarehouseId   string      `json:"warehouseId,omitempty"`
+	Type          string      `json:"type"` // enum (restock, sale, reservation, release, adjustment, return, transfer)
+	Delta         int         `json:"delta"`
+	StockAfter    int         `json:"stockAfter"`
+	Reason        string      `json:"reason"`
+	ActorId       string      `json:"-"` // kosong = sistem (contoh: reservation yang kadaluarsa)
+	Actor         UserMinimal `json:"actor"`
+	TransactionId string      `json:"transactionId,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// stock yang ditahan untuk transaksi yang belum diterima seller
+type StockReservation struct {
+	ID            string    `json:"id"`
+	TransactionId string    `json:"transactionId"`
+	ProductId     string    `json:"productId"`
+	VariantId     string    `json:"variantId,omitempty"`
+	WarehouseId   string    `json:"warehouseId,omitempty"` // kosong = dari stock yang belum dialokasikan
+	BuyerId       string    `json:"buyerId"`
+	Quantity      int       `json:"quantity"`
+	Status        string    `json:"status"` // enum (active, committed, released)
+	ExpiresAt     time.Time `json:"expiresAt"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"-"`
+}
@@ -0,0 +1,33 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+// profil toko seller, satu seller satu storefront
+type Storefront struct {
+	ID              string            `json:"id"`
+	SellerId        string            `json:"sellerId"`
+	Name            string            `json:"name"`
+	Slug            string            `json:"slug"`
+	Description     string            `json:"description"`
+	LogoUrl         string            `json:"logoUrl"`
+	BannerUrl       string            `json:"bannerUrl"`
+	Location        string            `json:"location"`
+	OpeningHours    map[string]string `json:"openingHours"` // key "mon".."sun", value "09:00-17:00" atau "closed"
+	VacationMode    bool              `json:"vacationMode"` // selama aktif semua product seller tidak bisa dibeli
+	VacationMessage string            `json:"vacationMessage"`
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+// ringkasan product dan rating untuk halaman publik storefront
+type StorefrontStats struct {
+	ProductCount  int         `json:"productCount"`
+	RatingAverage float64     `json:"ratingAverage"`
+	RatingCount   int         `json:"ratingCount"`
+	RatingCounts  map[int]int `json:"ratingCounts"` // jumlah review per bintang 1-5
+}
@@ -9,23 +9,43 @@ type Transaction struct {
 	ID        string  `json:"id"`
 	Status    string  `json:"status"` // enum (menunggu, diterima seller, dalam pengiriman, diterima)
 	ProductId string  `json:"productId"`
+	VariantId string  `json:"variantId"` // wajib diisi kalau product punya variant
 	BuyerId   string  `json:"buyerId"`
 	SellerId  string  `json:"sellerId"`
 	Total     float64 `json:"total"`
 	Quantity  int     `json:"quantity"`
 	Notes     string  `json:"notes"`
 
+	DiscountId     string  `json:"discountId,omitempty"`
+	DiscountAmount float64 `json:"discountAmount"` // potongan dari diskon/flash sale product
+
+	CouponCode     string  `json:"couponCode,omitempty"` // kode coupon dari buyer saat checkout
+	CouponId       string  `json:"couponId,omitempty"`
+	Subtotal       float64 `json:"subtotal"`       // total sebelum potongan coupon
+	CouponDiscount float64 `json:"couponDiscount"` // total = subtotal - couponDiscount
+
+	// lokasi buyer untuk memilih warehouse terdekat, kosong = pakai prioritas warehouse
+	Latitude    *float64 `json:"latitude,omitempty"`
+	Longitude   *float64 `json:"longitude,omitempty"`
+	WarehouseId string   `json:"warehouseId,omitempty"` // warehouse yang mengirim barang
+
 	CreatedAt time.Time    `json:"-"`
 	UpdatedAt time.Time    `json:"-"`
 	DeletedAt sql.NullTime `json:"-"`
 }
 
 type TransactionMinimal struct {
-	ID       string  `json:"id"`
-	Status   string  `json:"status"` // enum (menunggu, diterima seller, dalam pengiriman, diterima)
-	Total    float64 `json:"total"`
-	Quantity int     `json:"quantity"`
-	Notes    string  `json:"notes"`
+	ID        string  `json:"id"`
+	Status    string  `json:"status"` // enum (menunggu, diterima seller, dalam pengiriman, diterima)
+	VariantId string  `json:"variantId,omitempty"`
+	Total     float64 `json:"total"`
+	Quantity  int     `json:"quantity"`
+	Notes     string  `json:"notes"`
+
+	DiscountAmount float64 `json:"discountAmount"`
+	Subtotal       float64 `json:"subtotal"`
+	CouponDiscount float64 `json:"couponDiscount"`
+	WarehouseId    string  `json:"warehouseId,omitempty"`
 
 	CreatedAt time.Time    `json:"-"`
 	UpdatedAt time.Time    `json:"-"`
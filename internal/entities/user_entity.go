@@ -9,7 +9,22 @@ type User struct {
 	ID           string `json:"id"`
 	Name         string `json:"name"`
 	Username     string `json:"username"`
-	HashPassword string `json:"password"`
+	Password     string `json:"password,omitempty"` // password plain dari request, tidak pernah disimpan
+	HashPassword string `json:"-"`
+
+	PasswordChangedAt sql.NullTime `json:"-"`
+	TokenVersion      int          `json:"-"` // naik setiap password diganti, token dengan versi lama tidak berlaku
+
+	// kontak opsional, berubah = harus verifikasi ulang
+	Email           string       `json:"-"`
+	Phone           string       `json:"-"`
+	EmailVerifiedAt sql.NullTime `json:"-"`
+	PhoneVerifiedAt sql.NullTime `json:"-"`
+
+	// 2fa TOTP, aktif kalau TotpEnabledAt valid. secret disimpan apa adanya karena dipakai hitung kode
+	TotpSecret      string       `json:"-"`
+	TotpEnabledAt   sql.NullTime `json:"-"`
+	TotpLockedUntil sql.NullTime `json:"-"` // terlalu banyak kode salah
 
 	CreatedAt time.Time    `json:"-"`
 	UpdatedAt time.Time    `json:"-"`
@@ -25,3 +40,40 @@ type UserMinimal struct {
 	UpdatedAt time.Time    `json:"-"`
 	DeletedAt sql.NullTime `json:"-"`
 }
+
+// ringkasan aktivitas user untuk profil publik
+type UserStats struct {
+	JoinedAt       time.Time `json:"joinedAt"`
+	ProductCount   int       `json:"productCount"`   // product published
+	CompletedSales int       `json:"completedSales"` // transaksi sebagai seller yang sudah diterima buyer
+	RatingAverage  float64   `json:"ratingAverage"`  // dari semua review product user
+	RatingCount    int       `json:"ratingCount"`
+}
+
+// kontak user, hanya dikirim ke user itu sendiri
+type UserContact struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"`
+	Phone         string `json:"phone"`
+	PhoneVerified bool   `json:"phoneVerified"`
+}
+
+// kode verifikasi email/phone, yang disimpan hanya hash kodenya
+type ContactVerification struct {
+	ID         string
+	UserId     string
+	Channel    string // "email"|"phone"
+	Target     string // email/phone saat kode dibuat
+	CodeHash   string
+	Attempts   int
+	ExpiresAt  time.Time
+	VerifiedAt sql.NullTime
+	CreatedAt  time.Time
+}
+
+// status 2fa user, hanya dikirim ke user itu sendiri
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	EnabledAt         *time.Time `json:"enabledAt"`
+	RecoveryCodesLeft int        `json:"recoveryCodesLeft"`
+}
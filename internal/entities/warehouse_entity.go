@@ -0,0 +1,42 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Warehouse struct {
+	ID        string   `json:"id"`
+	SellerId  string   `json:"sellerId"`
+	Name      string   `json:"name"`
+	Address   string   `json:"address"`
+	City      string   `json:"city"`
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Priority  int      `json:"priority"` // makin kecil makin diutamakan saat checkout tanpa lokasi buyer
+	IsActive  bool     `json:"isActive"`
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+// stock product/variant di satu warehouse, bagian dari stock total product
+type WarehouseStock struct {
+	WarehouseId   string `json:"warehouseId"`
+	WarehouseName string `json:"warehouseName"`
+	ProductId     string `json:"productId"`
+	VariantId     string `json:"variantId,omitempty"`
+	Stock         int    `json:"stock"`
+}
+
+// pindah stock antar warehouse, warehouse kosong = stock yang belum dialokasikan ke warehouse manapun
+type StockTransfer struct {
+	ProductId       string `json:"-"`
+	VariantId       string `json:"variantId"`
+	FromWarehouseId string `json:"fromWarehouseId"`
+	ToWarehouseId   string `json:"toWarehouseId"`
+	Quantity        int    `json:"quantity"`
+	Reason          string `json:"reason"`
+	ActorId         string `json:"-"`
+}
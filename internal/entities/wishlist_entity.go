@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"database/sql"
+	"time"
+)
+
+type Wishlist struct {
+	ID         string         `json:"id"`
+	UserId     string         `json:"-"`
+	Name       string         `json:"name"`
+	ShareToken string         `json:"shareToken,omitempty"` // kosong = wishlist tidak dibagikan
+	ItemCount  int            `json:"itemCount"`
+	Items      []WishlistItem `json:"items,omitempty"`
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
+
+// price/stock saat ditambahkan disimpan supaya perubahan harga dan stock bisa ditampilkan
+type WishlistItem struct {
+	ID           string         `json:"id"`
+	WishlistId   string         `json:"-"`
+	ProductId    string         `json:"productId"`
+	VariantId    string         `json:"variantId,omitempty"`
+	Quantity     int            `json:"quantity"`
+	PriceAtAdd   float64        `json:"priceAtAdd"`
+	StockAtAdd   int            `json:"stockAtAdd"`
+	CurrentPrice float64        `json:"currentPrice"`
+	CurrentStock int            `json:"currentStock"`
+	PriceChange  float64        `json:"priceChange"` // currentPrice - priceAtAdd, negatif = turun harga
+	BackInStock  bool           `json:"backInStock"` // stock kosong saat ditambahkan, sekarang tersedia
+	Available    bool           `json:"available"`   // masih bisa dibeli (ada stock dan purchaseable)
+	Product      ProductMinimal `json:"product"`
+
+	CreatedAt time.Time    `json:"-"`
+	UpdatedAt time.Time    `json:"-"`
+	DeletedAt sql.NullTime `json:"-"`
+}
@@ -1,10 +1,17 @@
 package helper
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"log"
 	"net/http"
+	"strings"
 
+	"github.com/GetterSethya/golangApiMarketplace/config"
 	"github.com/GetterSethya/golangApiMarketplace/internal/types"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -43,6 +50,13 @@ func GenerateHash(plainText string) string {
 
 	return string(hash)
 }
+
+// true kalau plainText cocok dengan hash bcrypt
+func CompareHash(hash, plainText string) bool {
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plainText)) == nil
+}
+
 func ArrayToString(arr []string) string {
 	str := ""
 	for i, v := range arr {
@@ -60,3 +74,104 @@ func ValidateUUID(id string) bool {
 
 	return err == nil
 }
+
+// cursor ditandatangani (HMAC) karena value di dalamnya langsung dipakai sebagai parameter
+// query dengan cast tipe kolom, cursor yang diubah client ditolak. format "{payload}.{signature}"
+func EncodeCursor(c types.Cursor) string {
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(b)
+
+	return payload + "." + cursorSignature(payload)
+}
+
+// return nil kalau cursor kosong, tidak valid atau signature tidak cocok
+func DecodeCursor(token string) *types.Cursor {
+
+	if token == "" {
+		return nil
+	}
+
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(cursorSignature(payload))) {
+		return nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil
+	}
+
+	var cursor types.Cursor
+	if err := json.Unmarshal(b, &cursor); err != nil || len(cursor.Values) == 0 {
+		return nil
+	}
+
+	return &cursor
+}
+
+func cursorSignature(payload string) string {
+
+	mac := hmac.New(sha256.New, []byte("cursor:"+config.LoadConfig().App.JWTSecret))
+	mac.Write([]byte(payload))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// signature sort untuk cursor, contoh "price:asc,createdAt:desc"
+func SortSignature(sorts []types.SortField) string {
+
+	var parts []string
+	for _, sort := range sorts {
+		if sort.Desc {
+			parts = append(parts, sort.Field+":desc")
+		} else {
+			parts = append(parts, sort.Field+":asc")
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// token acak (hex) untuk link share dan sejenisnya, panjang hasil = 2*n karakter
+func GenerateRandomToken(n int) string {
+
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatal("Error when generating random token")
+	}
+
+	return hex.EncodeToString(b)
+}
+
+// hash sha256 (hex) untuk token yang disimpan di db, token asli hanya dikirim ke user
+func HashToken(token string) string {
+
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// kode angka acak (contoh kode verifikasi 6 digit), boleh diawali 0
+func GenerateNumericCode(n int) string {
+
+	code := make([]byte, 0, n)
+	b := make([]byte, 1)
+
+	for len(code) < n {
+		if _, err := rand.Read(b); err != nil {
+			log.Fatal("Error when generating random code")
+		}
+
+		// byte >= 250 dibuang supaya tiap digit punya peluang yang sama
+		if b[0] < 250 {
+			code = append(code, '0'+b[0]%10)
+		}
+	}
+
+	return string(code)
+}
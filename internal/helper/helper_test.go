@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+)
+
+func TestCursor(t *testing.T) {
+	t.Setenv("JWTSECRET", "superSecret")
+
+	cursor := types.Cursor{Order: "createdAt:desc", Values: []string{"2024-01-02 15:04:05", "a3c3d7a4-6b39-4f4c-9a8b-2f53d5b7b6a1"}}
+	token := EncodeCursor(cursor)
+
+	t.Run("Should decode cursor created by server", func(t *testing.T) {
+		decoded := DecodeCursor(token)
+		if decoded == nil || decoded.Order != cursor.Order || decoded.Values[0] != cursor.Values[0] {
+			t.Errorf("Expected cursor %+v, but got=%+v", cursor, decoded)
+		}
+	})
+
+	t.Run("Should reject cursor edited by client", func(t *testing.T) {
+		payload, signature, _ := strings.Cut(token, ".")
+		b, _ := base64.RawURLEncoding.DecodeString(payload)
+		edited := strings.Replace(string(b), "2024-01-02 15:04:05", "x", 1)
+
+		if decoded := DecodeCursor(base64.RawURLEncoding.EncodeToString([]byte(edited)) + "." + signature); decoded != nil {
+			t.Errorf("Expected edited cursor to be rejected, but got=%+v", decoded)
+		}
+
+		if decoded := DecodeCursor(payload); decoded != nil {
+			t.Errorf("Expected unsigned cursor to be rejected, but got=%+v", decoded)
+		}
+	})
+
+	t.Run("Should reject cursor signed with other secret", func(t *testing.T) {
+		t.Setenv("JWTSECRET", "otherSecret")
+
+		if decoded := DecodeCursor(token); decoded != nil {
+			t.Errorf("Expected cursor to be rejected, but got=%+v", decoded)
+		}
+	})
+}
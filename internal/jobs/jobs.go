@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"log"
+	"time"
+)
+
+// jalankan fn sekali di awal lalu tiap interval, berhenti kalau stop ditutup (stop nil = jalan terus).
+// dipanggil pakai goroutine: go jobs.Every(...)
+func Every(name string, interval time.Duration, stop <-chan struct{}, fn func() error) {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := fn(); err != nil {
+			log.Println("job", name, "failed:", err)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+// seberapa sering antrian import product dicek
+const PRODUCTIMPORTINTERVAL = 5 * time.Second
+
+// proses antrian import product satu per satu sampai kosong,
+// Process mengisi hasil import ke job (usecases.ProcessProductImport)
+type ProductImportJob struct {
+	Store   datastore.Store
+	Process func(s datastore.Store, job *entities.ProductImportJob)
+}
+
+func NewProductImportJob(s datastore.Store, process func(s datastore.Store, job *entities.ProductImportJob)) *ProductImportJob {
+
+	return &ProductImportJob{
+		Store:   s,
+		Process: process,
+	}
+}
+
+func (j *ProductImportJob) Run() error {
+
+	for {
+		job, err := j.Store.ClaimProductImportJob()
+		if err != nil {
+			return err
+		}
+
+		if job == nil {
+			return nil
+		}
+
+		j.Process(j.Store, job)
+
+		if err := j.Store.FinishProductImportJob(job); err != nil {
+			return err
+		}
+
+		log.Printf("product import %s %s: %d created, %d updated, %d failed", job.ID, job.Status, job.CreatedCount, job.UpdatedCount, job.FailedCount)
+	}
+}
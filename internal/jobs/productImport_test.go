@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+type importStore struct {
+	datastore.MockStore
+	pending  []*entities.ProductImportJob
+	finished []*entities.ProductImportJob
+}
+
+func (i *importStore) ClaimProductImportJob() (*entities.ProductImportJob, error) {
+
+	if len(i.pending) == 0 {
+		return nil, nil
+	}
+
+	job := i.pending[0]
+	i.pending = i.pending[1:]
+
+	return job, nil
+}
+
+func (i *importStore) FinishProductImportJob(job *entities.ProductImportJob) error {
+	i.finished = append(i.finished, job)
+
+	return nil
+}
+
+func TestProductImportJob(t *testing.T) {
+	store := &importStore{
+		pending: []*entities.ProductImportJob{{ID: "j1"}, {ID: "j2"}},
+	}
+
+	process := func(s datastore.Store, job *entities.ProductImportJob) {
+		job.Status = "completed"
+	}
+
+	if err := NewProductImportJob(store, process).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.finished) != 2 {
+		t.Fatalf("Expected 2 import jobs to be finished, but got %d", len(store.finished))
+	}
+
+	for _, job := range store.finished {
+		if job.Status != "completed" {
+			t.Errorf("Expected job %s to be processed before finished, but got status %q", job.ID, job.Status)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+)
+
+// seberapa sering jadwal publish/arsip product dicek
+const PRODUCTSCHEDULEINTERVAL = time.Minute
+
+// publish product scheduled dan arsip product yang archiveAt nya sudah lewat
+type ProductScheduleJob struct {
+	Store datastore.Store
+}
+
+func NewProductScheduleJob(s datastore.Store) *ProductScheduleJob {
+
+	return &ProductScheduleJob{
+		Store: s,
+	}
+}
+
+func (j *ProductScheduleJob) Run() error {
+
+	published, archived, err := j.Store.ApplyProductSchedule()
+	if err != nil {
+		return err
+	}
+
+	if published > 0 || archived > 0 {
+		log.Printf("product schedule: %d published, %d archived", published, archived)
+	}
+
+	return nil
+}
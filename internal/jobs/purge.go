@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/media"
+)
+
+// interval default purge kalau dijalankan bareng api
+const PURGEINTERVAL = 24 * time.Hour
+
+// hapus permanen data soft delete (product, user, bank account) yang sudah lewat grace period,
+// termasuk file gambar product di media store
+type PurgeJob struct {
+	Store       datastore.Store
+	Media       media.MediaStore
+	GracePeriod time.Duration
+}
+
+func NewPurgeJob(s datastore.Store, m media.MediaStore, grace time.Duration) *PurgeJob {
+
+	return &PurgeJob{
+		Store:       s,
+		Media:       m,
+		GracePeriod: grace,
+	}
+}
+
+func (j *PurgeJob) Run() error {
+
+	result, err := j.Store.PurgeDeleted(j.GracePeriod)
+	if err != nil {
+		return err
+	}
+
+	// baris di db sudah terhapus, file yang gagal dihapus cukup di log
+	for _, key := range result.ImageKeys {
+		if err := media.DeleteImage(j.Media, key, strings.TrimSuffix(key, path.Ext(key))); err != nil {
+			log.Println("error when deleting purged image", key, err)
+		}
+	}
+
+	log.Printf("purge done: %d products, %d users, %d bank accounts, %d images", result.Products, result.Users, result.BankAccounts, len(result.ImageKeys))
+
+	return nil
+}
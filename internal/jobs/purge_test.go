@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/media"
+)
+
+type purgeStore struct {
+	datastore.MockStore
+	grace time.Duration
+}
+
+func (p *purgeStore) PurgeDeleted(grace time.Duration) (*datastore.PurgeResult, error) {
+	p.grace = grace
+
+	return &datastore.PurgeResult{Products: 1, ImageKeys: []string{"products/p1/img1.jpg"}}, nil
+}
+
+func TestPurgeJob(t *testing.T) {
+	mediaStore, err := media.NewFileSystemStore(t.TempDir(), "/v1/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"products/p1/img1.jpg"}
+	for name := range media.ThumbnailSizes {
+		keys = append(keys, media.ThumbnailKey("products/p1/img1", name))
+	}
+
+	for _, key := range keys {
+		if err := mediaStore.Save(key, bytes.NewReader([]byte("x"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := &purgeStore{}
+	if err := NewPurgeJob(store, mediaStore, 48*time.Hour).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if store.grace != 48*time.Hour {
+		t.Errorf("Expected grace period 48h, but got=%s", store.grace)
+	}
+
+	for _, key := range keys {
+		if f, err := mediaStore.Open(key); err == nil {
+			f.Close()
+			t.Errorf("Expected %s to be deleted", key)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+)
+
+// seberapa sering reservation stock yang kadaluarsa dicek
+const STOCKRESERVATIONINTERVAL = time.Minute
+
+// lepas stock transaksi yang tidak diterima seller sampai reservation nya kadaluarsa,
+// transaksinya ditolak otomatis dan kuota diskon/coupon nya dikembalikan
+type StockReservationJob struct {
+	Store datastore.Store
+}
+
+func NewStockReservationJob(s datastore.Store) *StockReservationJob {
+
+	return &StockReservationJob{
+		Store: s,
+	}
+}
+
+func (j *StockReservationJob) Run() error {
+
+	transactionIds, err := j.Store.ReleaseExpiredReservations()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range transactionIds {
+		if err := j.Store.ReleaseDiscountClaim(id); err != nil {
+			log.Println("error when releasing discount claim of expired reservation", id, err)
+		}
+
+		if err := j.Store.ReleaseCouponClaim(id); err != nil {
+			log.Println("error when releasing coupon claim of expired reservation", id, err)
+		}
+	}
+
+	if len(transactionIds) > 0 {
+		log.Printf("stock reservation: %d expired reservation released", len(transactionIds))
+	}
+
+	return nil
+}
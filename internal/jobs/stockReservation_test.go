@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+)
+
+type reservationStore struct {
+	datastore.MockStore
+	discountReleased []string
+	couponReleased   []string
+}
+
+func (r *reservationStore) ReleaseExpiredReservations() ([]string, error) {
+
+	return []string{"t1", "t2"}, nil
+}
+
+func (r *reservationStore) ReleaseDiscountClaim(transactionId string) error {
+	r.discountReleased = append(r.discountReleased, transactionId)
+
+	return nil
+}
+
+func (r *reservationStore) ReleaseCouponClaim(transactionId string) error {
+	r.couponReleased = append(r.couponReleased, transactionId)
+
+	return nil
+}
+
+func TestStockReservationJob(t *testing.T) {
+	store := &reservationStore{}
+	if err := NewStockReservationJob(store).Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.discountReleased) != 2 || len(store.couponReleased) != 2 {
+		t.Errorf("Expected claims of 2 expired transactions to be released, but got discount=%v coupon=%v", store.discountReleased, store.couponReleased)
+	}
+}
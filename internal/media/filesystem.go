@@ -0,0 +1,120 @@
+package media
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// implementasi MediaStore yang menyimpan file di local filesystem
+type FileSystemStore struct {
+	root    string
+	baseUrl string
+}
+
+func NewFileSystemStore(root, baseUrl string) (*FileSystemStore, error) {
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileSystemStore{
+		root:    root,
+		baseUrl: strings.TrimSuffix(baseUrl, "/"),
+	}, nil
+}
+
+func (f *FileSystemStore) Save(key string, r io.Reader) error {
+
+	fullPath, err := f.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return err
+	}
+
+	// tulis ke file sementara dulu biar file yang sedang diserve tidak setengah jadi
+	tmp, err := os.CreateTemp(filepath.Dir(fullPath), ".upload-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), fullPath)
+}
+
+func (f *FileSystemStore) Open(key string) (io.ReadCloser, error) {
+
+	fullPath, err := f.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(fullPath)
+}
+
+func (f *FileSystemStore) Delete(key string) error {
+
+	fullPath, err := f.resolve(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (f *FileSystemStore) URL(key string) string {
+
+	return f.baseUrl + "/" + key
+}
+
+// serve file statis, directory listing sengaja dimatikan
+func (f *FileSystemStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	fullPath, err := f.resolve(strings.TrimPrefix(r.URL.Path, "/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil || info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeFile(w, r, fullPath)
+}
+
+// ubah key jadi path di filesystem, tolak key yang keluar dari root
+func (f *FileSystemStore) resolve(key string) (string, error) {
+
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("Invalid media key")
+	}
+
+	return filepath.Join(f.root, filepath.FromSlash(cleaned)), nil
+}
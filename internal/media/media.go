@@ -0,0 +1,229 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"log"
+	"net/http"
+
+	// register decoder untuk format yang diterima
+	_ "image/gif"
+	_ "image/png"
+)
+
+// MediaStore adalah abstraksi tempat menyimpan file media (gambar product, dll).
+//
+// key selalu berupa path relatif dengan separator "/", contoh: "products/{productId}/{imageId}.jpg"
+type MediaStore interface {
+	Save(key string, r io.Reader) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+	URL(key string) string
+}
+
+const (
+	MAXIMAGESIZE      = 5 << 20 // 5MB per file
+	MAXIMAGEDIMENSION = 5000
+)
+
+// ukuran thumbnail, value adalah panjang sisi terpanjang dalam pixel
+var ThumbnailSizes = map[string]int{
+	"small":  150,
+	"medium": 400,
+	"large":  800,
+}
+
+var allowedImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// hasil upload gambar, berisi key original dan key tiap thumbnail
+type UploadResult struct {
+	ContentType string
+	Key         string
+	Thumbnails  map[string]string
+}
+
+// cek content-type dari isi file (bukan dari header request), return error kalau bukan gambar yang diterima
+func SniffImage(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+
+	if _, ok := allowedImageTypes[contentType]; !ok {
+		return "", fmt.Errorf("Unsupported image type: %s", contentType)
+	}
+
+	return contentType, nil
+}
+
+// cek ukuran, tipe dan dimensi gambar tanpa decode penuh, return content-type.
+// dipakai untuk validasi semua file upload sebelum ada yang disimpan
+func ValidateImage(data []byte) (string, error) {
+
+	if len(data) > MAXIMAGESIZE {
+		return "", fmt.Errorf("Image too large, max %d bytes", MAXIMAGESIZE)
+	}
+
+	contentType, err := SniffImage(data)
+	if err != nil {
+		return "", err
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("Invalid image")
+	}
+
+	if cfg.Width > MAXIMAGEDIMENSION || cfg.Height > MAXIMAGEDIMENSION {
+		return "", fmt.Errorf("Image dimension too large, max %dx%d", MAXIMAGEDIMENSION, MAXIMAGEDIMENSION)
+	}
+
+	return contentType, nil
+}
+
+// simpan gambar original beserta thumbnailnya, prefix adalah key tanpa extension.
+// kalau gagal di tengah jalan file yang sudah tersimpan dihapus lagi
+func ProcessImageUpload(m MediaStore, prefix string, data []byte) (*UploadResult, error) {
+
+	contentType, err := ValidateImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid image")
+	}
+
+	result := &UploadResult{
+		ContentType: contentType,
+		Key:         prefix + allowedImageTypes[contentType],
+		Thumbnails:  map[string]string{},
+	}
+
+	if err := m.Save(result.Key, bytes.NewReader(data)); err != nil {
+		deleteKeys(m, result.Key)
+		return nil, err
+	}
+
+	for name, size := range ThumbnailSizes {
+		key := ThumbnailKey(prefix, name)
+
+		var buf bytes.Buffer
+		err := jpeg.Encode(&buf, Resize(img, size), &jpeg.Options{Quality: 85})
+		if err == nil {
+			err = m.Save(key, &buf)
+		}
+
+		if err != nil {
+			deleteKeys(m, append(result.ThumbnailKeys(), result.Key, key)...)
+			return nil, err
+		}
+
+		result.Thumbnails[name] = key
+	}
+
+	return result, nil
+}
+
+// key semua thumbnail yang sudah tersimpan
+func (u *UploadResult) ThumbnailKeys() []string {
+
+	keys := make([]string, 0, len(u.Thumbnails))
+	for _, key := range u.Thumbnails {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// hapus semua file hasil upload (original + thumbnail), dipakai untuk rollback
+func (u *UploadResult) Delete(m MediaStore) {
+
+	deleteKeys(m, append(u.ThumbnailKeys(), u.Key)...)
+}
+
+func deleteKeys(m MediaStore, keys ...string) {
+
+	for _, key := range keys {
+		if err := m.Delete(key); err != nil {
+			log.Println("error when deleting media file", key, err)
+		}
+	}
+}
+
+// key thumbnail selalu jpeg, format: {prefix}_{name}.jpg
+func ThumbnailKey(prefix, name string) string {
+
+	return prefix + "_" + name + ".jpg"
+}
+
+// hapus gambar original beserta semua thumbnailnya
+func DeleteImage(m MediaStore, key, prefix string) error {
+
+	if err := m.Delete(key); err != nil {
+		return err
+	}
+
+	for name := range ThumbnailSizes {
+		if err := m.Delete(ThumbnailKey(prefix, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resize gambar dengan sisi terpanjang = maxSide, aspect ratio tetap.
+// pakai box sampling (rata-rata pixel) biar tidak perlu dependency tambahan
+func Resize(src image.Image, maxSide int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+
+	if srcW <= maxSide && srcH <= maxSide {
+		maxSide = max(srcW, srcH)
+	}
+
+	dstW, dstH := maxSide, maxSide
+	if srcW > srcH {
+		dstH = max(1, srcH*maxSide/srcW)
+	} else {
+		dstW = max(1, srcW*maxSide/srcH)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstW, dstH))
+
+	for y := 0; y < dstH; y++ {
+		y0 := bounds.Min.Y + y*srcH/dstH
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*srcH/dstH)
+
+		for x := 0; x < dstW; x++ {
+			x0 := bounds.Min.X + x*srcW/dstW
+			x1 := max(x0+1, bounds.Min.X+(x+1)*srcW/dstW)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i+0] = uint8((r / n) >> 8)
+			dst.Pix[i+1] = uint8((g / n) >> 8)
+			dst.Pix[i+2] = uint8((b / n) >> 8)
+			dst.Pix[i+3] = uint8((a / n) >> 8)
+		}
+	}
+
+	return dst
+}
@@ -0,0 +1,169 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func createTestPng(t *testing.T, w, h int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 100, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestSniffImage(t *testing.T) {
+
+	t.Run("Should accept png", func(t *testing.T) {
+		contentType, err := SniffImage(createTestPng(t, 10, 10))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if contentType != "image/png" {
+			t.Errorf("Expected image/png, but got=%s", contentType)
+		}
+	})
+
+	t.Run("Should reject non image", func(t *testing.T) {
+		if _, err := SniffImage([]byte("<html><script>alert(1)</script></html>")); err == nil {
+			t.Errorf("Expected error for html payload")
+		}
+	})
+}
+
+func TestProcessImageUpload(t *testing.T) {
+	store, err := NewFileSystemStore(t.TempDir(), "/v1/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ProcessImageUpload(store, "products/abc/img1", createTestPng(t, 1000, 500))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Key != "products/abc/img1.png" {
+		t.Errorf("Expected key products/abc/img1.png, but got=%s", result.Key)
+	}
+
+	if len(result.Thumbnails) != len(ThumbnailSizes) {
+		t.Errorf("Expected %d thumbnails, but got=%d", len(ThumbnailSizes), len(result.Thumbnails))
+	}
+
+	f, err := store.Open(result.Thumbnails["small"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Width != 150 || cfg.Height != 75 {
+		t.Errorf("Expected small thumbnail 150x75, but got=%dx%d", cfg.Width, cfg.Height)
+	}
+}
+
+// store yang gagal menyimpan setelah beberapa file, untuk cek rollback
+type failingStore struct {
+	*FileSystemStore
+	remaining int
+}
+
+func (f *failingStore) Save(key string, r io.Reader) error {
+
+	if f.remaining == 0 {
+		return fmt.Errorf("disk full")
+	}
+
+	f.remaining--
+
+	return f.FileSystemStore.Save(key, r)
+}
+
+func TestProcessImageUploadCleanup(t *testing.T) {
+	root := t.TempDir()
+	fsStore, err := NewFileSystemStore(root, "/v1/media")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &failingStore{FileSystemStore: fsStore, remaining: 2}
+
+	if _, err := ProcessImageUpload(store, "products/abc/img1", createTestPng(t, 100, 100)); err == nil {
+		t.Fatal("Expected error when thumbnail cannot be saved")
+	}
+
+	var files []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+
+	if len(files) != 0 {
+		t.Errorf("Expected saved files to be removed, but got=%v", files)
+	}
+}
+
+func TestFileSystemStore(t *testing.T) {
+	store, err := NewFileSystemStore(t.TempDir(), "/v1/media/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Save("../../etc/passwd", bytes.NewReader([]byte("x"))); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := store.Open("etc/passwd")
+	if err != nil {
+		t.Fatalf("Expected traversal key to stay inside root, got=%v", err)
+	}
+	f.Close()
+
+	if url := store.URL("a/b.jpg"); url != "/v1/media/a/b.jpg" {
+		t.Errorf("Expected /v1/media/a/b.jpg, but got=%s", url)
+	}
+
+	t.Run("Should serve file", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		store.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/etc/passwd", nil))
+
+		body, _ := io.ReadAll(rr.Body)
+		if rr.Code != http.StatusOK || string(body) != "x" {
+			t.Errorf("Expected 200 with body x, but got=%d %s", rr.Code, body)
+		}
+	})
+
+	t.Run("Should not list directory", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		store.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/etc", nil))
+
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, but got=%d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Notifier adalah abstraksi pengiriman pesan ke user (email, sms, push, dll).
+//
+// implementasi dipilih saat server start, usecase tidak perlu tahu pesan dikirim lewat apa
+type Notifier interface {
+	Send(m Message) error
+}
+
+type Message struct {
+	UserId  string `json:"userId"`
+	Channel string `json:"channel"` // "email"|"sms", kosong kalau user belum punya kontak
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+// implementasi Notifier yang hanya menulis pesan ke log, untuk development
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+
+	return &LogNotifier{}
+}
+
+func (l *LogNotifier) Send(m Message) error {
+
+	log.Printf("notification %s to %s (%s): %s - %s", m.Channel, m.To, m.UserId, m.Subject, m.Body)
+
+	return nil
+}
+
+// implementasi Notifier yang menulis pesan (satu json per baris) ke file, untuk development
+type FileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) (*FileNotifier, error) {
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	return &FileNotifier{
+		path: path,
+	}, nil
+}
+
+func (f *FileNotifier) Send(m Message) error {
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	_, err = file.Write(append(b, '\n'))
+
+	return err
+}
+
+// implementasi Notifier yang POST pesan (json) ke url, pengiriman sebenarnya dilakukan service lain
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (wh *WebhookNotifier) Send(m Message) error {
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	resp, err := wh.client.Post(wh.url, "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhook notifier returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
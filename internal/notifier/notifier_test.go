@@ -0,0 +1,48 @@
+package notifier
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileNotifier(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "outbox", "messages.jsonl")
+
+	n, err := NewFileNotifier(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("Should append one json message per line", func(t *testing.T) {
+		for _, to := range []string{"budi@example.com", "+6281234567890"} {
+			if err := n.Send(Message{UserId: "user-id", To: to, Subject: "Verification code", Body: "123456"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+
+		var messages []Message
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var m Message
+			if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+				t.Fatal(err)
+			}
+
+			messages = append(messages, m)
+		}
+
+		if len(messages) != 2 || messages[1].To != "+6281234567890" || messages[0].Body != "123456" {
+			t.Errorf("Unexpected messages, got=%+v", messages)
+		}
+	})
+}
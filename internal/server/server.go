@@ -4,8 +4,11 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
 	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
 	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/media"
+	"github.com/GetterSethya/golangApiMarketplace/internal/notifier"
 	"github.com/GetterSethya/golangApiMarketplace/internal/services"
 	"github.com/gorilla/mux"
 )
@@ -13,18 +16,25 @@ import (
 type Server struct {
 	listenAddr string
 	store      datastore.Store
+	media      media.MediaStore
+	notifier   notifier.Notifier
 }
 
-func NewServer(addr string, store datastore.Store) *Server {
+func NewServer(addr string, store datastore.Store, mediaStore media.MediaStore, n notifier.Notifier) *Server {
 
 	return &Server{
 		listenAddr: addr,
 		store:      store,
+		media:      mediaStore,
+		notifier:   n,
 	}
 }
 
 func (s *Server) Run() {
 
+	// token yang dibuat sebelum user dihapus atau ganti password tidak berlaku lagi
+	auth.SessionValidator = s.validSession
+
 	// init router
 	router := mux.NewRouter()
 	subrouter := router.PathPrefix("/v1").Subrouter()
@@ -39,6 +49,18 @@ func (s *Server) Run() {
 	userService := services.NewUserService(s.store)
 	userService.RegisterRoutes(subrouter)
 
+	// register password service disini
+	passwordService := services.NewPasswordService(s.store, s.notifier)
+	passwordService.RegisterRoutes(subrouter)
+
+	// register contact service disini
+	contactService := services.NewContactService(s.store, s.notifier)
+	contactService.RegisterRoutes(subrouter)
+
+	// register twoFactor service disini
+	twoFactorService := services.NewTwoFactorService(s.store)
+	twoFactorService.RegisterRoutes(subrouter)
+
 	// register product service disini
 	productService := services.NewProductService(s.store)
 	productService.RegisterRoutes(subrouter)
@@ -51,6 +73,70 @@ func (s *Server) Run() {
 	transactionService := services.NewTransactionService(s.store)
 	transactionService.RegisterRoutes(subrouter)
 
+	// register productImage service disini
+	productImageService := services.NewProductImageService(s.store, s.media)
+	productImageService.RegisterRoutes(subrouter)
+
+	// register productVariant service disini
+	productVariantService := services.NewProductVariantService(s.store)
+	productVariantService.RegisterRoutes(subrouter)
+
+	// register category service disini
+	categoryService := services.NewCategoryService(s.store)
+	categoryService.RegisterRoutes(subrouter)
+
+	// register review service disini
+	reviewService := services.NewReviewService(s.store)
+	reviewService.RegisterRoutes(subrouter)
+
+	// register wishlist service disini
+	wishlistService := services.NewWishlistService(s.store)
+	wishlistService.RegisterRoutes(subrouter)
+
+	// register productQuestion service disini
+	productQuestionService := services.NewProductQuestionService(s.store)
+	productQuestionService.RegisterRoutes(subrouter)
+
+	// register productHistory service disini
+	productHistoryService := services.NewProductHistoryService(s.store)
+	productHistoryService.RegisterRoutes(subrouter)
+
+	// register discount service disini
+	discountService := services.NewDiscountService(s.store)
+	discountService.RegisterRoutes(subrouter)
+
+	// register coupon service disini
+	couponService := services.NewCouponService(s.store)
+	couponService.RegisterRoutes(subrouter)
+
+	// register warehouse service disini
+	warehouseService := services.NewWarehouseService(s.store)
+	warehouseService.RegisterRoutes(subrouter)
+
+	// register notification service disini
+	notificationService := services.NewNotificationService(s.store)
+	notificationService.RegisterRoutes(subrouter)
+
+	// register storefront service disini
+	storefrontService := services.NewStorefrontService(s.store)
+	storefrontService.RegisterRoutes(subrouter)
+
+	// serve file media statis kalau media store bisa serve sendiri (contoh: filesystem)
+	if mediaHandler, ok := s.media.(http.Handler); ok {
+		subrouter.PathPrefix("/media/").Handler(http.StripPrefix("/v1/media", mediaHandler)).Methods(http.MethodGet, http.MethodHead)
+	}
+
 	log.Println("Server is running on:", s.listenAddr)
 	log.Fatal(http.ListenAndServe(s.listenAddr, subrouter))
 }
+
+// token hanya berlaku kalau versinya sama dengan tokenVersion user sekarang
+func (s *Server) validSession(userId string, tokenVersion int) bool {
+
+	user, err := s.store.GetUserById(userId)
+	if err != nil {
+		return false
+	}
+
+	return user.TokenVersion == tokenVersion
+}
@@ -28,6 +28,7 @@ func (s *BankAccountService) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/bank/account", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateBankAccount))).Methods(http.MethodPost)
 	r.HandleFunc("/bank/account/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateBankAccount))).Methods(http.MethodPatch)
 	r.HandleFunc("/bank/account/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteBankAccount))).Methods(http.MethodDelete)
+	r.HandleFunc("/bank/account/{id}/restore", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleRestoreBankAccount))).Methods(http.MethodPost)
 }
 
 func (s *BankAccountService) handleUpdateBankAccount(w http.ResponseWriter, r *http.Request) types.AppError {
@@ -54,6 +55,18 @@ func (s *BankAccountService) handleDeleteBankAccount(w http.ResponseWriter, r *h
 	}
 }
 
+func (s *BankAccountService) handleRestoreBankAccount(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.RestoreBankAccount(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
 func (s *BankAccountService) handleListBankAccount(w http.ResponseWriter, r *http.Request) types.AppError {
 
 	if err := usecases.ListBankAccount(s.Store, w, r); err.Error != nil {
@@ -1 +1,75 @@
 package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+)
+
+// MockStore dengan seller yang sudah punya satu rekening payout, status 2fa diatur per test case
+type payoutStore struct {
+	datastore.MockStore
+	totpEnabledAt sql.NullTime
+}
+
+func (m *payoutStore) GetUserById(id string) (*entities.User, error) {
+
+	return &entities.User{ID: id, TotpEnabledAt: m.totpEnabledAt}, nil
+}
+
+func (m *payoutStore) ListBankAccount(id string) (*[]entities.BankAccount, error) {
+
+	return &[]entities.BankAccount{{Id: "bank-account-id", BankName: "BCA", AccountName: "Budi", AccountNumber: 1234567890, SellerId: id}}, nil
+}
+
+func TestListBankAccountTwoFactor(t *testing.T) {
+
+	cases := []struct {
+		name             string
+		totpEnabledAt    sql.NullTime
+		expectedAccounts int
+	}{
+		{"Should list payout accounts of seller with two-factor enabled", sql.NullTime{Time: time.Now(), Valid: true}, 1},
+		{"Should hide existing payout accounts of seller without two-factor", sql.NullTime{}, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := payoutStore{totpEnabledAt: c.totpEnabledAt}
+			bankAccountService := NewBankAccountService(&inMemoryDb)
+
+			req := httptest.NewRequest(http.MethodGet, "/bank/account/user/"+testSellerId, nil)
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/bank/account/user/{id}", helper.CreateHandlerFunc(bankAccountService.handleListBankAccount)).Methods(http.MethodGet)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d", http.StatusOK, rr.Code)
+			}
+
+			var resp struct {
+				Data struct {
+					BankAccounts []entities.BankAccount `json:"bankAccounts"`
+				} `json:"data"`
+			}
+
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(resp.Data.BankAccounts) != c.expectedAccounts {
+				t.Errorf("Expected %d payout accounts, but got=%v", c.expectedAccounts, resp.Data.BankAccounts)
+			}
+		})
+	}
+}
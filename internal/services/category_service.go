@@ -0,0 +1,91 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type CategoryService struct {
+	Store datastore.Store
+}
+
+func NewCategoryService(s datastore.Store) *CategoryService {
+
+	return &CategoryService{
+		Store: s,
+	}
+}
+
+func (s *CategoryService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/category", helper.CreateHandlerFunc(s.handleListCategory)).Methods(http.MethodGet)
+	r.HandleFunc("/category/{slug}", helper.CreateHandlerFunc(s.handleGetCategory)).Methods(http.MethodGet)
+	r.HandleFunc("/category", helper.CreateHandlerFunc(auth.AdminMiddleware(s.handleCreateCategory))).Methods(http.MethodPost)
+	r.HandleFunc("/category/{id}", helper.CreateHandlerFunc(auth.AdminMiddleware(s.handleUpdateCategory))).Methods(http.MethodPatch)
+	r.HandleFunc("/category/{id}", helper.CreateHandlerFunc(auth.AdminMiddleware(s.handleDeleteCategory))).Methods(http.MethodDelete)
+}
+
+func (s *CategoryService) handleListCategory(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListCategory(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *CategoryService) handleGetCategory(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.GetCategory(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *CategoryService) handleCreateCategory(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateCategory(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *CategoryService) handleUpdateCategory(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UpdateCategory(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *CategoryService) handleDeleteCategory(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DeleteCategory(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/notifier"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type ContactService struct {
+	Store    datastore.Store
+	Notifier notifier.Notifier
+}
+
+func NewContactService(s datastore.Store, n notifier.Notifier) *ContactService {
+
+	return &ContactService{
+		Store:    s,
+		Notifier: n,
+	}
+}
+
+func (s *ContactService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/user/contact", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateContact))).Methods(http.MethodPut)
+	r.HandleFunc("/user/contact/verify/send", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleSendContactCode))).Methods(http.MethodPost)
+	r.HandleFunc("/user/contact/verify", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleVerifyContact))).Methods(http.MethodPost)
+}
+
+func (s *ContactService) handleUpdateContact(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UpdateContact(s.Store, s.Notifier, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ContactService) handleSendContactCode(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.SendContactCode(s.Store, s.Notifier, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusAccepted,
+	}
+}
+
+func (s *ContactService) handleVerifyContact(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.VerifyContact(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type CouponService struct {
+	Store datastore.Store
+}
+
+func NewCouponService(s datastore.Store) *CouponService {
+
+	return &CouponService{
+		Store: s,
+	}
+}
+
+func (s *CouponService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/coupon", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateCoupon))).Methods(http.MethodPost)
+	r.HandleFunc("/coupon", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleListCoupon))).Methods(http.MethodGet)
+	r.HandleFunc("/coupon/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteCoupon))).Methods(http.MethodDelete)
+}
+
+func (s *CouponService) handleCreateCoupon(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateCoupon(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *CouponService) handleListCoupon(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListCoupon(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *CouponService) handleDeleteCoupon(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DeleteCoupon(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+// discountStore dengan satu coupon, hasil claim coupon diatur per test case. aturan kuota ada di store.
+// diskon hanya aktif kalau discount.ID diisi
+type couponStore struct {
+	discountStore
+	coupon         entities.Coupon
+	couponClaimErr error
+	couponClaimed  []float64
+	couponReleased []string
+}
+
+func (m *couponStore) GetActiveDiscount(productId string) (*entities.Discount, error) {
+
+	if m.discount.ID == "" {
+		return nil, nil
+	}
+
+	return m.discountStore.GetActiveDiscount(productId)
+}
+
+func (m *couponStore) GetCouponByCode(code string) (*entities.Coupon, error) {
+
+	if code != m.coupon.Code {
+		return nil, sql.ErrNoRows
+	}
+
+	coupon := m.coupon
+
+	return &coupon, nil
+}
+
+func (m *couponStore) ClaimCoupon(couponId, userId, transactionId string, amount float64) error {
+
+	m.couponClaimed = append(m.couponClaimed, amount)
+
+	return m.couponClaimErr
+}
+
+func (m *couponStore) ReleaseCouponClaim(transactionId string) error {
+
+	m.couponReleased = append(m.couponReleased, transactionId)
+
+	return nil
+}
+
+func TestCreateTransactionCoupon(t *testing.T) {
+
+	const productId = "1f0c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+
+	percentage := entities.Coupon{Type: "percentage", Value: 10}
+	tenPercentOff := entities.Discount{ID: "discount-id", Type: "percentage", Value: 10}
+
+	cases := []struct {
+		name                   string
+		coupon                 entities.Coupon
+		discount               entities.Discount
+		code                   string
+		claimErr               error
+		stockErr               error
+		expectedCode           int
+		expectedSubtotal       float64
+		expectedCouponDiscount float64
+		expectedClaim          bool
+	}{
+		{"Should apply platform coupon", percentage, entities.Discount{}, "hemat10", nil, nil, http.StatusCreated, 200000, 20000, true},
+		{
+			"Should apply coupon on discounted subtotal",
+			entities.Coupon{Type: "percentage", Value: 10, MaxDiscount: 15000, MinSpend: 150000},
+			tenPercentOff, " hemat10 ", nil, nil, http.StatusCreated, 180000, 15000, true,
+		},
+		{"Should apply fixed coupon of product seller", entities.Coupon{Type: "fixed", Value: 25000, SellerId: testSellerId}, tenPercentOff, "HEMAT10", nil, nil, http.StatusCreated, 180000, 25000, true},
+		{"Should reject coupon below minimum spend after discount", entities.Coupon{Type: "fixed", Value: 25000, MinSpend: 190000}, tenPercentOff, "HEMAT10", nil, nil, http.StatusBadRequest, 0, 0, false},
+		{"Should reject coupon of other seller", entities.Coupon{Type: "fixed", Value: 25000, SellerId: "2b0e5b8e-3f43-4a8e-9a57-0d6f1c2e3a4b"}, entities.Discount{}, "HEMAT10", nil, nil, http.StatusBadRequest, 0, 0, false},
+		{"Should reject coupon for other product", entities.Coupon{Type: "fixed", Value: 25000, ProductIds: pq.StringArray{"2a1b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"}}, entities.Discount{}, "HEMAT10", nil, nil, http.StatusBadRequest, 0, 0, false},
+		{"Should reject expired coupon", entities.Coupon{Type: "fixed", Value: 25000, ExpiresAt: time.Now().Add(-time.Hour)}, tenPercentOff, "HEMAT10", nil, nil, http.StatusBadRequest, 0, 0, false},
+		{"Should reject unknown coupon code", percentage, tenPercentOff, "GRATIS", nil, nil, http.StatusBadRequest, 0, 0, false},
+		{"Should reject coupon over usage limit", entities.Coupon{Type: "fixed", Value: 25000}, tenPercentOff, "HEMAT10", datastore.ErrCouponUsageLimit, nil, http.StatusBadRequest, 0, 0, true},
+		{"Should reject coupon over per user limit", entities.Coupon{Type: "fixed", Value: 25000}, tenPercentOff, "HEMAT10", datastore.ErrCouponUserLimit, nil, http.StatusBadRequest, 0, 0, true},
+		{"Should return internal server error when claim fails", percentage, tenPercentOff, "HEMAT10", fmt.Errorf("connection lost"), nil, http.StatusInternalServerError, 0, 0, true},
+		{"Should release coupon when stock reservation fails", percentage, tenPercentOff, "HEMAT10", nil, datastore.ErrInsufficientStock, http.StatusBadRequest, 0, 0, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.coupon.ID = "coupon-id"
+			c.coupon.Code = "HEMAT10"
+			c.coupon.StartsAt = time.Now().Add(-time.Hour)
+			if c.coupon.ExpiresAt.IsZero() {
+				c.coupon.ExpiresAt = time.Now().Add(time.Hour)
+			}
+
+			inMemoryDb := couponStore{
+				discountStore: discountStore{
+					orderStore: orderStore{
+						products: map[string]entities.Product{productId: {Status: "published", IsPurchaseable: true, SellerId: testSellerId, Stock: 10, Price: 100000}},
+						stockErr: c.stockErr,
+					},
+					discount: c.discount,
+				},
+				coupon:         c.coupon,
+				couponClaimErr: c.claimErr,
+			}
+
+			transactionService := NewTransactionService(&inMemoryDb)
+
+			payload := map[string]interface{}{"productId": productId, "quantity": 2, "couponCode": c.code}
+			req := newAuthRequest(t, http.MethodPost, "/transaction", testBuyerId, payload)
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/transaction", helper.CreateHandlerFunc(transactionService.CreateTransaction)).Methods(http.MethodPost)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if claimed := len(inMemoryDb.couponClaimed) == 1; claimed != c.expectedClaim {
+				t.Errorf("Expected coupon claimed to be %v, but got=%v", c.expectedClaim, inMemoryDb.couponClaimed)
+			}
+
+			if c.expectedCode != http.StatusCreated {
+				// diskon dan coupon yang sudah di-claim ikut dikembalikan
+				if len(inMemoryDb.claimed) > 0 && len(inMemoryDb.released) == 0 {
+					t.Errorf("Expected discount claim released, but got claimed=%v released=%v", inMemoryDb.claimed, inMemoryDb.released)
+				}
+
+				if c.expectedClaim && len(inMemoryDb.couponReleased) == 0 {
+					t.Errorf("Expected coupon claim released, but got=%v", inMemoryDb.couponReleased)
+				}
+
+				if len(inMemoryDb.created) != 0 {
+					t.Errorf("Expected no transaction created, but got=%v", inMemoryDb.created)
+				}
+
+				return
+			}
+
+			order := inMemoryDb.order
+			if order.CouponId != "coupon-id" || order.Subtotal != c.expectedSubtotal || order.CouponDiscount != c.expectedCouponDiscount {
+				t.Errorf("Unexpected coupon breakdown, got couponId=%s subtotal=%v couponDiscount=%v", order.CouponId, order.Subtotal, order.CouponDiscount)
+			}
+
+			if inMemoryDb.couponClaimed[0] != c.expectedCouponDiscount {
+				t.Errorf("Expected coupon claimed with amount %v, but got=%v", c.expectedCouponDiscount, inMemoryDb.couponClaimed[0])
+			}
+
+			if expectedTotal := c.expectedSubtotal - c.expectedCouponDiscount; inMemoryDb.total != expectedTotal {
+				t.Errorf("Expected total %v, but got=%v", expectedTotal, inMemoryDb.total)
+			}
+
+			if len(inMemoryDb.couponReleased) != 0 {
+				t.Errorf("Expected no coupon claim released, but got=%v", inMemoryDb.couponReleased)
+			}
+		})
+	}
+}
+
+// couponStore dengan transaksi menunggu yang sudah claim diskon dan coupon
+type couponTransactionStore struct {
+	couponStore
+}
+
+func (m *couponTransactionStore) GetTransaction(id string) (*datastore.TransactionReturn, error) {
+
+	return &datastore.TransactionReturn{
+		Transaction: entities.TransactionMinimal{ID: id, Status: "menunggu"},
+		Seller:      entities.UserMinimal{ID: testSellerId},
+		Buyer:       entities.UserMinimal{ID: testBuyerId},
+	}, nil
+}
+
+func TestUpdateStatusTransactionCouponRelease(t *testing.T) {
+
+	const transactionId = "b78cd7e2-765e-4344-aa83-9b61aaa3dec4"
+
+	cases := []struct {
+		name            string
+		status          string
+		expectedRelease bool
+	}{
+		{"Should release coupon and discount when seller rejects transaction", "ditolak", true},
+		{"Should keep coupon and discount when seller accepts transaction", "diterima seller", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := couponTransactionStore{}
+			transactionService := NewTransactionService(&inMemoryDb)
+
+			req := newAuthRequest(t, http.MethodPatch, "/transaction/"+transactionId, testSellerId, map[string]string{"status": c.status})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/transaction/{id}", helper.CreateHandlerFunc(transactionService.UpdateStatusTransaction)).Methods(http.MethodPatch)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d", http.StatusOK, rr.Code)
+			}
+
+			if released := len(inMemoryDb.couponReleased) == 1 && inMemoryDb.couponReleased[0] == transactionId; released != c.expectedRelease {
+				t.Errorf("Expected coupon claim released to be %v, but got=%v", c.expectedRelease, inMemoryDb.couponReleased)
+			}
+
+			if released := len(inMemoryDb.released) == 1 && inMemoryDb.released[0] == transactionId; released != c.expectedRelease {
+				t.Errorf("Expected discount claim released to be %v, but got=%v", c.expectedRelease, inMemoryDb.released)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type DiscountService struct {
+	Store datastore.Store
+}
+
+func NewDiscountService(s datastore.Store) *DiscountService {
+
+	return &DiscountService{
+		Store: s,
+	}
+}
+
+func (s *DiscountService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/product/{id}/discount", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateDiscount))).Methods(http.MethodPost)
+	r.HandleFunc("/product/{id}/discount", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleListProductDiscount))).Methods(http.MethodGet)
+	r.HandleFunc("/product/{id}/discount/{discountId}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteDiscount))).Methods(http.MethodDelete)
+}
+
+func (s *DiscountService) handleCreateDiscount(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateDiscount(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *DiscountService) handleListProductDiscount(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListProductDiscount(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *DiscountService) handleDeleteDiscount(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DeleteDiscount(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
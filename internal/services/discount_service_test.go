@@ -0,0 +1,167 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+)
+
+// MockStore dengan diskon aktif, hasil claim diatur per test case. aturan kuota ada di store
+type discountStore struct {
+	orderStore
+	discount entities.Discount
+	claimErr error
+	claimed  []int
+	released []string
+}
+
+func (m *discountStore) GetActiveDiscount(productId string) (*entities.Discount, error) {
+
+	discount := m.discount
+
+	return &discount, nil
+}
+
+func (m *discountStore) ClaimDiscount(discountId, buyerId, transactionId string, quantity int) error {
+
+	m.claimed = append(m.claimed, quantity)
+
+	return m.claimErr
+}
+
+func (m *discountStore) ReleaseDiscountClaim(transactionId string) error {
+
+	m.released = append(m.released, transactionId)
+
+	return nil
+}
+
+func TestCreateTransactionDiscount(t *testing.T) {
+
+	const productId = "1f0c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+
+	cases := []struct {
+		name             string
+		discount         entities.Discount
+		quantity         int
+		claimErr         error
+		stockErr         error
+		expectedCode     int
+		expectedDiscount float64
+		expectedRelease  bool
+	}{
+		{"Should apply percentage discount", entities.Discount{Type: "percentage", Value: 10, PerBuyerLimit: 2}, 2, nil, nil, http.StatusCreated, 20000, false},
+		{"Should apply fixed discount per unit", entities.Discount{Type: "fixed", Value: 5000}, 5, nil, nil, http.StatusCreated, 25000, false},
+		{"Should reject when buyer cap reached", entities.Discount{Type: "percentage", Value: 10, PerBuyerLimit: 2}, 3, datastore.ErrDiscountBuyerLimit, nil, http.StatusBadRequest, 0, false},
+		{"Should return conflict when flash sale sold out", entities.Discount{Type: "percentage", Value: 50, QuantityLimit: 5, IsFlashSale: true}, 2, datastore.ErrDiscountSoldOut, nil, http.StatusConflict, 0, false},
+		{"Should return conflict when discount ended", entities.Discount{Type: "percentage", Value: 10}, 1, datastore.ErrDiscountUnavailable, nil, http.StatusConflict, 0, false},
+		{"Should release discount claim when stock reservation fails", entities.Discount{Type: "percentage", Value: 10, PerBuyerLimit: 2}, 1, nil, datastore.ErrInsufficientStock, http.StatusBadRequest, 0, true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.discount.ID = "discount-id"
+			inMemoryDb := discountStore{
+				orderStore: orderStore{
+					products: map[string]entities.Product{productId: {Status: "published", IsPurchaseable: true, SellerId: testSellerId, Stock: 10, Price: 100000}},
+					stockErr: c.stockErr,
+				},
+				discount: c.discount,
+				claimErr: c.claimErr,
+			}
+
+			transactionService := NewTransactionService(&inMemoryDb)
+
+			req := newAuthRequest(t, http.MethodPost, "/transaction", testBuyerId, map[string]interface{}{"productId": productId, "quantity": c.quantity})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/transaction", helper.CreateHandlerFunc(transactionService.CreateTransaction)).Methods(http.MethodPost)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if len(inMemoryDb.claimed) != 1 || inMemoryDb.claimed[0] != c.quantity {
+				t.Errorf("Expected one claim of %d units, but got=%v", c.quantity, inMemoryDb.claimed)
+			}
+
+			if released := len(inMemoryDb.released) > 0; released != c.expectedRelease {
+				t.Errorf("Expected claim released to be %v, but got=%v", c.expectedRelease, inMemoryDb.released)
+			}
+
+			if c.expectedCode != http.StatusCreated {
+				if len(inMemoryDb.created) != 0 {
+					t.Errorf("Expected no transaction created, but got=%v", inMemoryDb.created)
+				}
+
+				return
+			}
+
+			if inMemoryDb.order.DiscountId != "discount-id" || inMemoryDb.order.DiscountAmount != c.expectedDiscount {
+				t.Errorf("Expected discount %v, but got=%s %v", c.expectedDiscount, inMemoryDb.order.DiscountId, inMemoryDb.order.DiscountAmount)
+			}
+
+			if expectedTotal := 100000*float64(c.quantity) - c.expectedDiscount; inMemoryDb.total != expectedTotal {
+				t.Errorf("Expected total %v, but got=%v", expectedTotal, inMemoryDb.total)
+			}
+		})
+	}
+}
+
+// discountStore dengan transaksi menunggu yang sudah claim diskon
+type discountTransactionStore struct {
+	discountStore
+}
+
+func (m *discountTransactionStore) GetTransaction(id string) (*datastore.TransactionReturn, error) {
+
+	return &datastore.TransactionReturn{
+		Transaction: entities.TransactionMinimal{ID: id, Status: "menunggu"},
+		Seller:      entities.UserMinimal{ID: testSellerId},
+		Buyer:       entities.UserMinimal{ID: testBuyerId},
+	}, nil
+}
+
+func TestUpdateStatusTransactionDiscountRelease(t *testing.T) {
+
+	const transactionId = "b78cd7e2-765e-4344-aa83-9b61aaa3dec4"
+
+	cases := []struct {
+		name            string
+		status          string
+		expectedRelease bool
+	}{
+		{"Should release discount claim when seller rejects transaction", "ditolak", true},
+		{"Should keep discount claim when seller accepts transaction", "diterima seller", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := discountTransactionStore{}
+			transactionService := NewTransactionService(&inMemoryDb)
+
+			req := newAuthRequest(t, http.MethodPatch, "/transaction/"+transactionId, testSellerId, map[string]string{"status": c.status})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/transaction/{id}", helper.CreateHandlerFunc(transactionService.UpdateStatusTransaction)).Methods(http.MethodPatch)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d", http.StatusOK, rr.Code)
+			}
+
+			released := len(inMemoryDb.released) == 1 && inMemoryDb.released[0] == transactionId
+			if released != c.expectedRelease {
+				t.Errorf("Expected claim of %s released to be %v, but got=%v", transactionId, c.expectedRelease, inMemoryDb.released)
+			}
+		})
+	}
+}
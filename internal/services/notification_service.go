@@ -0,0 +1,65 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type NotificationService struct {
+	Store datastore.Store
+}
+
+func NewNotificationService(s datastore.Store) *NotificationService {
+
+	return &NotificationService{
+		Store: s,
+	}
+}
+
+func (s *NotificationService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/notification", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleListNotification))).Methods(http.MethodGet)
+	r.HandleFunc("/notification/read", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleReadAllNotification))).Methods(http.MethodPost)
+	r.HandleFunc("/notification/{id}/read", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleReadNotification))).Methods(http.MethodPost)
+}
+
+func (s *NotificationService) handleListNotification(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListNotification(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *NotificationService) handleReadNotification(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ReadNotification(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *NotificationService) handleReadAllNotification(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ReadAllNotification(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
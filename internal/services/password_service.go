@@ -0,0 +1,68 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/notifier"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type PasswordService struct {
+	Store    datastore.Store
+	Notifier notifier.Notifier
+}
+
+func NewPasswordService(s datastore.Store, n notifier.Notifier) *PasswordService {
+
+	return &PasswordService{
+		Store:    s,
+		Notifier: n,
+	}
+}
+
+func (s *PasswordService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/user/password", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleChangePassword))).Methods(http.MethodPost)
+	r.HandleFunc("/user/password/forgot", helper.CreateHandlerFunc(s.handleForgotPassword)).Methods(http.MethodPost)
+	r.HandleFunc("/user/password/reset", helper.CreateHandlerFunc(s.handleResetPassword)).Methods(http.MethodPost)
+}
+
+func (s *PasswordService) handleChangePassword(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ChangePassword(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *PasswordService) handleForgotPassword(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ForgotPassword(s.Store, s.Notifier, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusAccepted,
+	}
+}
+
+func (s *PasswordService) handleResetPassword(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ResetPassword(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
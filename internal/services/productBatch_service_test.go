@@ -0,0 +1,207 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+)
+
+// MockStore dengan hasil batch yang sudah ditentukan per test case, item dan mode yang diterima store dicatat
+type batchStore struct {
+	datastore.MockStore
+	results  []entities.ProductBatchResult
+	batchErr error
+	calls    int
+	received []entities.ProductBatchItem
+	atomic   bool
+	history  []string
+}
+
+func (m *batchStore) GetProductById(id string) (*entities.Product, error) {
+
+	return &entities.Product{ID: id, SellerId: testSellerId, Status: "published"}, nil
+}
+
+func (m *batchStore) BatchUpdateProducts(sellerId string, items []entities.ProductBatchItem, atomic bool) ([]entities.ProductBatchResult, error) {
+
+	m.calls++
+	m.received = items
+	m.atomic = atomic
+
+	return m.results, m.batchErr
+}
+
+func (m *batchStore) CreateProductHistory(productId, changedBy string, before, after *entities.Product) error {
+
+	m.history = append(m.history, productId)
+
+	return nil
+}
+
+func TestBatchUpdateProduct(t *testing.T) {
+
+	const (
+		kaosId   = "1f0c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+		celanaId = "2a1b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+	)
+
+	type item map[string]interface{}
+
+	ok := func(productId string) entities.ProductBatchResult {
+		return entities.ProductBatchResult{ProductId: productId, Success: true}
+	}
+	failed := func(productId, err string) entities.ProductBatchResult {
+		return entities.ProductBatchResult{ProductId: productId, Error: err}
+	}
+
+	cases := []struct {
+		name             string
+		mode             string
+		items            []item
+		storeResults     []entities.ProductBatchResult
+		storeErr         error
+		expectedCode     int
+		expectedReceived int
+		expectedAtomic   bool
+		expectedSuccess  []bool
+	}{
+		{
+			"Atomic should send all items to store",
+			"atomic",
+			[]item{{"productId": kaosId, "delta": -2}, {"productId": celanaId, "stock": 20, "price": 75000}},
+			[]entities.ProductBatchResult{ok(kaosId), ok(celanaId)}, nil,
+			http.StatusOK, 2, true,
+			[]bool{true, true},
+		},
+		{
+			"Should default to atomic mode",
+			"",
+			[]item{{"productId": kaosId, "delta": 1}},
+			[]entities.ProductBatchResult{ok(kaosId)}, nil,
+			http.StatusOK, 1, true,
+			[]bool{true},
+		},
+		{
+			"Atomic should return bad request when store rejects batch",
+			"atomic",
+			[]item{{"productId": kaosId, "delta": -2}, {"productId": celanaId, "delta": -3}},
+			[]entities.ProductBatchResult{
+				failed(kaosId, "Not applied, another item in the batch failed"),
+				failed(celanaId, datastore.ErrInsufficientStock.Error()),
+			}, datastore.ErrProductBatchFailed,
+			http.StatusBadRequest, 2, true,
+			[]bool{false, false},
+		},
+		{
+			"Atomic should not reach store when one item is invalid",
+			"atomic",
+			[]item{{"productId": kaosId, "delta": -2}, {"productId": celanaId, "stock": 1, "delta": 1}},
+			nil, nil,
+			http.StatusBadRequest, 0, false,
+			[]bool{false, false},
+		},
+		{
+			"Best effort should send only valid items and keep original index",
+			"best_effort",
+			[]item{{"productId": kaosId, "stock": -1}, {"productId": celanaId, "delta": 1}},
+			[]entities.ProductBatchResult{ok(celanaId)}, nil,
+			http.StatusOK, 1, false,
+			[]bool{false, true},
+		},
+		{
+			"Best effort should return store result per item",
+			"best_effort",
+			[]item{{"productId": kaosId, "delta": -1}, {"productId": celanaId, "delta": -3}},
+			[]entities.ProductBatchResult{ok(kaosId), failed(celanaId, datastore.ErrInsufficientStock.Error())}, nil,
+			http.StatusOK, 2, false,
+			[]bool{true, false},
+		},
+		{
+			"Should return internal server error when store fails",
+			"best_effort",
+			[]item{{"productId": kaosId, "delta": 1}},
+			nil, fmt.Errorf("connection lost"),
+			http.StatusInternalServerError, 1, false,
+			nil,
+		},
+		{
+			"Should reject unknown mode",
+			"partial",
+			[]item{{"productId": kaosId, "delta": 1}},
+			nil, nil,
+			http.StatusBadRequest, 0, false,
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := batchStore{results: c.storeResults, batchErr: c.storeErr}
+			productService := NewProductService(&inMemoryDb)
+
+			req := newAuthRequest(t, http.MethodPost, "/product/batch", testSellerId, map[string]interface{}{"mode": c.mode, "items": c.items})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/product/batch", helper.CreateHandlerFunc(productService.handleBatchUpdateProduct)).Methods(http.MethodPost)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if len(inMemoryDb.received) != c.expectedReceived {
+				t.Errorf("Expected %d items sent to store, but got=%v", c.expectedReceived, inMemoryDb.received)
+			}
+
+			if inMemoryDb.calls > 0 && inMemoryDb.atomic != c.expectedAtomic {
+				t.Errorf("Expected atomic=%v, but got=%v", c.expectedAtomic, inMemoryDb.atomic)
+			}
+
+			if c.expectedSuccess == nil {
+				return
+			}
+
+			var resp struct {
+				Data struct {
+					Results []entities.ProductBatchResult `json:"results"`
+				} `json:"data"`
+			}
+
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(resp.Data.Results) != len(c.expectedSuccess) {
+				t.Fatalf("Expected %d results, but got=%v", len(c.expectedSuccess), resp.Data.Results)
+			}
+
+			succeeded := 0
+			for i, result := range resp.Data.Results {
+				if result.Index != i || result.Success != c.expectedSuccess[i] {
+					t.Errorf("Unexpected result at %d, got=%+v", i, result)
+				}
+
+				if !result.Success && result.Error == "" {
+					t.Errorf("Expected error message for failed item %d", i)
+				}
+
+				if result.Success {
+					succeeded++
+				}
+			}
+
+			// history hanya untuk product yang benar-benar berubah
+			if len(inMemoryDb.history) != succeeded {
+				t.Errorf("Expected %d history records, but got=%v", succeeded, inMemoryDb.history)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type ProductHistoryService struct {
+	Store datastore.Store
+}
+
+func NewProductHistoryService(s datastore.Store) *ProductHistoryService {
+
+	return &ProductHistoryService{
+		Store: s,
+	}
+}
+
+func (s *ProductHistoryService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/product/{id}/history", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleListProductHistory))).Methods(http.MethodGet)
+	r.HandleFunc("/product/{id}/price-history", helper.CreateHandlerFunc(auth.OptionalJWTMiddleware(s.handleListProductPriceHistory))).Methods(http.MethodGet)
+}
+
+func (s *ProductHistoryService) handleListProductHistory(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListProductHistory(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductHistoryService) handleListProductPriceHistory(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListProductPriceHistory(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/media"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type ProductImageService struct {
+	Store datastore.Store
+	Media media.MediaStore
+}
+
+func NewProductImageService(s datastore.Store, m media.MediaStore) *ProductImageService {
+
+	return &ProductImageService{
+		Store: s,
+		Media: m,
+	}
+}
+
+func (s *ProductImageService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/product/{id}/image", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUploadProductImage))).Methods(http.MethodPost)
+	r.HandleFunc("/product/{id}/image", helper.CreateHandlerFunc(auth.OptionalJWTMiddleware(s.handleListProductImage))).Methods(http.MethodGet)
+	r.HandleFunc("/product/{id}/image/order", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleReorderProductImage))).Methods(http.MethodPut)
+	r.HandleFunc("/product/{id}/image/{imageId}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteProductImage))).Methods(http.MethodDelete)
+}
+
+func (s *ProductImageService) handleUploadProductImage(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UploadProductImage(s.Store, s.Media, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *ProductImageService) handleListProductImage(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListProductImage(s.Store, s.Media, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductImageService) handleReorderProductImage(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ReorderProductImage(s.Store, s.Media, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductImageService) handleDeleteProductImage(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DeleteProductImage(s.Store, s.Media, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
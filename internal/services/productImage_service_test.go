@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/media"
+	"github.com/gorilla/mux"
+)
+
+// MockStore yang mencatat gambar yang disimpan
+type productImageStore struct {
+	datastore.MockStore
+	created   []entities.ProductImage
+	createErr error
+}
+
+func (m *productImageStore) CreateProductImages(productId string, imgs []entities.ProductImage, maxImages int) error {
+
+	if m.createErr != nil {
+		return m.createErr
+	}
+
+	m.created = append(m.created, imgs...)
+
+	return nil
+}
+
+func TestUploadProductImage(t *testing.T) {
+
+	var pngBuf bytes.Buffer
+	if err := png.Encode(&pngBuf, image.NewRGBA(image.Rect(0, 0, 20, 20))); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name          string
+		files         [][]byte
+		createErr     error
+		expectedCode  int
+		expectedSaved int
+	}{
+		{"Should upload all valid images", [][]byte{pngBuf.Bytes(), pngBuf.Bytes()}, nil, http.StatusCreated, 2},
+		{"Should not save any image when one file is invalid", [][]byte{pngBuf.Bytes(), []byte("<html></html>")}, nil, http.StatusBadRequest, 0},
+		{"Should remove uploaded files when image limit reached", [][]byte{pngBuf.Bytes()}, datastore.ErrProductImageLimit, http.StatusBadRequest, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			root := t.TempDir()
+			mediaStore, err := media.NewFileSystemStore(root, "/v1/media")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			inMemoryDb := productImageStore{createErr: c.createErr}
+			productImageService := NewProductImageService(&inMemoryDb, mediaStore)
+
+			var body bytes.Buffer
+			writer := multipart.NewWriter(&body)
+			for _, file := range c.files {
+				part, err := writer.CreateFormFile("image", "image.png")
+				if err != nil {
+					t.Fatal(err)
+				}
+				part.Write(file)
+			}
+			writer.Close()
+
+			req := newAuthRequest(t, http.MethodPost, "/product/b78cd7e2-765e-4344-aa83-9b61aaa3dec4/image", testSellerId, nil)
+			req.Body = io.NopCloser(bytes.NewReader(body.Bytes()))
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/product/{id}/image", helper.CreateHandlerFunc(productImageService.handleUploadProductImage)).Methods(http.MethodPost)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Errorf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if len(inMemoryDb.created) != c.expectedSaved {
+				t.Errorf("Expected %d images saved, but got=%d", c.expectedSaved, len(inMemoryDb.created))
+			}
+
+			files := 0
+			filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					files++
+				}
+				return nil
+			})
+
+			if expectedFiles := c.expectedSaved * (len(media.ThumbnailSizes) + 1); files != expectedFiles {
+				t.Errorf("Expected %d files on disk, but got=%d", expectedFiles, files)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type ProductQuestionService struct {
+	Store datastore.Store
+}
+
+func NewProductQuestionService(s datastore.Store) *ProductQuestionService {
+
+	return &ProductQuestionService{
+		Store: s,
+	}
+}
+
+func (s *ProductQuestionService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/product/{id}/question", helper.CreateHandlerFunc(auth.OptionalJWTMiddleware(s.handleListProductQuestion))).Methods(http.MethodGet)
+	r.HandleFunc("/product/{id}/question", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateProductQuestion))).Methods(http.MethodPost)
+	r.HandleFunc("/product/{id}/question/{questionId}/answer", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleAnswerProductQuestion))).Methods(http.MethodPut)
+	r.HandleFunc("/product/{id}/question/{questionId}/visibility", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleHideProductQuestion))).Methods(http.MethodPatch)
+	r.HandleFunc("/product/{id}/question/{questionId}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteProductQuestion))).Methods(http.MethodDelete)
+}
+
+func (s *ProductQuestionService) handleListProductQuestion(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListProductQuestion(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductQuestionService) handleCreateProductQuestion(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateProductQuestion(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *ProductQuestionService) handleAnswerProductQuestion(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.AnswerProductQuestion(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductQuestionService) handleHideProductQuestion(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.HideProductQuestion(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductQuestionService) handleDeleteProductQuestion(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DeleteProductQuestion(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type ProductVariantService struct {
+	Store datastore.Store
+}
+
+func NewProductVariantService(s datastore.Store) *ProductVariantService {
+
+	return &ProductVariantService{
+		Store: s,
+	}
+}
+
+func (s *ProductVariantService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/product/{id}/variant", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateProductVariant))).Methods(http.MethodPost)
+	r.HandleFunc("/product/{id}/variant", helper.CreateHandlerFunc(auth.OptionalJWTMiddleware(s.handleListProductVariant))).Methods(http.MethodGet)
+	r.HandleFunc("/product/{id}/variant/{variantId}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateProductVariant))).Methods(http.MethodPatch)
+	r.HandleFunc("/product/{id}/variant/{variantId}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteProductVariant))).Methods(http.MethodDelete)
+}
+
+func (s *ProductVariantService) handleCreateProductVariant(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateProductVariant(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *ProductVariantService) handleListProductVariant(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListProductVariant(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductVariantService) handleUpdateProductVariant(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UpdateProductVariant(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductVariantService) handleDeleteProductVariant(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DeleteProductVariant(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
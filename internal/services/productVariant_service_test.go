@@ -0,0 +1,142 @@
+package services
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+)
+
+// MockStore dengan product yang bisa diatur per test case
+type productStore struct {
+	datastore.MockStore
+	product entities.Product
+}
+
+func (m *productStore) GetProductById(id string) (*entities.Product, error) {
+
+	product := m.product
+	product.ID = id
+
+	return &product, nil
+}
+
+func TestListProductVariantVisibility(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		product      entities.Product
+		userId       string
+		expectedCode int
+	}{
+		{"Should list variants of published product", entities.Product{Status: "published", SellerId: testSellerId}, "", http.StatusOK},
+		{"Should 404 for draft product", entities.Product{Status: "draft", SellerId: testSellerId}, "", http.StatusNotFound},
+		{"Should 404 for scheduled product for other user", entities.Product{Status: "scheduled", SellerId: testSellerId}, testBuyerId, http.StatusNotFound},
+		{"Should 404 for archived product", entities.Product{Status: "archived", SellerId: testSellerId}, "", http.StatusNotFound},
+		{"Should list variants of draft product for its seller", entities.Product{Status: "draft", SellerId: testSellerId}, testSellerId, http.StatusOK},
+		{
+			"Should 404 for deleted product",
+			entities.Product{Status: "published", SellerId: testSellerId, DeletedAt: sql.NullTime{Time: time.Now(), Valid: true}},
+			testSellerId,
+			http.StatusNotFound,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := productStore{product: c.product}
+			productVariantService := NewProductVariantService(&inMemoryDb)
+
+			req := newAuthRequest(t, http.MethodGet, "/product/b78cd7e2-765e-4344-aa83-9b61aaa3dec4/variant", c.userId, nil)
+			if c.userId == "" {
+				req.Header.Del("authorization")
+			}
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/product/{id}/variant", helper.CreateHandlerFunc(productVariantService.handleListProductVariant)).Methods(http.MethodGet)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Errorf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+		})
+	}
+}
+
+// MockStore dengan satu variant yang harganya bisa diubah, history yang dicatat disimpan
+type productVariantStore struct {
+	datastore.MockStore
+	variant entities.ProductVariant
+	before  *entities.Product
+	after   *entities.Product
+}
+
+func (m *productVariantStore) GetProductVariant(id string) (*entities.ProductVariant, error) {
+
+	variant := m.variant
+
+	return &variant, nil
+}
+
+func (m *productVariantStore) ListProductVariants(productId string) (*[]entities.ProductVariant, error) {
+
+	return &[]entities.ProductVariant{m.variant}, nil
+}
+
+func (m *productVariantStore) UpdateProductVariant(id string, v *entities.ProductVariant) error {
+
+	m.variant.Price = v.Price
+
+	return nil
+}
+
+func (m *productVariantStore) CreateProductHistory(productId, changedBy string, before, after *entities.Product) error {
+
+	m.before = before
+	m.after = after
+
+	return nil
+}
+
+func TestUpdateProductVariantHistory(t *testing.T) {
+
+	productId := "b78cd7e2-765e-4344-aa83-9b61aaa3dec4"
+	variantId := "5d1c7b0e-2f4a-4c1e-8b3d-6a7f9e0c1d2b"
+	oldPrice := 55000.0
+
+	t.Run("Should record variant price change in product history", func(t *testing.T) {
+		inMemoryDb := productVariantStore{variant: entities.ProductVariant{ID: variantId, ProductId: productId, Price: &oldPrice}}
+		productVariantService := NewProductVariantService(&inMemoryDb)
+
+		payload := map[string]interface{}{"sku": "KAOS-XL", "name": "XL", "price": 60000, "stock": 5}
+		req := newAuthRequest(t, http.MethodPatch, "/product/"+productId+"/variant/"+variantId, testSellerId, payload)
+
+		rr := httptest.NewRecorder()
+		router := mux.NewRouter()
+		router.HandleFunc("/product/{id}/variant/{variantId}", helper.CreateHandlerFunc(productVariantService.handleUpdateProductVariant)).Methods(http.MethodPatch)
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Invalid status code, expected: %d, but got: %d", http.StatusOK, rr.Code)
+		}
+
+		if inMemoryDb.before == nil || inMemoryDb.after == nil {
+			t.Fatal("Expected product history to be recorded")
+		}
+
+		if price := inMemoryDb.before.Variants[0].Price; price == nil || *price != oldPrice {
+			t.Errorf("Expected previous variant price %v, but got=%v", oldPrice, price)
+		}
+
+		if price := inMemoryDb.after.Variants[0].Price; price == nil || *price != 60000 {
+			t.Errorf("Expected new variant price 60000, but got=%v", price)
+		}
+	})
+}
@@ -24,11 +24,34 @@ func NewProductService(s datastore.Store) *ProductService {
 
 func (s *ProductService) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/product", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateProduct))).Methods(http.MethodPost)
+	r.HandleFunc("/product/suggest", helper.CreateHandlerFunc(s.handleSuggestProduct)).Methods(http.MethodGet)
+	r.HandleFunc("/product/import", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleImportProduct))).Methods(http.MethodPost)
+	r.HandleFunc("/product/import/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleGetProductImport))).Methods(http.MethodGet)
+	r.HandleFunc("/product/export", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleExportProduct))).Methods(http.MethodGet)
+	r.HandleFunc("/product/batch", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleBatchUpdateProduct))).Methods(http.MethodPost)
 	r.HandleFunc("/product/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateProduct))).Methods(http.MethodPatch)
-	r.HandleFunc("/product/{id}", helper.CreateHandlerFunc(s.handleGetProduct)).Methods(http.MethodGet)
-	r.HandleFunc("/product", helper.CreateHandlerFunc(s.handleListProduct)).Methods(http.MethodGet)
+	r.HandleFunc("/product/{id}", helper.CreateHandlerFunc(auth.OptionalJWTMiddleware(s.handleGetProduct))).Methods(http.MethodGet)
+	r.HandleFunc("/product", helper.CreateHandlerFunc(auth.OptionalJWTMiddleware(s.handleListProduct))).Methods(http.MethodGet)
 	r.HandleFunc("/product/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteProduct))).Methods(http.MethodDelete)
+	r.HandleFunc("/product/{id}/status", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateProductStatus))).Methods(http.MethodPatch)
+	r.HandleFunc("/product/{id}/restore", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleRestoreProduct))).Methods(http.MethodPost)
 	r.HandleFunc("/product/{id}/stock", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateStock))).Methods(http.MethodPost)
+	r.HandleFunc("/product/{id}/stock/movements", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleListStockMovements))).Methods(http.MethodGet)
+	r.HandleFunc("/product/{id}/stock/threshold", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateLowStockThreshold))).Methods(http.MethodPut)
+	r.HandleFunc("/product/{id}/stock/subscribe", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleSubscribeStock))).Methods(http.MethodPost)
+	r.HandleFunc("/product/{id}/stock/subscribe", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUnsubscribeStock))).Methods(http.MethodDelete)
+}
+
+func (s *ProductService) handleSuggestProduct(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.SuggestProduct(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
 }
 
 func (s *ProductService) handleUpdateStock(w http.ResponseWriter, r *http.Request) types.AppError {
@@ -43,6 +66,102 @@ func (s *ProductService) handleUpdateStock(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+func (s *ProductService) handleListStockMovements(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListStockMovements(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductService) handleUpdateLowStockThreshold(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UpdateLowStockThreshold(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductService) handleSubscribeStock(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.SubscribeStock(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductService) handleUnsubscribeStock(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UnsubscribeStock(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductService) handleImportProduct(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ImportProduct(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusAccepted,
+	}
+}
+
+func (s *ProductService) handleGetProductImport(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.GetProductImport(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductService) handleExportProduct(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ExportProduct(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductService) handleBatchUpdateProduct(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.BatchUpdateProduct(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
 func (s *ProductService) handleDeleteProduct(w http.ResponseWriter, r *http.Request) types.AppError {
 
 	if err := usecases.DeleteProduct(s.Store, w, r); err.Error != nil {
@@ -56,6 +175,32 @@ func (s *ProductService) handleDeleteProduct(w http.ResponseWriter, r *http.Requ
 	}
 }
 
+func (s *ProductService) handleUpdateProductStatus(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	err := usecases.UpdateProductStatus(s.Store, w, r)
+	if err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ProductService) handleRestoreProduct(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	err := usecases.RestoreProduct(s.Store, w, r)
+	if err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
 func (s *ProductService) handleListProduct(w http.ResponseWriter, r *http.Request) types.AppError {
 
 	if err := usecases.ListProduct(s.Store, w, r); err.Error != nil {
@@ -124,7 +124,7 @@ func TestEditProduct(t *testing.T) {
 			SellerId:       userId,
 		}
 
-		token, err := auth.CreateJWT(userId, "qnqwienidbfsldjlsdf")
+		token, err := auth.CreateJWT(userId, 0, "qnqwienidbfsldjlsdf")
 		if err != nil {
 			t.Error(err)
 		}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type ReviewService struct {
+	Store datastore.Store
+}
+
+func NewReviewService(s datastore.Store) *ReviewService {
+
+	return &ReviewService{
+		Store: s,
+	}
+}
+
+func (s *ReviewService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/transaction/{id}/review", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateReview))).Methods(http.MethodPost)
+	r.HandleFunc("/product/{id}/review", helper.CreateHandlerFunc(auth.OptionalJWTMiddleware(s.handleListProductReview))).Methods(http.MethodGet)
+	r.HandleFunc("/review/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateReview))).Methods(http.MethodPatch)
+	r.HandleFunc("/review/{id}/reply", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleReplyReview))).Methods(http.MethodPut)
+	r.HandleFunc("/review/{id}/vote", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleVoteReview))).Methods(http.MethodPost)
+	r.HandleFunc("/review/{id}/vote", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUnvoteReview))).Methods(http.MethodDelete)
+}
+
+func (s *ReviewService) handleCreateReview(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateReview(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *ReviewService) handleListProductReview(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListProductReview(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ReviewService) handleUpdateReview(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UpdateReview(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ReviewService) handleReplyReview(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ReplyReview(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ReviewService) handleVoteReview(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.VoteReview(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *ReviewService) handleUnvoteReview(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UnvoteReview(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
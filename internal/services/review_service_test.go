@@ -0,0 +1,153 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+// MockStore dengan transaksi dan review yang bisa diatur per test case
+type reviewStore struct {
+	datastore.MockStore
+	tx        datastore.TransactionReturn
+	reviewed  bool
+	created   bool
+	replied   bool
+	voted     bool
+	createErr error
+}
+
+func (m *reviewStore) GetTransaction(id string) (*datastore.TransactionReturn, error) {
+
+	tx := m.tx
+
+	return &tx, nil
+}
+
+func (m *reviewStore) CreateReview(id string, r *entities.Review) error {
+
+	if m.reviewed {
+		return &pq.Error{Code: "23505"}
+	}
+
+	m.created = true
+
+	return nil
+}
+
+func (m *reviewStore) GetReview(id string) (*entities.Review, error) {
+
+	return &entities.Review{ID: id, BuyerId: testBuyerId, SellerId: testSellerId, Rating: 5}, nil
+}
+
+func (m *reviewStore) ReplyReview(id, reply string) error {
+
+	m.replied = true
+
+	return nil
+}
+
+func (m *reviewStore) VoteReview(reviewId, userId string) error {
+
+	m.voted = true
+
+	return nil
+}
+
+func TestCreateReview(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		userId       string
+		status       string
+		rating       int
+		reviewed     bool
+		expectedCode int
+	}{
+		{"Buyer should review received transaction", testBuyerId, "diterima", 5, false, http.StatusCreated},
+		{"Buyer should not review waiting transaction", testBuyerId, "menunggu", 5, false, http.StatusBadRequest},
+		{"Buyer should not review accepted transaction", testBuyerId, "diterima seller", 4, false, http.StatusBadRequest},
+		{"Buyer should not review shipped transaction", testBuyerId, "dalam pengiriman", 4, false, http.StatusBadRequest},
+		{"Buyer should not review rejected transaction", testBuyerId, "ditolak", 1, false, http.StatusBadRequest},
+		{"Seller should not review own transaction", testSellerId, "diterima", 5, false, http.StatusForbidden},
+		{"Other user should not review transaction", "2b0e5b8e-3f43-4a8e-9a57-0d6f1c2e3a4b", "diterima", 5, false, http.StatusForbidden},
+		{"Should reject rating below 1", testBuyerId, "diterima", 0, false, http.StatusBadRequest},
+		{"Should reject rating above 5", testBuyerId, "diterima", 6, false, http.StatusBadRequest},
+		{"Should reject second review for same transaction", testBuyerId, "diterima", 5, true, http.StatusConflict},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := reviewStore{
+				tx: datastore.TransactionReturn{
+					Transaction: entities.TransactionMinimal{ID: "b78cd7e2-765e-4344-aa83-9b61aaa3dec4", Status: c.status},
+					Seller:      entities.UserMinimal{ID: testSellerId},
+					Buyer:       entities.UserMinimal{ID: testBuyerId},
+				},
+				reviewed: c.reviewed,
+			}
+			reviewService := NewReviewService(&inMemoryDb)
+
+			payload := map[string]interface{}{"rating": c.rating, "body": "Barang sesuai"}
+			req := newAuthRequest(t, http.MethodPost, "/transaction/b78cd7e2-765e-4344-aa83-9b61aaa3dec4/review", c.userId, payload)
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/transaction/{id}/review", helper.CreateHandlerFunc(reviewService.handleCreateReview)).Methods(http.MethodPost)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Errorf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if inMemoryDb.created != (c.expectedCode == http.StatusCreated) {
+				t.Errorf("Expected review created to be %v, but got=%v", c.expectedCode == http.StatusCreated, inMemoryDb.created)
+			}
+		})
+	}
+}
+
+func TestReplyAndVoteReview(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		method       string
+		url          string
+		userId       string
+		expectedCode int
+	}{
+		{"Seller should reply review", http.MethodPut, "/review/b78cd7e2-765e-4344-aa83-9b61aaa3dec4/reply", testSellerId, http.StatusOK},
+		{"Buyer should not reply review", http.MethodPut, "/review/b78cd7e2-765e-4344-aa83-9b61aaa3dec4/reply", testBuyerId, http.StatusForbidden},
+		{"Other user should vote review", http.MethodPost, "/review/b78cd7e2-765e-4344-aa83-9b61aaa3dec4/vote", testSellerId, http.StatusOK},
+		{"Buyer should not vote own review", http.MethodPost, "/review/b78cd7e2-765e-4344-aa83-9b61aaa3dec4/vote", testBuyerId, http.StatusBadRequest},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := reviewStore{}
+			reviewService := NewReviewService(&inMemoryDb)
+
+			req := newAuthRequest(t, c.method, c.url, c.userId, map[string]string{"reply": "Terima kasih"})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/review/{id}/reply", helper.CreateHandlerFunc(reviewService.handleReplyReview)).Methods(http.MethodPut)
+			router.HandleFunc("/review/{id}/vote", helper.CreateHandlerFunc(reviewService.handleVoteReview)).Methods(http.MethodPost)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Errorf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if changed := inMemoryDb.replied || inMemoryDb.voted; changed != (c.expectedCode == http.StatusOK) {
+				t.Errorf("Expected review changed to be %v, but got=%v", c.expectedCode == http.StatusOK, changed)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type StorefrontService struct {
+	Store datastore.Store
+}
+
+func NewStorefrontService(s datastore.Store) *StorefrontService {
+
+	return &StorefrontService{
+		Store: s,
+	}
+}
+
+func (s *StorefrontService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/storefront", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateStorefront))).Methods(http.MethodPost)
+	r.HandleFunc("/storefront", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleGetMyStorefront))).Methods(http.MethodGet)
+	r.HandleFunc("/storefront", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateStorefront))).Methods(http.MethodPatch)
+	r.HandleFunc("/storefront/{slug}", helper.CreateHandlerFunc(s.handleGetStorefront)).Methods(http.MethodGet)
+	r.HandleFunc("/storefront/{slug}/product", helper.CreateHandlerFunc(s.handleListStorefrontProduct)).Methods(http.MethodGet)
+}
+
+func (s *StorefrontService) handleCreateStorefront(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateStorefront(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *StorefrontService) handleGetMyStorefront(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.GetMyStorefront(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *StorefrontService) handleUpdateStorefront(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UpdateStorefront(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *StorefrontService) handleGetStorefront(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.GetStorefront(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *StorefrontService) handleListStorefrontProduct(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListStorefrontProduct(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+)
+
+// MockStore yang hanya mencatat storefront dan product yang disimpan,
+// aturan vacation (vacationPaused) ada di store jadi tidak ditiru di sini
+type vacationStore struct {
+	datastore.MockStore
+	storefront   entities.Storefront
+	saved        *entities.Storefront
+	savedProduct *entities.Product
+}
+
+func (m *vacationStore) GetStorefrontBySeller(sellerId string) (*entities.Storefront, error) {
+
+	storefront := m.storefront
+
+	return &storefront, nil
+}
+
+func (m *vacationStore) UpdateStorefront(id string, f *entities.Storefront) error {
+
+	saved := *f
+	m.saved = &saved
+
+	return nil
+}
+
+func (m *vacationStore) UpdateProduct(id string, p *entities.Product) error {
+
+	saved := *p
+	m.savedProduct = &saved
+
+	return nil
+}
+
+func TestUpdateStorefrontVacation(t *testing.T) {
+
+	cases := []struct {
+		name             string
+		currentVacation  bool
+		payload          map[string]interface{}
+		expectedVacation bool
+	}{
+		{"Should turn vacation on", false, map[string]interface{}{"vacationMode": true}, true},
+		{"Should turn vacation off", true, map[string]interface{}{"vacationMode": false}, false},
+		{"Should keep vacation on when field is omitted", true, map[string]interface{}{"name": "Toko Budi"}, true},
+		{"Should keep vacation off when field is omitted", false, map[string]interface{}{"name": "Toko Budi"}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := vacationStore{
+				storefront: entities.Storefront{ID: "storefront-id", SellerId: testSellerId, Name: "Toko Budi", Slug: "toko-budi", VacationMode: c.currentVacation},
+			}
+
+			req := newAuthRequest(t, http.MethodPatch, "/storefront", testSellerId, c.payload)
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/storefront", helper.CreateHandlerFunc(NewStorefrontService(&inMemoryDb).handleUpdateStorefront)).Methods(http.MethodPatch)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d (%s)", http.StatusOK, rr.Code, rr.Body.String())
+			}
+
+			if inMemoryDb.saved == nil || inMemoryDb.saved.VacationMode != c.expectedVacation {
+				t.Errorf("Expected storefront saved with vacationMode=%v, but got=%+v", c.expectedVacation, inMemoryDb.saved)
+			}
+		})
+	}
+}
+
+func TestUpdateProductIsPurchaseable(t *testing.T) {
+
+	cases := []struct {
+		name           string
+		isPurchaseable bool
+	}{
+		{"Should pass isPurchaseable true to store", true},
+		{"Should pass isPurchaseable false to store", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := vacationStore{}
+
+			payload := map[string]interface{}{
+				"name":           "Kaos",
+				"price":          50000,
+				"condition":      "new",
+				"isPurchaseable": c.isPurchaseable,
+			}
+			req := newAuthRequest(t, http.MethodPatch, "/product/1f0c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f", testSellerId, payload)
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/product/{id}", helper.CreateHandlerFunc(NewProductService(&inMemoryDb).handleUpdateProduct)).Methods(http.MethodPatch)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d (%s)", http.StatusOK, rr.Code, rr.Body.String())
+			}
+
+			// keputusan vacation ada di store, usecase meneruskan nilai dari seller apa adanya
+			if inMemoryDb.savedProduct == nil || inMemoryDb.savedProduct.IsPurchaseable != c.isPurchaseable {
+				t.Errorf("Expected product saved with isPurchaseable=%v, but got=%+v", c.isPurchaseable, inMemoryDb.savedProduct)
+			}
+		})
+	}
+}
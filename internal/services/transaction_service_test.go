@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+)
+
+const (
+	testJWTSecret = "qnqwienidbfsldjlsdf"
+	testBuyerId   = "0c7f3a52-8c1e-4a4b-9d4e-1f2a3b4c5d6e"
+	testSellerId  = "75ea96d2-8077-48aa-aad6-a02fbd282f3c"
+)
+
+// MockStore dengan transaksi yang bisa diatur per test case
+type transactionStore struct {
+	datastore.MockStore
+	tx        datastore.TransactionReturn
+	updated   string
+	updateErr error
+}
+
+func (m *transactionStore) GetTransaction(id string) (*datastore.TransactionReturn, error) {
+
+	tx := m.tx
+
+	return &tx, nil
+}
+
+func (m *transactionStore) UpdateStatusTransaction(id, from, status string) error {
+
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+
+	m.updated = status
+
+	return nil
+}
+
+func (m *transactionStore) AcceptTransaction(id, from, actorId string) error {
+
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+
+	m.updated = "diterima seller"
+
+	return nil
+}
+
+// MockStore untuk checkout, product diatur per id dan transaksi yang dibuat dicatat
+type orderStore struct {
+	datastore.MockStore
+	products map[string]entities.Product
+	stockErr error
+	created  []string
+	order    entities.Transaction
+	total    float64
+}
+
+func (m *orderStore) GetProductById(id string) (*entities.Product, error) {
+
+	product, ok := m.products[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	product.ID = id
+
+	return &product, nil
+}
+
+func (m *orderStore) ReserveStock(r *entities.StockReservation) error {
+
+	return m.stockErr
+}
+
+func (m *orderStore) CreateTransaction(id, buyerId, sellerId, productId string, total float64, t *entities.Transaction) error {
+
+	m.created = append(m.created, productId)
+	m.order = *t
+	m.total = total
+
+	return nil
+}
+
+// request dengan JWT user, payload di-marshal ke json
+func newAuthRequest(t *testing.T, method, url, userId string, payload interface{}) *http.Request {
+
+	t.Helper()
+	t.Setenv("JWTSECRET", testJWTSecret)
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := auth.CreateJWT(userId, 0, testJWTSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Add("authorization", token)
+
+	return req
+}
+
+func TestUpdateStatusTransaction(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		userId       string
+		from         string
+		to           string
+		expectedCode int
+	}{
+		{"Seller should accept waiting transaction", testSellerId, "menunggu", "diterima seller", http.StatusOK},
+		{"Seller should reject waiting transaction", testSellerId, "menunggu", "ditolak", http.StatusOK},
+		{"Seller should ship accepted transaction", testSellerId, "diterima seller", "dalam pengiriman", http.StatusOK},
+		{"Buyer should receive shipped transaction", testBuyerId, "dalam pengiriman", "diterima", http.StatusOK},
+
+		{"Buyer should not receive waiting transaction", testBuyerId, "menunggu", "diterima", http.StatusBadRequest},
+		{"Buyer should not receive rejected transaction", testBuyerId, "ditolak", "diterima", http.StatusBadRequest},
+		{"Buyer should not receive unshipped transaction", testBuyerId, "diterima seller", "diterima", http.StatusBadRequest},
+		{"Buyer should not accept as seller", testBuyerId, "menunggu", "diterima seller", http.StatusForbidden},
+		{"Seller should not ship waiting transaction", testSellerId, "menunggu", "dalam pengiriman", http.StatusBadRequest},
+		{"Seller should not reject accepted transaction", testSellerId, "diterima seller", "ditolak", http.StatusBadRequest},
+		{"Seller should not accept rejected transaction", testSellerId, "ditolak", "diterima seller", http.StatusBadRequest},
+		{"Seller should not ship received transaction", testSellerId, "diterima", "dalam pengiriman", http.StatusBadRequest},
+		{"Seller should not mark transaction as received", testSellerId, "dalam pengiriman", "diterima", http.StatusForbidden},
+		{"Other user should not update transaction", "2b0e5b8e-3f43-4a8e-9a57-0d6f1c2e3a4b", "menunggu", "ditolak", http.StatusForbidden},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := transactionStore{
+				tx: datastore.TransactionReturn{
+					Transaction: entities.TransactionMinimal{ID: "b78cd7e2-765e-4344-aa83-9b61aaa3dec4", Status: c.from},
+					Seller:      entities.UserMinimal{ID: testSellerId},
+					Buyer:       entities.UserMinimal{ID: testBuyerId},
+				},
+			}
+			transactionService := NewTransactionService(&inMemoryDb)
+
+			req := newAuthRequest(t, http.MethodPatch, "/transaction/b78cd7e2-765e-4344-aa83-9b61aaa3dec4", c.userId, map[string]string{"status": c.to})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/transaction/{id}", helper.CreateHandlerFunc(transactionService.UpdateStatusTransaction)).Methods(http.MethodPatch)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Errorf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if c.expectedCode != http.StatusOK && inMemoryDb.updated != "" {
+				t.Errorf("Expected status to stay %s, but got updated to %s", c.from, inMemoryDb.updated)
+			}
+		})
+	}
+}
+
+func TestAcceptTransactionConflict(t *testing.T) {
+
+	cases := []struct {
+		name         string
+		updateErr    error
+		expectedCode int
+	}{
+		{"Should return conflict when transaction status changed", datastore.ErrTransactionStatusChanged, http.StatusConflict},
+		{"Should return conflict when reservation already released", datastore.ErrReservationReleased, http.StatusConflict},
+		{"Should return internal server error when store failed", fmt.Errorf("db error"), http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := transactionStore{
+				tx: datastore.TransactionReturn{
+					Transaction: entities.TransactionMinimal{ID: "b78cd7e2-765e-4344-aa83-9b61aaa3dec4", Status: "menunggu"},
+					Seller:      entities.UserMinimal{ID: testSellerId},
+					Buyer:       entities.UserMinimal{ID: testBuyerId},
+				},
+				updateErr: c.updateErr,
+			}
+			transactionService := NewTransactionService(&inMemoryDb)
+
+			req := newAuthRequest(t, http.MethodPatch, "/transaction/b78cd7e2-765e-4344-aa83-9b61aaa3dec4", testSellerId, map[string]string{"status": "diterima seller"})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/transaction/{id}", helper.CreateHandlerFunc(transactionService.UpdateStatusTransaction)).Methods(http.MethodPatch)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Errorf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if inMemoryDb.updated != "" {
+				t.Errorf("Expected status to stay menunggu, but got updated to %s", inMemoryDb.updated)
+			}
+		})
+	}
+}
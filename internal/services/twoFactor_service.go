@@ -0,0 +1,104 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type TwoFactorService struct {
+	Store datastore.Store
+}
+
+func NewTwoFactorService(s datastore.Store) *TwoFactorService {
+
+	return &TwoFactorService{
+		Store: s,
+	}
+}
+
+func (s *TwoFactorService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/user/login/2fa", helper.CreateHandlerFunc(s.handleLoginTwoFactor)).Methods(http.MethodPost)
+	r.HandleFunc("/user/2fa/status", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleGetTwoFactorStatus))).Methods(http.MethodGet)
+	r.HandleFunc("/user/2fa/setup", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleSetupTwoFactor))).Methods(http.MethodPost)
+	r.HandleFunc("/user/2fa/enable", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleEnableTwoFactor))).Methods(http.MethodPost)
+	r.HandleFunc("/user/2fa/disable", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDisableTwoFactor))).Methods(http.MethodPost)
+	r.HandleFunc("/user/2fa/recovery-codes", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleRegenerateRecoveryCodes))).Methods(http.MethodPost)
+}
+
+func (s *TwoFactorService) handleLoginTwoFactor(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.LoginTwoFactor(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *TwoFactorService) handleGetTwoFactorStatus(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.GetTwoFactorStatus(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *TwoFactorService) handleSetupTwoFactor(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.SetupTwoFactor(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *TwoFactorService) handleEnableTwoFactor(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.EnableTwoFactor(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *TwoFactorService) handleDisableTwoFactor(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DisableTwoFactor(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *TwoFactorService) handleRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.RegenerateRecoveryCodes(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
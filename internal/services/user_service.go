@@ -18,6 +18,9 @@ import (
 
        handleUserRegister()
        handleUserLogin()
+       handleUserMe()
+       handleUserGetById()
+       handleUserGetByUsername()
 */
 type UserService struct {
 	Store datastore.Store
@@ -34,11 +37,54 @@ func NewUserService(s datastore.Store) *UserService {
 func (s *UserService) RegisterRoutes(r *mux.Router) {
 	r.HandleFunc("/user/register", helper.CreateHandlerFunc(s.handleUserRegister)).Methods(http.MethodPost)
 	r.HandleFunc("/user/login", helper.CreateHandlerFunc(s.handleUserLogin)).Methods(http.MethodPost)
+	r.HandleFunc("/user/restore", helper.CreateHandlerFunc(s.handleUserRestore)).Methods(http.MethodPost)
+	r.HandleFunc("/user/me", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUserMe))).Methods(http.MethodGet)
+	r.HandleFunc("/user/username/{username}", helper.CreateHandlerFunc(s.handleUserGetByUsername)).Methods(http.MethodGet)
 
+	r.HandleFunc("/user/{id}", helper.CreateHandlerFunc(s.handleUserGetById)).Methods(http.MethodGet)
 	r.HandleFunc("/user/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUserUpdate))).Methods(http.MethodPatch)
 	r.HandleFunc("/user/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUserDelete))).Methods(http.MethodDelete)
 }
 
+func (s *UserService) handleUserMe(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	err := usecases.GetMe(s.Store, w, r)
+	if err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *UserService) handleUserGetById(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	err := usecases.GetUserById(s.Store, w, r)
+	if err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *UserService) handleUserGetByUsername(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	err := usecases.GetUserByUsername(s.Store, w, r)
+	if err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
 func (s *UserService) handleUserUpdate(w http.ResponseWriter, r *http.Request) types.AppError {
 
 	err := usecases.UpdateUser(s.Store, w, r)
@@ -52,6 +98,19 @@ func (s *UserService) handleUserUpdate(w http.ResponseWriter, r *http.Request) t
 	}
 }
 
+func (s *UserService) handleUserRestore(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	err := usecases.RestoreUser(s.Store, w, r)
+	if err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
 func (s *UserService) handleUserDelete(w http.ResponseWriter, r *http.Request) types.AppError {
 
 	err := usecases.DeleteUser(s.Store, w, r)
@@ -75,7 +75,7 @@ func TestCreateUser(t *testing.T) {
 
 	t.Run("Should return an error if password is empty", func(t *testing.T) {
 		payload := &entities.User{
-			HashPassword: "",
+			Password: "",
 		}
 
 		b, err := json.Marshal(payload)
@@ -131,9 +131,9 @@ func TestCreateUser(t *testing.T) {
 
 	t.Run("Should create user", func(t *testing.T) {
 		payload := &entities.User{
-			Username:     "username",
-			Name:         "nama user",
-			HashPassword: "12345678",
+			Username: "username",
+			Name:     "nama user",
+			Password: "12345678",
 		}
 
 		b, err := json.Marshal(payload)
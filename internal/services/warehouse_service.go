@@ -0,0 +1,104 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type WarehouseService struct {
+	Store datastore.Store
+}
+
+func NewWarehouseService(s datastore.Store) *WarehouseService {
+
+	return &WarehouseService{
+		Store: s,
+	}
+}
+
+func (s *WarehouseService) RegisterRoutes(r *mux.Router) {
+	r.HandleFunc("/warehouse", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateWarehouse))).Methods(http.MethodPost)
+	r.HandleFunc("/warehouse", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleListWarehouse))).Methods(http.MethodGet)
+	r.HandleFunc("/warehouse/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateWarehouse))).Methods(http.MethodPatch)
+	r.HandleFunc("/warehouse/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteWarehouse))).Methods(http.MethodDelete)
+	r.HandleFunc("/product/{id}/warehouse-stock", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleListWarehouseStock))).Methods(http.MethodGet)
+	r.HandleFunc("/product/{id}/stock/transfer", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleTransferStock))).Methods(http.MethodPost)
+}
+
+func (s *WarehouseService) handleCreateWarehouse(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateWarehouse(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *WarehouseService) handleListWarehouse(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListWarehouse(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WarehouseService) handleUpdateWarehouse(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UpdateWarehouse(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WarehouseService) handleDeleteWarehouse(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DeleteWarehouse(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WarehouseService) handleListWarehouseStock(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListWarehouseStock(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WarehouseService) handleTransferStock(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.TransferStock(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
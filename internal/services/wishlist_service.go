@@ -0,0 +1,170 @@
+package services
+
+import (
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/usecases"
+	"github.com/gorilla/mux"
+)
+
+type WishlistService struct {
+	Store datastore.Store
+}
+
+func NewWishlistService(s datastore.Store) *WishlistService {
+
+	return &WishlistService{
+		Store: s,
+	}
+}
+
+func (s *WishlistService) RegisterRoutes(r *mux.Router) {
+	// route shared harus didaftarkan sebelum /wishlist/{id}
+	r.HandleFunc("/wishlist/shared/{token}", helper.CreateHandlerFunc(s.handleGetSharedWishlist)).Methods(http.MethodGet)
+	r.HandleFunc("/wishlist", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleListWishlist))).Methods(http.MethodGet)
+	r.HandleFunc("/wishlist", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCreateWishlist))).Methods(http.MethodPost)
+	r.HandleFunc("/wishlist/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleGetWishlist))).Methods(http.MethodGet)
+	r.HandleFunc("/wishlist/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUpdateWishlist))).Methods(http.MethodPatch)
+	r.HandleFunc("/wishlist/{id}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleDeleteWishlist))).Methods(http.MethodDelete)
+	r.HandleFunc("/wishlist/{id}/item", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleAddWishlistItem))).Methods(http.MethodPost)
+	r.HandleFunc("/wishlist/{id}/item/{itemId}", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleRemoveWishlistItem))).Methods(http.MethodDelete)
+	r.HandleFunc("/wishlist/{id}/share", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleShareWishlist))).Methods(http.MethodPost)
+	r.HandleFunc("/wishlist/{id}/share", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleUnshareWishlist))).Methods(http.MethodDelete)
+	r.HandleFunc("/wishlist/{id}/checkout", helper.CreateHandlerFunc(auth.JWTMiddleware(s.handleCheckoutWishlist))).Methods(http.MethodPost)
+}
+
+func (s *WishlistService) handleListWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ListWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WishlistService) handleCreateWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CreateWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *WishlistService) handleGetWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.GetWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WishlistService) handleUpdateWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UpdateWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WishlistService) handleDeleteWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.DeleteWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WishlistService) handleAddWishlistItem(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.AddWishlistItem(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func (s *WishlistService) handleRemoveWishlistItem(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.RemoveWishlistItem(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WishlistService) handleShareWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.ShareWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WishlistService) handleUnshareWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.UnshareWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WishlistService) handleGetSharedWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.GetSharedWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func (s *WishlistService) handleCheckoutWishlist(w http.ResponseWriter, r *http.Request) types.AppError {
+
+	if err := usecases.CheckoutWishlist(s.Store, w, r); err.Error != nil {
+		return err
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
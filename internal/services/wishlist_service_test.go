@@ -0,0 +1,163 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/gorilla/mux"
+)
+
+// MockStore dengan wishlist milik buyer, item yang di-checkout dicatat
+type wishlistStore struct {
+	orderStore
+	items   []entities.WishlistItem
+	deleted []string
+}
+
+func (m *wishlistStore) GetWishlist(id string) (*entities.Wishlist, error) {
+
+	return &entities.Wishlist{ID: id, UserId: testBuyerId, Name: "Favorit"}, nil
+}
+
+func (m *wishlistStore) ListWishlistItems(wishlistId string) (*[]entities.WishlistItem, error) {
+
+	items := m.items
+
+	return &items, nil
+}
+
+func (m *wishlistStore) DeleteWishlistItem(wishlistId, itemId string) error {
+
+	m.deleted = append(m.deleted, itemId)
+
+	return nil
+}
+
+func TestCheckoutWishlist(t *testing.T) {
+
+	const (
+		kaosId   = "1f0c2d3e-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+		celanaId = "2a1b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"
+		ownId    = "3b2c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"
+		hiddenId = "4c3d5e6f-7a8b-4c9d-8e1f-2a3b4c5d6e7f"
+	)
+
+	products := map[string]entities.Product{
+		kaosId:   {Status: "published", IsPurchaseable: true, SellerId: testSellerId, Stock: 10},
+		celanaId: {Status: "published", IsPurchaseable: true, SellerId: testSellerId, Stock: 1},
+		ownId:    {Status: "published", IsPurchaseable: true, SellerId: testBuyerId, Stock: 10},
+		hiddenId: {Status: "published", IsPurchaseable: false, SellerId: testSellerId, Stock: 10},
+	}
+
+	cases := []struct {
+		name            string
+		userId          string
+		items           []entities.WishlistItem
+		itemIds         []string
+		expectedCode    int
+		expectedOrdered []string
+		expectedFailed  int
+	}{
+		{
+			"Should checkout all items",
+			testBuyerId,
+			[]entities.WishlistItem{{ID: "item-1", ProductId: kaosId, Quantity: 2}, {ID: "item-2", ProductId: celanaId, Quantity: 1}},
+			nil,
+			http.StatusCreated,
+			[]string{"item-1", "item-2"},
+			0,
+		},
+		{
+			"Should only checkout selected items",
+			testBuyerId,
+			[]entities.WishlistItem{{ID: "item-1", ProductId: kaosId, Quantity: 2}, {ID: "item-2", ProductId: celanaId, Quantity: 1}},
+			[]string{"item-2"},
+			http.StatusCreated,
+			[]string{"item-2"},
+			0,
+		},
+		{
+			"Should keep failed items in wishlist",
+			testBuyerId,
+			[]entities.WishlistItem{
+				{ID: "item-1", ProductId: kaosId, Quantity: 1},
+				{ID: "item-2", ProductId: celanaId, Quantity: 5},
+				{ID: "item-3", ProductId: hiddenId, Quantity: 1},
+			},
+			nil,
+			http.StatusCreated,
+			[]string{"item-1"},
+			2,
+		},
+		{
+			"Should fail when no item can be checked out",
+			testBuyerId,
+			[]entities.WishlistItem{{ID: "item-1", ProductId: ownId, Quantity: 1}},
+			[]string{"item-1", "item-404"},
+			http.StatusBadRequest,
+			nil,
+			2,
+		},
+		{
+			"Should not checkout wishlist of other user",
+			testSellerId,
+			[]entities.WishlistItem{{ID: "item-1", ProductId: kaosId, Quantity: 1}},
+			nil,
+			http.StatusForbidden,
+			nil,
+			0,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			inMemoryDb := wishlistStore{orderStore: orderStore{products: products}, items: c.items}
+			wishlistService := NewWishlistService(&inMemoryDb)
+
+			req := newAuthRequest(t, http.MethodPost, "/wishlist/b78cd7e2-765e-4344-aa83-9b61aaa3dec4/checkout", c.userId, map[string]interface{}{"itemIds": c.itemIds})
+
+			rr := httptest.NewRecorder()
+			router := mux.NewRouter()
+			router.HandleFunc("/wishlist/{id}/checkout", helper.CreateHandlerFunc(wishlistService.handleCheckoutWishlist)).Methods(http.MethodPost)
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != c.expectedCode {
+				t.Fatalf("Invalid status code, expected: %d, but got: %d", c.expectedCode, rr.Code)
+			}
+
+			if len(inMemoryDb.created) != len(c.expectedOrdered) || len(inMemoryDb.deleted) != len(c.expectedOrdered) {
+				t.Fatalf("Expected %d items checked out, but got created=%v deleted=%v", len(c.expectedOrdered), inMemoryDb.created, inMemoryDb.deleted)
+			}
+
+			for i, itemId := range c.expectedOrdered {
+				if inMemoryDb.deleted[i] != itemId {
+					t.Errorf("Expected %s removed from wishlist, but got=%s", itemId, inMemoryDb.deleted[i])
+				}
+			}
+
+			if c.expectedCode == http.StatusForbidden {
+				return
+			}
+
+			var resp struct {
+				Data struct {
+					Failed []struct {
+						ItemId string `json:"itemId"`
+					} `json:"failed"`
+				} `json:"data"`
+			}
+
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(resp.Data.Failed) != c.expectedFailed {
+				t.Errorf("Expected %d failed items, but got=%v", c.expectedFailed, resp.Data.Failed)
+			}
+		})
+	}
+}
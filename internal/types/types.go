@@ -5,27 +5,71 @@ type ServerResponse struct {
 	Data    interface{} `json:"data"`
 }
 
+// posisi halaman untuk keyset pagination, dikirim ke client sebagai token base64 (opaque)
+type Cursor struct {
+	Order    string   `json:"o"`
+	Values   []string `json:"v"` // value tiap sort key, key terakhir selalu id
+	Backward bool     `json:"b,omitempty"`
+}
+
+type PageInfo struct {
+	Next  string `json:"next"`
+	Prev  string `json:"prev"`
+	Total *int   `json:"total,omitempty"`
+}
+
+// satu kolom sort untuk list product, contoh order=price:asc
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// pagination berbasis offset untuk list kecil (review, pertanyaan product)
+type OffsetPageInfo struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
 type AppError struct {
 	Error  error
 	Status int
 }
 
 type ListQueryTransactionValid struct {
-	Seller bool
+	Seller    bool
+	Limit     int
+	Offset    int
+	Sort      string
+	Order     string
+	Search    string
+	Cursor    *Cursor
+	WithTotal bool
+}
+
+type ListQueryTransaction struct {
+	Seller    string
+	Limit     string
+	Offset    string
+	Sort      string
+	Order     string
+	Search    string
+	Cursor    string
+	WithTotal string
+}
+
+type ListQueryReviewValid struct {
 	Limit  int
 	Offset int
-	Sort   string
-	Order  string
-	Search string
+	Order  string // "recent"|"helpful"|"rating"
+	Rating int    // 0 = semua rating
 }
 
-type ListQueryTransaction struct {
-	Seller string
+type ListQueryReview struct {
 	Limit  string
 	Offset string
-	Sort   string
 	Order  string
-	Search string
+	Rating string
 }
 
 type ListQuery struct {
@@ -33,13 +77,25 @@ type ListQuery struct {
 	Limit          string
 	Offset         string
 	Tags           []string
+	TagMatch       string
 	Condition      string
 	ShowEmptyStock string
+	Stock          string
+	Seller         string
+	Storefront     string
+	Purchaseable   string
 	MaxPrice       string
 	MinPrice       string
+	MinRating      string
 	Sort           string
 	Order          string
 	Search         string
+	Attributes     []string
+	Category       string
+	Facets         string
+	Cursor         string
+	WithTotal      string
+	Status         string
 }
 
 type ListQueryValid struct {
@@ -47,11 +103,21 @@ type ListQueryValid struct {
 	Limit          int
 	Offset         int
 	Tags           []string
-	Condition      string
-	ShowEmptyStock string
+	TagMatch       string // "any"|"all"
+	Condition      string // "new"|"second"|"any"
+	Stock          string // "include"|"exclude"|"only"
+	SellerId       string
+	Storefront     string // slug storefront
+	IsPurchaseable *bool
 	MaxPrice       float64
 	MinPrice       float64
-	Sort           string
-	Order          string
+	MinRating      float64
+	Sorts          []SortField
 	Search         string
+	Attributes     map[string]string
+	Category       string
+	Facets         bool
+	Cursor         *Cursor
+	WithTotal      bool
+	Status         string // "draft"|"scheduled"|"published"|"archived"|"any", hanya berlaku untuk useronly
 }
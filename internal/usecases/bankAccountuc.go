@@ -1,12 +1,14 @@
 package usecases
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 
+	"github.com/GetterSethya/golangApiMarketplace/config"
 	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
 	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
 	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
@@ -22,6 +24,7 @@ type BankAccountUseCase interface {
 	ListBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 	UpdateBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 	DeleteBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	RestoreBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 }
 
 func UpdateBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
@@ -30,6 +33,10 @@ func UpdateBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request
 	bankAccId := vars["id"]
 	sellerId := auth.GetUserIdFromJWT(r)
 
+	if err := checkTwoFactorSeller(s, sellerId); err.Error != nil {
+		return err
+	}
+
 	bankAcc, err := s.GetBankAccount(bankAccId)
 	if err != nil {
 
@@ -168,11 +175,91 @@ func DeleteBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request
 	}
 }
 
+// POST /v1/bank/account/{id}/restore, hanya selama grace period
+func RestoreBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	bankAccId := vars["id"]
+	sellerId := auth.GetUserIdFromJWT(r)
+
+	if err := checkTwoFactorSeller(s, sellerId); err.Error != nil {
+		return err
+	}
+
+	if !helper.ValidateUUID(bankAccId) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Bank account didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	err := s.RestoreBankAccount(bankAccId, sellerId, config.LoadConfig().App.DeleteGracePeriod)
+	switch {
+	case err == sql.ErrNoRows:
+
+		return types.AppError{
+			Error:  fmt.Errorf("Bank account didnot exist or can no longer be restored"),
+			Status: http.StatusNotFound,
+		}
+	case err != nil:
+
+		log.Println("error when restoring bank account", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when restoring bank account, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	bankAcc, err := s.GetBankAccount(bankAccId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching bank account"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Bank account restored successfully",
+		Data: map[string]interface{}{
+			"bankAccount": bankAcc,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
 func ListBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
 
 	vars := mux.Vars(r)
 	userIdUrlPath := vars["id"]
 
+	// rekening seller yang belum mengaktifkan 2fa (termasuk rekening lama sebelum 2fa diwajibkan)
+	// tidak ditampilkan ke pembeli sampai seller enroll 2fa
+	if err := checkTwoFactorSeller(s, userIdUrlPath); err.Error != nil {
+
+		resp := types.ServerResponse{
+			Message: "Ok",
+			Data: map[string]interface{}{
+				"bankAccounts": []entities.BankAccount{},
+			},
+		}
+
+		helper.WriteJson(w, http.StatusOK, resp)
+
+		return types.AppError{
+			Error:  nil,
+			Status: http.StatusOK,
+		}
+	}
+
 	listBankAcc, err := s.ListBankAccount(userIdUrlPath)
 
 	if err != nil {
@@ -204,6 +291,10 @@ func CreateBankAccount(s datastore.Store, w http.ResponseWriter, r *http.Request
 
 	sellerId := auth.GetUserIdFromJWT(r)
 
+	if err := checkTwoFactorSeller(s, sellerId); err.Error != nil {
+		return err
+	}
+
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 
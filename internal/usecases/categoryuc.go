@@ -0,0 +1,435 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+type CategoryUseCase interface {
+	ListCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	GetCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	CreateCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UpdateCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	DeleteCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// nampilin seluruh category dalam bentuk tree, GET /v1/category
+func ListCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	categories, err := s.ListCategories()
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching categories"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"categories": buildCategoryTree(*categories, ""),
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// detail category beserta sub category dan breadcrumb, GET /v1/category/{slug}
+func GetCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	slugUrlPath := strings.ToLower(vars["slug"])
+
+	if !validator.ValidateSlug(slugUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Category didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	category, err := s.GetCategoryBySlug(slugUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Category didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	categories, err := s.ListCategories()
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching categories"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	path, err := s.GetCategoryPath(category.ID)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching categories"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	category.Children = buildCategoryTree(*categories, category.ID)
+	category.Path = *path
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"category": category,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func CreateCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	category, appErr := readCategoryPayload(s, "", r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	id := uuid.NewString()
+
+	if err := s.CreateCategory(id, category); err != nil {
+
+		log.Println("error when creating category", err)
+
+		return categoryWriteError(err)
+	}
+
+	newCategory, err := s.GetCategoryById(id)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating category, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Category created successfully",
+		Data: map[string]interface{}{
+			"category": newCategory,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func UpdateCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	categoryIdUrlPath := vars["id"]
+
+	if !helper.ValidateUUID(categoryIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Category didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if _, err := s.GetCategoryById(categoryIdUrlPath); err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Category didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	category, appErr := readCategoryPayload(s, categoryIdUrlPath, r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if err := s.UpdateCategory(categoryIdUrlPath, category); err != nil {
+
+		log.Println("error when updating category", err)
+
+		return categoryWriteError(err)
+	}
+
+	respCategory, err := s.GetCategoryById(categoryIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating category, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Category updated successfully",
+		Data: map[string]interface{}{
+			"category": respCategory,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func DeleteCategory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	categoryIdUrlPath := vars["id"]
+
+	if !helper.ValidateUUID(categoryIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Category didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	children, products, err := s.CountCategoryUsage(categoryIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting category, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if children > 0 || products > 0 {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Category still has sub categories or products"),
+			Status: http.StatusConflict,
+		}
+	}
+
+	if err := s.DeleteCategory(categoryIdUrlPath); err != nil {
+
+		log.Println("error when deleting category", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting category, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Category deleted successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// product hanya boleh ditempel ke leaf category (category tanpa sub category)
+func checkLeafCategory(s datastore.Store, categoryId string) types.AppError {
+
+	if categoryId == "" {
+
+		return types.AppError{
+			Error:  nil,
+			Status: http.StatusOK,
+		}
+	}
+
+	if _, err := s.GetCategoryById(categoryId); err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid product categoryId"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	children, _, err := s.CountCategoryUsage(categoryId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if children > 0 {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product category must be a leaf category"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// baca payload category, parent harus ada, bukan category itu sendiri / turunannya, dan tidak sedang dipakai product
+func readCategoryPayload(s datastore.Store, categoryId string, r *http.Request) (*entities.Category, types.AppError) {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in category usecase")
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var category *entities.Category
+
+	err = json.Unmarshal(body, &category)
+	if err != nil || category == nil {
+
+		log.Println("error when Unmarshal body in category usecase", err)
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	category.Slug = strings.ToLower(category.Slug)
+
+	if err := validator.ValidateCategoryPayload(category); err != nil {
+
+		return nil, types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if category.ParentId == "" {
+
+		return category, types.AppError{
+			Error:  nil,
+			Status: http.StatusOK,
+		}
+	}
+
+	if _, err := s.GetCategoryById(category.ParentId); err != nil {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid category parentId"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	parentPath, err := s.GetCategoryPath(category.ParentId)
+	if err != nil {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Something went wrong"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	for _, ancestor := range *parentPath {
+		if categoryId != "" && ancestor.ID == categoryId {
+
+			return nil, types.AppError{
+				Error:  fmt.Errorf("Invalid category parentId, cannot move category under itself"),
+				Status: http.StatusBadRequest,
+			}
+		}
+	}
+
+	_, products, err := s.CountCategoryUsage(category.ParentId)
+	if err != nil {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Something went wrong"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if products > 0 {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid category parentId, parent category already has products"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	return category, types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func categoryWriteError(err error) types.AppError {
+
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Category slug already used"),
+			Status: http.StatusConflict,
+		}
+	}
+
+	return types.AppError{
+		Error:  fmt.Errorf("Failed when saving category, please try again."),
+		Status: http.StatusInternalServerError,
+	}
+}
+
+// susun list flat jadi tree mulai dari parentId, urutan mengikuti list (position, name)
+func buildCategoryTree(categories []entities.Category, parentId string) []entities.Category {
+
+	tree := []entities.Category{}
+
+	for _, category := range categories {
+		if category.ParentId != parentId {
+			continue
+		}
+
+		category.Children = buildCategoryTree(categories, category.ID)
+		tree = append(tree, category)
+	}
+
+	return tree
+}
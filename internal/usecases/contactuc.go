@@ -0,0 +1,428 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/notifier"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/lib/pq"
+)
+
+type ContactUseCase interface {
+	UpdateContact(s datastore.Store, n notifier.Notifier, w http.ResponseWriter, r *http.Request) types.AppError
+	SendContactCode(s datastore.Store, n notifier.Notifier, w http.ResponseWriter, r *http.Request) types.AppError
+	VerifyContact(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// ganti email/phone, kontak yang berubah harus diverifikasi ulang dan kode langsung dikirim.
+// PUT /v1/user/contact
+func UpdateContact(s datastore.Store, n notifier.Notifier, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type contactStruct struct {
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+	}
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in UpdateContact usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload contactStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in update contact usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	email := strings.ToLower(strings.TrimSpace(payload.Email))
+	phone := strings.NewReplacer(" ", "", "-", "").Replace(payload.Phone)
+
+	if err := validator.ValidateContactPayload(email, phone); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	before, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if err := s.UpdateUserContact(userId, email, phone); err != nil {
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Email/phone already used by another account"),
+				Status: http.StatusConflict,
+			}
+		}
+
+		log.Println("error when updating contact", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating contact, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong. Please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	// kode verifikasi untuk kontak yang baru diisi/diganti
+	sent := []string{}
+	for _, channel := range []string{"email", "phone"} {
+		target, previous := contactTarget(user, channel), contactTarget(before, channel)
+		if target == "" || target == previous {
+			continue
+		}
+
+		if err := sendContactCode(s, n, user, channel); err != nil {
+			log.Println("error when sending contact verification code", err)
+			continue
+		}
+
+		sent = append(sent, channel)
+	}
+
+	resp := types.ServerResponse{
+		Message: "Contact updated successfully",
+		Data: map[string]interface{}{
+			"contact":          toUserContact(user),
+			"verificationSent": sent,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// kirim ulang kode verifikasi, kode sebelumnya tidak berlaku lagi. POST /v1/user/contact/verify/send
+func SendContactCode(s datastore.Store, n notifier.Notifier, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type sendCodeStruct struct {
+		Channel string `json:"channel"`
+	}
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in SendContactCode usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload sendCodeStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil || !validator.ValidateContactChannel(payload.Channel) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid channel"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if contactTarget(user, payload.Channel) == "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("No %s to verify", payload.Channel),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if contactVerified(user, payload.Channel) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("%s already verified", payload.Channel),
+			Status: http.StatusConflict,
+		}
+	}
+
+	latest, err := s.GetLatestContactVerification(userId, payload.Channel)
+	if err != nil {
+
+		log.Println("error when getting latest contact verification", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong. Please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if latest != nil && time.Since(latest.CreatedAt) < validator.CONTACTCODERESEND {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Please wait before requesting another code"),
+			Status: http.StatusTooManyRequests,
+		}
+	}
+
+	if err := sendContactCode(s, n, user, payload.Channel); err != nil {
+
+		log.Println("error when sending contact verification code", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when sending verification code, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Verification code sent",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusAccepted, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusAccepted,
+	}
+}
+
+// POST /v1/user/contact/verify
+func VerifyContact(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type verifyStruct struct {
+		Channel string `json:"channel"`
+		Code    string `json:"code"`
+	}
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in VerifyContact usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload verifyStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil || !validator.ValidateContactChannel(payload.Channel) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid channel"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	code := strings.TrimSpace(payload.Code)
+	if len(code) != validator.CONTACTCODELENGTH {
+
+		return types.AppError{
+			Error:  datastore.ErrContactVerificationInvalid,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	err = s.VerifyContact(userId, payload.Channel, helper.HashToken(code))
+	if err == datastore.ErrContactVerificationInvalid {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when verifying contact", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when verifying contact, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong. Please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Contact verified successfully",
+		Data: map[string]interface{}{
+			"contact": toUserContact(user),
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// jualan (buat product, import, buka storefront) hanya untuk user yang sudah verifikasi email atau phone
+func checkVerifiedSeller(s datastore.Store, userId string) types.AppError {
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if !user.EmailVerifiedAt.Valid && !user.PhoneVerifiedAt.Valid {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Verify your email or phone before selling"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	return types.AppError{}
+}
+
+func sendContactCode(s datastore.Store, n notifier.Notifier, user *entities.User, channel string) error {
+
+	code := helper.GenerateNumericCode(validator.CONTACTCODELENGTH)
+	target := contactTarget(user, channel)
+
+	err := s.CreateContactVerification(&entities.ContactVerification{
+		UserId:    user.ID,
+		Channel:   channel,
+		Target:    target,
+		CodeHash:  helper.HashToken(code),
+		ExpiresAt: time.Now().Add(validator.CONTACTCODETTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return n.Send(notifier.Message{
+		UserId:  user.ID,
+		Channel: notifierChannel(channel),
+		To:      target,
+		Subject: "Verification code",
+		Body:    fmt.Sprintf("Your verification code: %s (valid for %d minutes)", code, int(validator.CONTACTCODETTL.Minutes())),
+	})
+}
+
+// tujuan pesan untuk user, kontak yang sudah verified dulu (email lalu phone).
+// user tanpa kontak verified hanya bisa menerima lewat sink development (log/file)
+func contactRecipient(user *entities.User) (string, string) {
+
+	switch {
+	case user.EmailVerifiedAt.Valid:
+		return notifierChannel("email"), user.Email
+	case user.PhoneVerifiedAt.Valid:
+		return notifierChannel("phone"), user.Phone
+	default:
+		return "", user.Username
+	}
+}
+
+func contactTarget(user *entities.User, channel string) string {
+
+	if channel == "phone" {
+		return user.Phone
+	}
+
+	return user.Email
+}
+
+func contactVerified(user *entities.User, channel string) bool {
+
+	if channel == "phone" {
+		return user.PhoneVerifiedAt.Valid
+	}
+
+	return user.EmailVerifiedAt.Valid
+}
+
+// channel kontak -> channel notifier
+func notifierChannel(channel string) string {
+
+	if channel == "phone" {
+		return "sms"
+	}
+
+	return "email"
+}
+
+func toUserContact(user *entities.User) entities.UserContact {
+
+	return entities.UserContact{
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt.Valid,
+		Phone:         user.Phone,
+		PhoneVerified: user.PhoneVerifiedAt.Valid,
+	}
+}
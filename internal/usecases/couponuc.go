@@ -0,0 +1,353 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+type CouponUseCase interface {
+	CreateCoupon(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ListCoupon(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	DeleteCoupon(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// coupon seller hanya berlaku untuk product seller itu sendiri,
+// coupon platform (platform: true) hanya bisa dibuat admin. POST /v1/coupon
+func CreateCoupon(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type couponStruct struct {
+		entities.Coupon
+		Platform bool `json:"platform"`
+	}
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in CreateCoupon usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload couponStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in create coupon usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	coupon := payload.Coupon
+	coupon.SellerId = userId
+
+	if payload.Platform {
+		if !auth.IsAdmin(userId) {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Forbidden"),
+				Status: http.StatusForbidden,
+			}
+		}
+
+		coupon.SellerId = ""
+	}
+
+	if err := validator.ValidateCouponPayload(&coupon, time.Now()); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if coupon.SellerId != "" {
+		for _, productId := range coupon.ProductIds {
+			sellerId, err := s.GetProductSeller(productId)
+			if err != nil || sellerId != coupon.SellerId {
+
+				return types.AppError{
+					Error:  fmt.Errorf("Invalid coupon productIds"),
+					Status: http.StatusBadRequest,
+				}
+			}
+		}
+	}
+
+	for _, categoryId := range coupon.CategoryIds {
+		if _, err := s.GetCategoryById(categoryId); err != nil {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Invalid coupon categoryIds"),
+				Status: http.StatusBadRequest,
+			}
+		}
+	}
+
+	id := uuid.NewString()
+
+	if err := s.CreateCoupon(id, &coupon); err != nil {
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Coupon code already used"),
+				Status: http.StatusConflict,
+			}
+		}
+
+		log.Println("error when creating coupon", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating coupon, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	newCoupon, err := s.GetCoupon(id)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching coupon"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Coupon created successfully",
+		Data: map[string]interface{}{
+			"coupon": newCoupon,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+// coupon milik user, ?platform=true untuk coupon platform (admin). GET /v1/coupon
+func ListCoupon(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+	sellerId := userId
+
+	if r.URL.Query().Get("platform") == "true" {
+		if !auth.IsAdmin(userId) {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Forbidden"),
+				Status: http.StatusForbidden,
+			}
+		}
+
+		sellerId = ""
+	}
+
+	coupons, err := s.ListCoupons(sellerId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching coupons"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"coupons": coupons,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// DELETE /v1/coupon/{id}
+func DeleteCoupon(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	couponIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !helper.ValidateUUID(couponIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Coupon didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	coupon, err := s.GetCoupon(couponIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Coupon didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if !(coupon.SellerId == userId || (coupon.SellerId == "" && auth.IsAdmin(userId))) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	if err := s.DeleteCoupon(coupon.ID); err != nil {
+
+		log.Println("error when deleting coupon", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting coupon, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Coupon deleted successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// cek coupon bisa dipakai untuk product dan subtotal ini, return coupon dan besar potongannya.
+// batas pemakaian dicek ulang saat claim
+func checkCoupon(s datastore.Store, code string, product *entities.Product, subtotal float64, now time.Time) (*entities.Coupon, float64, types.AppError) {
+
+	invalidCoupon := func(err error) (*entities.Coupon, float64, types.AppError) {
+		return nil, 0, types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	coupon, err := s.GetCouponByCode(validator.NormalizeCouponCode(code))
+	if err != nil {
+		return invalidCoupon(fmt.Errorf("Invalid coupon code"))
+	}
+
+	if now.Before(coupon.StartsAt) || !now.Before(coupon.ExpiresAt) {
+		return invalidCoupon(datastore.ErrCouponUnavailable)
+	}
+
+	if coupon.SellerId != "" && coupon.SellerId != product.SellerId {
+		return invalidCoupon(fmt.Errorf("Coupon is not valid for this product"))
+	}
+
+	if len(coupon.ProductIds) > 0 && !containsString(coupon.ProductIds, product.ID) {
+		return invalidCoupon(fmt.Errorf("Coupon is not valid for this product"))
+	}
+
+	if len(coupon.CategoryIds) > 0 {
+		eligible := false
+
+		// product di sub category ikut eligible
+		if product.CategoryId != "" {
+			path, err := s.GetCategoryPath(product.CategoryId)
+			if err != nil {
+				log.Println("error when getting category path for coupon", err)
+
+				return nil, 0, types.AppError{
+					Error:  fmt.Errorf("Failed when creating transaction, please try again."),
+					Status: http.StatusInternalServerError,
+				}
+			}
+
+			for _, category := range *path {
+				if containsString(coupon.CategoryIds, category.ID) {
+					eligible = true
+					break
+				}
+			}
+		}
+
+		if !eligible {
+			return invalidCoupon(fmt.Errorf("Coupon is not valid for this product"))
+		}
+	}
+
+	if subtotal < coupon.MinSpend {
+		return invalidCoupon(fmt.Errorf("Minimum spend for this coupon is %.2f", coupon.MinSpend))
+	}
+
+	return coupon, coupon.DiscountFor(subtotal), types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// ambil jatah pemakaian coupon untuk transaksi
+func claimCoupon(s datastore.Store, coupon *entities.Coupon, buyerId, transactionId string, amount float64) types.AppError {
+
+	err := s.ClaimCoupon(coupon.ID, buyerId, transactionId, amount)
+
+	switch {
+	case err == datastore.ErrCouponUnavailable, err == datastore.ErrCouponUsageLimit, err == datastore.ErrCouponUserLimit:
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	case err != nil:
+		log.Println("error when claiming coupon", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating transaction, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	default:
+		return types.AppError{
+			Error:  nil,
+			Status: http.StatusOK,
+		}
+	}
+}
+
+func containsString(values []string, value string) bool {
+
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,264 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type DiscountUseCase interface {
+	CreateDiscount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ListProductDiscount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	DeleteDiscount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// diskon baru untuk product, periode diskon dalam satu product tidak boleh bertumpuk.
+// POST /v1/product/{id}/discount
+func CreateDiscount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	product, err := s.GetProductById(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in CreateDiscount usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var discount entities.Discount
+
+	err = json.Unmarshal(body, &discount)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in create discount usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	discount.Name = strings.TrimSpace(discount.Name)
+	if err := validator.ValidateDiscountPayload(&discount, product.Price, time.Now()); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	overlapping, err := s.CountOverlappingDiscounts(product.ID, discount.StartsAt, discount.EndsAt)
+	if err != nil {
+
+		log.Println("error when checking overlapping discount", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating discount, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if overlapping > 0 {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product already has a discount in this period"),
+			Status: http.StatusConflict,
+		}
+	}
+
+	discount.ProductId = product.ID
+	discount.SellerId = userId
+	id := uuid.NewString()
+
+	if err := s.CreateDiscount(id, &discount); err != nil {
+
+		log.Println("error when creating discount", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating discount, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	newDiscount, err := s.GetDiscount(id)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching discount"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Discount created successfully",
+		Data: map[string]interface{}{
+			"discount": newDiscount,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+// semua diskon product (aktif, terjadwal dan yang sudah lewat), hanya untuk seller pemilik product.
+// GET /v1/product/{id}/discount
+func ListProductDiscount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	discounts, err := s.ListProductDiscounts(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching discounts"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"discounts": discounts,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// batalkan diskon, transaksi yang sudah memakai diskon tidak berubah.
+// DELETE /v1/product/{id}/discount/{discountId}
+func DeleteDiscount(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	discountIdUrlPath := vars["discountId"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	if !helper.ValidateUUID(discountIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Discount didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	discount, err := s.GetDiscount(discountIdUrlPath)
+	if err != nil || discount.ProductId != productIdUrlPath {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Discount didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if err := s.DeleteDiscount(discount.ID); err != nil {
+
+		log.Println("error when deleting discount", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting discount, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Discount deleted successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// isi originalPrice, effectivePrice dan diskon aktif tiap product (dan harga variant setelah diskon)
+func applyActiveDiscounts(s datastore.Store, products []entities.Product) error {
+
+	productIds := make([]string, 0, len(products))
+	for _, product := range products {
+		productIds = append(productIds, product.ID)
+	}
+
+	discounts, err := s.ListActiveDiscounts(productIds)
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		product := &products[i]
+		product.OriginalPrice = product.Price
+		product.EffectivePrice = product.Price
+		product.Discount = nil
+
+		discount, ok := discounts[product.ID]
+		if !ok {
+			continue
+		}
+
+		product.Discount = &discount
+		product.EffectivePrice = discount.Apply(product.Price)
+
+		for j := range product.Variants {
+			discountedPrice := discount.Apply(product.Variants[j].EffectivePrice(product.Price))
+			product.Variants[j].DiscountedPrice = &discountedPrice
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/gorilla/mux"
+)
+
+type NotificationUseCase interface {
+	ListNotification(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ReadNotification(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ReadAllNotification(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// notifikasi user, GET /v1/notification?unread=true&limit=&offset=
+func ListNotification(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	queryParams := r.URL.Query()
+	unread, limit, offset := validator.ValidateListNotificationQuery(queryParams.Get("unread"), queryParams.Get("limit"), queryParams.Get("offset"))
+
+	notifications, total, err := s.ListNotifications(userId, unread, limit, offset)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching notifications"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"notifications": notifications,
+			"pagination": types.OffsetPageInfo{
+				Limit:  limit,
+				Offset: offset,
+				Total:  total,
+			},
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// POST /v1/notification/{id}/read
+func ReadNotification(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	notificationIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !helper.ValidateUUID(notificationIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Notification didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	err := s.MarkNotificationRead(notificationIdUrlPath, userId)
+	if err == datastore.ErrNotificationNotFound {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Notification didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when marking notification as read", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating notification, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Notification marked as read",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// POST /v1/notification/read
+func ReadAllNotification(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := s.MarkAllNotificationsRead(userId); err != nil {
+
+		log.Println("error when marking all notifications as read", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating notification, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "All notifications marked as read",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
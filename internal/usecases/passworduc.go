@@ -0,0 +1,263 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/config"
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/notifier"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+)
+
+type PasswordUseCase interface {
+	ChangePassword(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ForgotPassword(s datastore.Store, n notifier.Notifier, w http.ResponseWriter, r *http.Request) types.AppError
+	ResetPassword(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// ganti password dengan password lama, semua token lain tidak berlaku lagi
+// dan token baru dikirim di response. POST /v1/user/password
+func ChangePassword(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type changePasswordStruct struct {
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in ChangePassword usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload changePasswordStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in change password usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if !validator.ValidatePassword(payload.NewPassword) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid newPassword"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, err := s.GetUserById(userId)
+	if err != nil || !helper.CompareHash(user.HashPassword, payload.CurrentPassword) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid currentPassword"),
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	tokenVersion, err := s.UpdateUserPassword(userId, payload.NewPassword)
+	if err != nil {
+
+		log.Println("error when updating password", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when changing password, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	accessToken, err := auth.CreateJWT(userId, tokenVersion, config.LoadConfig().App.JWTSecret)
+	if err != nil {
+
+		log.Println("Error when creating JWT")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Password changed successfully",
+		Data: map[string]interface{}{
+			"accessToken": accessToken,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// kirim token reset lewat notifier. response selalu sama walaupun username tidak ada,
+// supaya endpoint ini tidak bisa dipakai untuk cek username. POST /v1/user/password/forgot
+func ForgotPassword(s datastore.Store, n notifier.Notifier, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type forgotPasswordStruct struct {
+		Username string `json:"username"`
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in ForgotPassword usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload forgotPasswordStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil || strings.TrimSpace(payload.Username) == "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid username"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if user, err := s.GetUserByUsername(strings.TrimSpace(payload.Username)); err == nil {
+		ttl := config.LoadConfig().App.PasswordResetTTL
+		token := helper.GenerateRandomToken(32)
+
+		if err := s.CreatePasswordReset(user.ID, helper.HashToken(token), time.Now().Add(ttl)); err != nil {
+
+			log.Println("error when creating password reset", err)
+
+			return types.AppError{
+				Error:  fmt.Errorf("Something went wrong, please try again"),
+				Status: http.StatusInternalServerError,
+			}
+		}
+
+		channel, to := contactRecipient(user)
+		err := n.Send(notifier.Message{
+			UserId:  user.ID,
+			Channel: channel,
+			To:      to,
+			Subject: "Reset password",
+			Body:    fmt.Sprintf("Reset token: %s (valid for %d minutes)", token, int(ttl.Minutes())),
+		})
+		if err != nil {
+			log.Println("error when sending password reset", err)
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "If the account exists, a reset token has been sent",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusAccepted, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusAccepted,
+	}
+}
+
+// token reset hanya bisa dipakai sekali, semua session user tidak berlaku lagi
+// dan user harus login ulang. POST /v1/user/password/reset
+func ResetPassword(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type resetPasswordStruct struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in ResetPassword usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload resetPasswordStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in reset password usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if !validator.ValidatePassword(payload.Password) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid password"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	_, err = s.ResetPassword(helper.HashToken(strings.TrimSpace(payload.Token)), payload.Password)
+	if err == datastore.ErrPasswordResetInvalid {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when resetting password", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when resetting password, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Password reset successfully, please login again",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
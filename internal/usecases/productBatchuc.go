@@ -0,0 +1,195 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+)
+
+type ProductBatchUseCase interface {
+	BatchUpdateProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// ubah stock (absolut atau delta) dan harga banyak product sekaligus dalam satu transaksi db.
+// mode "atomic" (default) semua item gagal kalau ada satu yang gagal, "best_effort" hanya item yang gagal.
+// POST /v1/product/batch
+func BatchUpdateProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type batchStruct struct {
+		Mode  string                      `json:"mode"`
+		Items []entities.ProductBatchItem `json:"items"`
+	}
+
+	sellerId := auth.GetUserIdFromJWT(r)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in BatchUpdateProduct usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload batchStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in batch update product usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	mode, err := validator.ValidateProductBatchMode(payload.Mode)
+	if err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if len(payload.Items) < 1 || len(payload.Items) > validator.MAXPRODUCTBATCHITEMS {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Batch must contain 1 to %d items", validator.MAXPRODUCTBATCHITEMS),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	results := make([]entities.ProductBatchResult, len(payload.Items))
+	validItems := []entities.ProductBatchItem{}
+	validIndexes := []int{}
+	invalid := false
+
+	for i := range payload.Items {
+		item := &payload.Items[i]
+		item.Reason = strings.TrimSpace(item.Reason)
+
+		results[i] = entities.ProductBatchResult{
+			Index:     i,
+			ProductId: item.ProductId,
+			VariantId: item.VariantId,
+		}
+
+		if err := validator.ValidateProductBatchItem(item); err != nil {
+			invalid = true
+			results[i].Error = err.Error()
+			continue
+		}
+
+		validItems = append(validItems, *item)
+		validIndexes = append(validIndexes, i)
+	}
+
+	atomic := mode == "atomic"
+
+	// item tidak valid di mode atomic, tidak ada yang diubah
+	if atomic && invalid {
+		for i := range results {
+			if results[i].Error == "" {
+				results[i].Error = "Not applied, another item in the batch failed"
+			}
+		}
+
+		return writeProductBatchResponse(w, http.StatusBadRequest, datastore.ErrProductBatchFailed.Error(), mode, results)
+	}
+
+	// snapshot sebelum diubah untuk history product
+	before := map[string]*entities.Product{}
+	for _, item := range validItems {
+		if _, ok := before[item.ProductId]; ok {
+			continue
+		}
+
+		if product, err := s.GetProductById(item.ProductId); err == nil && product.SellerId == sellerId {
+			before[item.ProductId] = product
+		}
+	}
+
+	if len(validItems) > 0 {
+		batchResults, err := s.BatchUpdateProducts(sellerId, validItems, atomic)
+		if err != nil && err != datastore.ErrProductBatchFailed {
+
+			log.Println("error when applying batch update", err)
+
+			return types.AppError{
+				Error:  fmt.Errorf("Failed when updating products, please try again."),
+				Status: http.StatusInternalServerError,
+			}
+		}
+
+		for i, result := range batchResults {
+			result.Index = validIndexes[i]
+			results[validIndexes[i]] = result
+		}
+
+		if err == datastore.ErrProductBatchFailed {
+			return writeProductBatchResponse(w, http.StatusBadRequest, err.Error(), mode, results)
+		}
+	}
+
+	updated := map[string]bool{}
+	for _, result := range results {
+		if !result.Success || updated[result.ProductId] {
+			continue
+		}
+
+		updated[result.ProductId] = true
+
+		if previous, ok := before[result.ProductId]; ok {
+			if after, err := s.GetProductById(result.ProductId); err == nil {
+				recordProductHistory(s, result.ProductId, sellerId, previous, after)
+			}
+		}
+
+		notifyBackInStock(s, result.ProductId, result.PreviousStock)
+	}
+
+	return writeProductBatchResponse(w, http.StatusOK, "Batch update processed", mode, results)
+}
+
+func writeProductBatchResponse(w http.ResponseWriter, status int, message, mode string, results []entities.ProductBatchResult) types.AppError {
+
+	succeeded := 0
+	for _, result := range results {
+		if result.Success {
+			succeeded++
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: message,
+		Data: map[string]interface{}{
+			"mode":      mode,
+			"results":   results,
+			"succeeded": succeeded,
+			"failed":    len(results) - succeeded,
+		},
+	}
+
+	helper.WriteJson(w, status, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: status,
+	}
+}
@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/gorilla/mux"
+)
+
+type ProductHistoryUseCase interface {
+	ListProductHistory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ListProductPriceHistory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// semua versi product beserta diff nya, hanya untuk seller pemilik product
+// GET /v1/product/{id}/history?limit=&offset=
+func ListProductHistory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	queryParams := r.URL.Query()
+	limit, offset := validator.ValidateListProductHistoryQuery(queryParams.Get("limit"), queryParams.Get("offset"))
+
+	history, total, err := s.ListProductHistory(productIdUrlPath, limit, offset)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching product history"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"history": history,
+			"pagination": types.OffsetPageInfo{
+				Limit:  limit,
+				Offset: offset,
+				Total:  total,
+			},
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// riwayat harga product untuk buyer, GET /v1/product/{id}/price-history
+func ListProductPriceHistory(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+
+	if !helper.ValidateUUID(productIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	product, err := s.GetProductById(productIdUrlPath)
+	if err != nil || !canViewProduct(product, auth.GetUserIdFromJWT(r)) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	points, err := s.ListProductPriceHistory(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching price history"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	// product lama yang belum pernah diubah belum punya history, pakai harga sekarang
+	if len(*points) == 0 {
+		point := entities.PricePoint{Price: product.Price, VariantPrices: map[string]float64{}, ChangedAt: product.UpdatedAt}
+		if variants, err := s.ListProductVariants(productIdUrlPath); err == nil {
+			for _, variant := range *variants {
+				if variant.Price != nil {
+					point.VariantPrices[variant.ID] = *variant.Price
+				}
+			}
+		}
+
+		points = &[]entities.PricePoint{point}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"currentPrice": product.Price,
+			"prices":       points,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// gagal mencatat history tidak menggagalkan perubahan product yang sudah tersimpan, cukup di log.
+// harga variant ikut dicatat, product yang tidak membawa variant pakai variant yang tersimpan sekarang
+func recordProductHistory(s datastore.Store, productId, changedBy string, before, after *entities.Product) {
+
+	variants, err := s.ListProductVariants(productId)
+	if err != nil {
+		log.Println("error when recording product history", productId, err)
+		return
+	}
+
+	before = withVariants(before, *variants)
+	after = withVariants(after, *variants)
+
+	if err := s.CreateProductHistory(productId, changedBy, before, after); err != nil {
+		log.Println("error when recording product history", productId, err)
+	}
+}
+
+// copy product dengan variant, product asli tidak diubah karena bisa jadi masih dipakai untuk response
+func withVariants(p *entities.Product, variants []entities.ProductVariant) *entities.Product {
+
+	if p == nil || p.Variants != nil {
+		return p
+	}
+
+	product := *p
+	product.Variants = variants
+
+	return &product
+}
@@ -0,0 +1,471 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/media"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ProductImageUseCase interface {
+	UploadProductImage(s datastore.Store, m media.MediaStore, w http.ResponseWriter, r *http.Request) types.AppError
+	ListProductImage(s datastore.Store, m media.MediaStore, w http.ResponseWriter, r *http.Request) types.AppError
+	DeleteProductImage(s datastore.Store, m media.MediaStore, w http.ResponseWriter, r *http.Request) types.AppError
+	ReorderProductImage(s datastore.Store, m media.MediaStore, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+func UploadProductImage(s datastore.Store, m media.MediaStore, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	existingImages, err := s.ListProductImages(productIdUrlPath)
+	if err != nil {
+
+		log.Println("error when listing product images in UploadProductImage", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when uploading image, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	// batasi total body, sisa slot gambar * ukuran maksimal per gambar
+	remainingSlot := validator.MAXPRODUCTIMAGES - len(*existingImages)
+	if remainingSlot < 1 {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product can only have %d images", validator.MAXPRODUCTIMAGES),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, int64(remainingSlot*media.MAXIMAGESIZE)+(1<<20))
+	if err := r.ParseMultipartForm(media.MAXIMAGESIZE); err != nil {
+
+		log.Println("error when parsing multipart form in UploadProductImage", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing image"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["image"]
+	if len(files) == 0 {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing image"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if len(files) > remainingSlot {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product can only have %d images", validator.MAXPRODUCTIMAGES),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	// validasi semua file dulu, kalau ada satu yang tidak valid tidak ada yang disimpan
+	uploads := make([][]byte, 0, len(files))
+	for _, fileHeader := range files {
+		if fileHeader.Size > media.MAXIMAGESIZE {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Image %s too large, max %d bytes", fileHeader.Filename, media.MAXIMAGESIZE),
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Invalid/missing image"),
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		data, err := io.ReadAll(io.LimitReader(file, media.MAXIMAGESIZE+1))
+		file.Close()
+		if err != nil {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Invalid/missing image"),
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		if _, err := media.ValidateImage(data); err != nil {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Image %s: %s", fileHeader.Filename, err.Error()),
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		uploads = append(uploads, data)
+	}
+
+	// file yang sudah tersimpan dihapus lagi kalau ada langkah yang gagal
+	results := make([]*media.UploadResult, 0, len(uploads))
+	rollback := func() {
+		for _, result := range results {
+			result.Delete(m)
+		}
+	}
+
+	imgs := make([]entities.ProductImage, 0, len(uploads))
+	for i, data := range uploads {
+		id := uuid.NewString()
+		result, err := media.ProcessImageUpload(m, "products/"+productIdUrlPath+"/"+id, data)
+		if err != nil {
+
+			log.Println("error when processing image upload", err)
+			rollback()
+
+			return types.AppError{
+				Error:  fmt.Errorf("Image %s: %s", files[i].Filename, err.Error()),
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		results = append(results, result)
+		imgs = append(imgs, entities.ProductImage{
+			ID:          id,
+			ProductId:   productIdUrlPath,
+			ContentType: result.ContentType,
+			StorageKey:  result.Key,
+		})
+	}
+
+	// jumlah gambar dicek ulang di store, bisa saja ada upload lain yang masuk duluan
+	err = s.CreateProductImages(productIdUrlPath, imgs, validator.MAXPRODUCTIMAGES)
+	if err == datastore.ErrProductImageLimit {
+
+		rollback()
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product can only have %d images", validator.MAXPRODUCTIMAGES),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when creating product images", err)
+		rollback()
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when uploading image, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	images, appErr := refreshProductImages(s, m, productIdUrlPath)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	resp := types.ServerResponse{
+		Message: "Image uploaded successfully",
+		Data: map[string]interface{}{
+			"images": images,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func ListProductImage(s datastore.Store, m media.MediaStore, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+
+	if _, err := getViewableProduct(s, r, productIdUrlPath); err.Error != nil {
+		return err
+	}
+
+	images, err := s.ListProductImages(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching images"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"images": withImageUrls(m, *images),
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func DeleteProductImage(s datastore.Store, m media.MediaStore, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	imageIdUrlPath := vars["imageId"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	if !helper.ValidateUUID(imageIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Image didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	img, err := s.GetProductImage(imageIdUrlPath)
+	if err != nil || img.ProductId != productIdUrlPath {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Image didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if err := s.DeleteProductImage(img.ID); err != nil {
+
+		log.Println("error when deleting product image", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting image, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if err := media.DeleteImage(m, img.StorageKey, imagePrefix(img.StorageKey)); err != nil {
+		log.Println("error when deleting image file", err)
+	}
+
+	images, appErr := refreshProductImages(s, m, productIdUrlPath)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	resp := types.ServerResponse{
+		Message: "Image deleted successfully",
+		Data: map[string]interface{}{
+			"images": images,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func ReorderProductImage(s datastore.Store, m media.MediaStore, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type reorderStruct struct {
+		ImageIds []string `json:"imageIds"`
+	}
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in ReorderProductImage")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload *reorderStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil || payload == nil {
+
+		log.Println("error when Unmarshal body in reorder product image usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	images, err := s.ListProductImages(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching images"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if err := validator.ValidateReorderProductImagesPayload(payload.ImageIds, *images); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.ReorderProductImages(productIdUrlPath, payload.ImageIds); err != nil {
+
+		log.Println("error when reordering product images", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when reordering images, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	newImages, appErr := refreshProductImages(s, m, productIdUrlPath)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	resp := types.ServerResponse{
+		Message: "Image reordered successfully",
+		Data: map[string]interface{}{
+			"images": newImages,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func checkProductOwner(s datastore.Store, productId, userId string) types.AppError {
+
+	if !helper.ValidateUUID(productId) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	sellerId, err := s.GetProductSeller(productId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if sellerId != userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// ambil ulang gambar setelah ada perubahan, lalu sinkronkan imageUrl product dengan gambar pertama
+func refreshProductImages(s datastore.Store, m media.MediaStore, productId string) ([]entities.ProductImage, types.AppError) {
+
+	images, err := s.ListProductImages(productId)
+	if err != nil {
+
+		log.Println("error when listing product images", err)
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Error when fetching images"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	result := withImageUrls(m, *images)
+
+	primaryUrl := ""
+	if len(result) > 0 {
+		primaryUrl = result[0].Url
+	}
+
+	if err := s.UpdateProductImageUrl(productId, primaryUrl); err != nil {
+		log.Println("error when updating product imageUrl", err)
+	}
+
+	return result, types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func withImageUrls(m media.MediaStore, images []entities.ProductImage) []entities.ProductImage {
+
+	for i := range images {
+		prefix := imagePrefix(images[i].StorageKey)
+		images[i].Url = m.URL(images[i].StorageKey)
+		images[i].Thumbnails = map[string]string{}
+
+		for name := range media.ThumbnailSizes {
+			images[i].Thumbnails[name] = m.URL(media.ThumbnailKey(prefix, name))
+		}
+	}
+
+	return images
+}
+
+func imagePrefix(key string) string {
+
+	return strings.TrimSuffix(key, path.Ext(key))
+}
@@ -0,0 +1,578 @@
+package usecases
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+type ProductImportUseCase interface {
+	ImportProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	GetProductImport(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ExportProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// kolom file import/export, urutan kolom csv saat export.
+// tags di csv dipisah dengan "|"
+var productFileColumns = []string{"sku", "name", "price", "stock", "condition", "tags", "isPurchaseable", "descriptions", "categoryId", "imageUrl", "status"}
+
+// satu row file import/export
+type productFileRow struct {
+	Sku            string   `json:"sku"`
+	Name           string   `json:"name"`
+	Price          float64  `json:"price"`
+	Stock          int      `json:"stock"`
+	Condition      string   `json:"condition"`
+	Tags           []string `json:"tags"`
+	IsPurchaseable *bool    `json:"isPurchaseable"` // kosong = true
+	Descriptions   string   `json:"descriptions"`
+	CategoryId     string   `json:"categoryId"`
+	ImageUrl       string   `json:"imageUrl"`
+	Status         string   `json:"status"` // hanya dipakai untuk product baru
+}
+
+type parsedProductRow struct {
+	Row  int
+	Data productFileRow
+	Err  error
+}
+
+// terima file csv/jsonl lalu antrikan untuk diproses di background.
+// POST /v1/product/import?format=csv|jsonl (atau dari Content-Type)
+func ImportProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	sellerId := auth.GetUserIdFromJWT(r)
+
+	if err := checkVerifiedSeller(s, sellerId); err.Error != nil {
+		return err
+	}
+
+	format := validator.ValidateProductFileFormat(r.URL.Query().Get("format"), r.Header.Get("Content-Type"))
+	if format == "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid import format, use csv or jsonl"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, validator.MAXPRODUCTIMPORTSIZE+1))
+	if err != nil {
+
+		log.Println("error when reading body in ImportProduct usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	if err := validator.ValidateProductImportPayload(body); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	id := uuid.NewString()
+	job := entities.ProductImportJob{
+		SellerId: sellerId,
+		Format:   format,
+		Payload:  string(body),
+	}
+
+	if err := s.CreateProductImportJob(id, &job); err != nil {
+
+		log.Println("error when creating product import job", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating import job, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	newJob, err := s.GetProductImportJob(id)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching import job"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Import job queued",
+		Data: map[string]interface{}{
+			"job": newJob,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusAccepted, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusAccepted,
+	}
+}
+
+// status job import beserta error tiap row. GET /v1/product/import/{id}
+func GetProductImport(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	jobIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !helper.ValidateUUID(jobIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Import job didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	job, err := s.GetProductImportJob(jobIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Import job didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if job.SellerId != userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"job": job,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// seluruh katalog seller dalam format yang sama dengan file import.
+// GET /v1/product/export?format=csv|jsonl, default csv
+func ExportProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	sellerId := auth.GetUserIdFromJWT(r)
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	format = validator.ValidateProductFileFormat(format, "")
+	if format == "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid export format, use csv or jsonl"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	products, err := s.ListSellerProducts(sellerId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching products"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeProductFile(&buf, format, *products); err != nil {
+
+		log.Println("error when writing product export", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when exporting products, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	contentType := "text/csv"
+	if format == "jsonl" {
+		contentType = "application/x-ndjson"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="products.`+format+`"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// proses job import, dipanggil oleh job import di background. hasilnya diisi ke job,
+// row yang gagal tidak membatalkan row lain
+func ProcessProductImport(s datastore.Store, job *entities.ProductImportJob) {
+
+	job.CreatedCount, job.UpdatedCount, job.FailedCount = 0, 0, 0
+	job.Errors = []entities.ProductImportError{}
+
+	rows, err := parseProductFile(job.Format, job.Payload)
+	if err != nil {
+		job.Status = "failed"
+		job.Errors = append(job.Errors, entities.ProductImportError{Error: err.Error()})
+		return
+	}
+
+	job.TotalRows = len(rows)
+
+	for _, row := range rows {
+		err := row.Err
+		created := false
+
+		if err == nil {
+			created, err = importProductRow(s, job.SellerId, row.Data)
+		}
+
+		switch {
+		case err != nil:
+			job.FailedCount++
+			job.Errors = append(job.Errors, entities.ProductImportError{
+				Row:   row.Row,
+				Sku:   row.Data.Sku,
+				Error: err.Error(),
+			})
+		case created:
+			job.CreatedCount++
+		default:
+			job.UpdatedCount++
+		}
+	}
+
+	job.Status = "completed"
+}
+
+// buat product baru atau update product seller dengan sku yang sama, return true kalau product baru
+func importProductRow(s datastore.Store, sellerId string, row productFileRow) (bool, error) {
+
+	failed := fmt.Errorf("Failed to import product, please try again.")
+
+	if row.Sku == "" {
+		return false, fmt.Errorf("Missing product sku")
+	}
+
+	product := row.product()
+
+	if err := validator.ValidateCreateProductPayload(product); err != nil {
+		return false, err
+	}
+
+	if err := checkLeafCategory(s, product.CategoryId); err.Error != nil {
+		return false, err.Error
+	}
+
+	existing, err := s.GetProductBySku(sellerId, product.Sku)
+	if err != nil {
+		log.Println("error when getting product by sku in import", err)
+		return false, failed
+	}
+
+	if existing == nil {
+		if err := validator.ValidateProductStatusPayload(product, time.Now()); err != nil {
+			return false, err
+		}
+
+		id := uuid.NewString()
+		if err := s.CreateProduct(id, sellerId, product); err != nil {
+			if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+				return false, fmt.Errorf("Product sku already used")
+			}
+
+			log.Println("error when creating product in import", err)
+			return false, failed
+		}
+
+		if newProduct, err := s.GetProductById(id); err == nil {
+			recordProductHistory(s, id, sellerId, nil, newProduct)
+		}
+
+		return true, nil
+	}
+
+	// status, stock dan imageUrl product yang sudah ada tidak diubah lewat import, pakai endpoint status,
+	// POST /v1/product/{id}/stock dan endpoint gambar supaya stock yang sudah direservasi checkout tidak tertimpa
+	if err := s.UpdateProduct(existing.ID, product); err != nil {
+		log.Println("error when updating product in import", err)
+		return false, failed
+	}
+
+	if updatedProduct, err := s.GetProductById(existing.ID); err == nil {
+		recordProductHistory(s, existing.ID, sellerId, existing, updatedProduct)
+	}
+
+	return false, nil
+}
+
+// parse isi file import per row, error di level file (header, jumlah row) membatalkan seluruh job
+func parseProductFile(format, payload string) ([]parsedProductRow, error) {
+
+	var rows []parsedProductRow
+	var err error
+
+	switch format {
+	case "csv":
+		rows, err = parseProductCsv(payload)
+	case "jsonl":
+		rows, err = parseProductJsonl(payload)
+	default:
+		return nil, fmt.Errorf("Invalid import format")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) > validator.MAXPRODUCTIMPORTROWS {
+		return nil, fmt.Errorf("Import file has too many rows, maximum is %d", validator.MAXPRODUCTIMPORTROWS)
+	}
+
+	return rows, nil
+}
+
+func parseProductCsv(payload string) ([]parsedProductRow, error) {
+
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(payload, "\ufeff")))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid csv header")
+	}
+
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+
+	for _, required := range []string{"sku", "name", "price"} {
+		if _, ok := columns[strings.ToLower(required)]; !ok {
+			return nil, fmt.Errorf("Missing csv column %s", required)
+		}
+	}
+
+	rows := []parsedProductRow{}
+	for rowNumber := 1; ; rowNumber++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			rows = append(rows, parsedProductRow{Row: rowNumber, Err: fmt.Errorf("Invalid csv row")})
+			continue
+		}
+
+		get := func(column string) string {
+			i, ok := columns[strings.ToLower(column)]
+			if !ok || i >= len(record) {
+				return ""
+			}
+
+			return strings.TrimSpace(record[i])
+		}
+
+		row := productFileRow{
+			Sku:          get("sku"),
+			Name:         get("name"),
+			Condition:    get("condition"),
+			Descriptions: get("descriptions"),
+			CategoryId:   get("categoryId"),
+			ImageUrl:     get("imageUrl"),
+			Status:       get("status"),
+		}
+
+		var invalidFields []string
+
+		if row.Price, err = strconv.ParseFloat(get("price"), 64); err != nil {
+			invalidFields = append(invalidFields, "product price")
+		}
+
+		if stock := get("stock"); stock != "" {
+			if row.Stock, err = strconv.Atoi(stock); err != nil {
+				invalidFields = append(invalidFields, "product stock")
+			}
+		}
+
+		if purchaseable := get("isPurchaseable"); purchaseable != "" {
+			parsed, err := strconv.ParseBool(purchaseable)
+			if err != nil {
+				invalidFields = append(invalidFields, "product isPurchaseable")
+			}
+
+			row.IsPurchaseable = &parsed
+		}
+
+		for _, tag := range strings.Split(get("tags"), "|") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				row.Tags = append(row.Tags, tag)
+			}
+		}
+
+		parsed := parsedProductRow{Row: rowNumber, Data: row}
+		if len(invalidFields) > 0 {
+			parsed.Err = fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+		}
+
+		rows = append(rows, parsed)
+	}
+
+	return rows, nil
+}
+
+// satu object json per baris, baris kosong dilewati
+func parseProductJsonl(payload string) ([]parsedProductRow, error) {
+
+	scanner := bufio.NewScanner(strings.NewReader(payload))
+	scanner.Buffer(make([]byte, 0, 64*1024), validator.MAXPRODUCTIMPORTSIZE)
+
+	rows := []parsedProductRow{}
+	rowNumber := 0
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		rowNumber++
+		parsed := parsedProductRow{Row: rowNumber}
+		if err := json.Unmarshal([]byte(line), &parsed.Data); err != nil {
+			parsed.Err = fmt.Errorf("Invalid json row")
+		}
+
+		parsed.Data.Sku = strings.TrimSpace(parsed.Data.Sku)
+		rows = append(rows, parsed)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("Invalid jsonl file")
+	}
+
+	return rows, nil
+}
+
+func writeProductFile(w io.Writer, format string, products []entities.Product) error {
+
+	if format == "jsonl" {
+		encoder := json.NewEncoder(w)
+		for _, product := range products {
+			if err := encoder.Encode(newProductFileRow(product)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(productFileColumns); err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		row := newProductFileRow(product)
+		if err := writer.Write([]string{
+			row.Sku,
+			row.Name,
+			strconv.FormatFloat(row.Price, 'f', -1, 64),
+			strconv.Itoa(row.Stock),
+			row.Condition,
+			strings.Join(row.Tags, "|"),
+			strconv.FormatBool(*row.IsPurchaseable),
+			row.Descriptions,
+			row.CategoryId,
+			row.ImageUrl,
+			row.Status,
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func newProductFileRow(p entities.Product) productFileRow {
+
+	isPurchaseable := p.IsPurchaseable
+	tags := []string(p.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return productFileRow{
+		Sku:            p.Sku,
+		Name:           p.Name,
+		Price:          p.Price,
+		Stock:          p.Stock,
+		Condition:      p.Condition,
+		Tags:           tags,
+		IsPurchaseable: &isPurchaseable,
+		Descriptions:   p.Descriptions,
+		CategoryId:     p.CategoryId,
+		ImageUrl:       p.ImageUrl,
+		Status:         p.Status,
+	}
+}
+
+func (row productFileRow) product() *entities.Product {
+
+	isPurchaseable := true
+	if row.IsPurchaseable != nil {
+		isPurchaseable = *row.IsPurchaseable
+	}
+
+	return &entities.Product{
+		Sku:            row.Sku,
+		Name:           strings.TrimSpace(row.Name),
+		Price:          row.Price,
+		Stock:          row.Stock,
+		Condition:      row.Condition,
+		Tags:           pq.StringArray(row.Tags),
+		IsPurchaseable: isPurchaseable,
+		Descriptions:   row.Descriptions,
+		CategoryId:     row.CategoryId,
+		ImageUrl:       row.ImageUrl,
+		Status:         row.Status,
+	}
+}
@@ -0,0 +1,374 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ProductQuestionUseCase interface {
+	ListProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	CreateProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	AnswerProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	HideProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	DeleteProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// GET /v1/product/{id}/question?limit=&offset=
+func ListProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+
+	product, appErr := getViewableProduct(s, r, productIdUrlPath)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	queryParams := r.URL.Query()
+	limit, offset := validator.ValidateListProductQuestionQuery(queryParams.Get("limit"), queryParams.Get("offset"))
+
+	questions, pageInfo, err := listProductQuestions(s, productIdUrlPath, product.SellerId, auth.GetUserIdFromJWT(r), limit, offset)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching questions"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"questions":  questions,
+			"pagination": pageInfo,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// POST /v1/product/{id}/question
+func CreateProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type questionStruct struct {
+		Question string `json:"question"`
+	}
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !helper.ValidateUUID(productIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	sellerId, err := s.GetProductSeller(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if sellerId == userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Cannot ask question on your own product"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	var payload questionStruct
+	if appErr := readQuestionBody(r, &payload); appErr.Error != nil {
+		return appErr
+	}
+
+	if err := validator.ValidateProductQuestionPayload(payload.Question); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	question := entities.ProductQuestion{
+		ProductId: productIdUrlPath,
+		AskerId:   userId,
+		Question:  strings.TrimSpace(payload.Question),
+	}
+
+	id := uuid.NewString()
+
+	if err := s.CreateProductQuestion(id, &question); err != nil {
+
+		log.Println("error when creating product question", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating question, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeProductQuestion(s, w, id, http.StatusCreated, "Question created successfully")
+}
+
+// hanya seller pemilik product yang bisa menjawab, jawaban baru menimpa jawaban lama.
+// PUT /v1/product/{id}/question/{questionId}/answer
+func AnswerProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type answerStruct struct {
+		Answer string `json:"answer"`
+	}
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	question, appErr := checkQuestionOfProduct(s, productIdUrlPath, vars["questionId"])
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	var payload answerStruct
+	if appErr := readQuestionBody(r, &payload); appErr.Error != nil {
+		return appErr
+	}
+
+	if err := validator.ValidateProductAnswerPayload(payload.Answer); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.AnswerProductQuestion(question.ID, strings.TrimSpace(payload.Answer)); err != nil {
+
+		log.Println("error when answering product question", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when answering question, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeProductQuestion(s, w, question.ID, http.StatusOK, "Question answered successfully")
+}
+
+// moderasi oleh seller pemilik product atau admin, PATCH /v1/product/{id}/question/{questionId}/visibility
+func HideProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type visibilityStruct struct {
+		Hidden *bool `json:"hidden"`
+	}
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !auth.IsAdmin(userId) {
+		if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+			return err
+		}
+	}
+
+	question, appErr := checkQuestionOfProduct(s, productIdUrlPath, vars["questionId"])
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	var payload visibilityStruct
+	if appErr := readQuestionBody(r, &payload); appErr.Error != nil {
+		return appErr
+	}
+
+	if payload.Hidden == nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.SetProductQuestionHidden(question.ID, *payload.Hidden); err != nil {
+
+		log.Println("error when updating product question visibility", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating question, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeProductQuestion(s, w, question.ID, http.StatusOK, "Question updated successfully")
+}
+
+// penanya bisa hapus pertanyaan yang belum dijawab, admin bisa hapus semua.
+// DELETE /v1/product/{id}/question/{questionId}
+func DeleteProductQuestion(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	question, appErr := checkQuestionOfProduct(s, productIdUrlPath, vars["questionId"])
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if !auth.IsAdmin(userId) && (question.AskerId != userId || question.Answer != "") {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	if err := s.DeleteProductQuestion(question.ID); err != nil {
+
+		log.Println("error when deleting product question", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting question, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Question deleted successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// pertanyaan tersembunyi hanya terlihat oleh seller pemilik product dan admin
+func listProductQuestions(s datastore.Store, productId, sellerId, userId string, limit, offset int) (*[]entities.ProductQuestion, *types.OffsetPageInfo, error) {
+
+	includeHidden := userId != "" && (userId == sellerId || auth.IsAdmin(userId))
+
+	questions, total, err := s.ListProductQuestions(productId, limit, offset, includeHidden)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return questions, &types.OffsetPageInfo{
+		Limit:  limit,
+		Offset: offset,
+		Total:  total,
+	}, nil
+}
+
+func checkQuestionOfProduct(s datastore.Store, productId, questionId string) (*entities.ProductQuestion, types.AppError) {
+
+	if !helper.ValidateUUID(productId) || !helper.ValidateUUID(questionId) {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Question didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	question, err := s.GetProductQuestion(questionId)
+	if err != nil || question.ProductId != productId {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Question didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	return question, types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func writeProductQuestion(s datastore.Store, w http.ResponseWriter, questionId string, status int, message string) types.AppError {
+
+	question, err := s.GetProductQuestion(questionId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching question"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: message,
+		Data: map[string]interface{}{
+			"question": question,
+		},
+	}
+
+	helper.WriteJson(w, status, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: status,
+	}
+}
+
+func readQuestionBody(r *http.Request, payload interface{}) types.AppError {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in product question usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	if err := json.Unmarshal(body, payload); err != nil {
+
+		log.Println("error when Unmarshal body in product question usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,374 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+type ProductVariantUseCase interface {
+	CreateProductVariant(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ListProductVariant(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UpdateProductVariant(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	DeleteProductVariant(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+func CreateProductVariant(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	variant, appErr := readProductVariantPayload(s, productIdUrlPath, r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	before, beforeErr := getProductWithVariants(s, productIdUrlPath)
+	id := uuid.NewString()
+
+	if err := s.CreateProductVariant(id, productIdUrlPath, variant); err != nil {
+
+		log.Println("error when creating product variant", err)
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Failed when creating variant, sku already used"),
+				Status: http.StatusConflict,
+			}
+		}
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating variant, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	newVariant, err := s.GetProductVariant(id)
+	if err != nil {
+
+		log.Println("error when getting product variant", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating variant, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	recordVariantPriceHistory(s, productIdUrlPath, userId, before, beforeErr)
+
+	resp := types.ServerResponse{
+		Message: "Variant created successfully",
+		Data: map[string]interface{}{
+			"variant": newVariant,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+func ListProductVariant(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+
+	if _, err := getViewableProduct(s, r, productIdUrlPath); err.Error != nil {
+		return err
+	}
+
+	variants, err := s.ListProductVariants(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching variants"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"variants": variants,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func UpdateProductVariant(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	variantIdUrlPath := vars["variantId"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	if err := checkVariantOfProduct(s, productIdUrlPath, variantIdUrlPath); err.Error != nil {
+		return err
+	}
+
+	variant, appErr := readProductVariantPayload(s, productIdUrlPath, r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	before, beforeErr := getProductWithVariants(s, productIdUrlPath)
+
+	if err := s.UpdateProductVariant(variantIdUrlPath, variant); err != nil {
+
+		log.Println("error when updating product variant", err)
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Failed when updating variant, sku already used"),
+				Status: http.StatusConflict,
+			}
+		}
+
+		if err == datastore.ErrInsufficientStock {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Stock is lower than the stock allocated to warehouses"),
+				Status: http.StatusConflict,
+			}
+		}
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating variant, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	respVariant, err := s.GetProductVariant(variantIdUrlPath)
+	if err != nil {
+
+		log.Println("error when getting product variant", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating variant, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	recordVariantPriceHistory(s, productIdUrlPath, userId, before, beforeErr)
+
+	resp := types.ServerResponse{
+		Message: "Variant updated successfully",
+		Data: map[string]interface{}{
+			"variant": respVariant,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func DeleteProductVariant(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	variantIdUrlPath := vars["variantId"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	if err := checkVariantOfProduct(s, productIdUrlPath, variantIdUrlPath); err.Error != nil {
+		return err
+	}
+
+	before, beforeErr := getProductWithVariants(s, productIdUrlPath)
+
+	if err := s.DeleteProductVariant(variantIdUrlPath); err != nil {
+
+		log.Println("error when deleting product variant", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting variant, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	recordVariantPriceHistory(s, productIdUrlPath, userId, before, beforeErr)
+
+	resp := types.ServerResponse{
+		Message: "Variant deleted successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// product beserta variant-nya, dipakai sebagai snapshot history sebelum/sesudah variant berubah
+func getProductWithVariants(s datastore.Store, productId string) (*entities.Product, error) {
+
+	product, err := s.GetProductById(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	variants, err := s.ListProductVariants(productId)
+	if err != nil {
+		return nil, err
+	}
+
+	product.Variants = *variants
+
+	return product, nil
+}
+
+// perubahan harga variant dicatat sebagai versi product baru, perubahan variant tanpa harga tidak menghasilkan versi
+func recordVariantPriceHistory(s datastore.Store, productId, changedBy string, before *entities.Product, beforeErr error) {
+
+	if beforeErr != nil {
+		log.Println("error when recording product history", productId, beforeErr)
+		return
+	}
+
+	after, err := getProductWithVariants(s, productId)
+	if err != nil {
+		log.Println("error when recording product history", productId, err)
+		return
+	}
+
+	recordProductHistory(s, productId, changedBy, before, after)
+}
+
+func checkVariantOfProduct(s datastore.Store, productId, variantId string) types.AppError {
+
+	if !helper.ValidateUUID(variantId) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Variant didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	variant, err := s.GetProductVariant(variantId)
+	if err != nil || variant.ProductId != productId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Variant didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// baca dan validasi payload variant, imageIds harus gambar milik product yang sama
+func readProductVariantPayload(s datastore.Store, productId string, r *http.Request) (*entities.ProductVariant, types.AppError) {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in product variant usecase")
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var variant *entities.ProductVariant
+
+	err = json.Unmarshal(body, &variant)
+	if err != nil || variant == nil {
+
+		log.Println("error when Unmarshal body in product variant usecase", err)
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := validator.ValidateProductVariantPayload(variant); err != nil {
+
+		return nil, types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if variant.ImageIds == nil {
+		variant.ImageIds = pq.StringArray{}
+	}
+
+	if len(variant.ImageIds) > 0 {
+		images, err := s.ListProductImages(productId)
+		if err != nil {
+
+			return nil, types.AppError{
+				Error:  fmt.Errorf("Error when fetching images"),
+				Status: http.StatusInternalServerError,
+			}
+		}
+
+		productImages := map[string]bool{}
+		for _, img := range *images {
+			productImages[img.ID] = true
+		}
+
+		for _, imageId := range variant.ImageIds {
+			if !productImages[imageId] {
+
+				return nil, types.AppError{
+					Error:  fmt.Errorf("Invalid variant imageIds"),
+					Status: http.StatusBadRequest,
+				}
+			}
+		}
+	}
+
+	return variant, types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
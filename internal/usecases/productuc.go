@@ -1,12 +1,16 @@
 package usecases
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/GetterSethya/golangApiMarketplace/config"
 	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
 	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
 	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
@@ -15,6 +19,7 @@ import (
 	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/lib/pq"
 )
 
 type ProductUseCase interface {
@@ -23,12 +28,52 @@ type ProductUseCase interface {
 	GetProductById(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 	ListProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 	DeleteProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	RestoreProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UpdateProductStatus(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	SuggestProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 }
 
+// autocomplete nama product untuk typeahead, GET /v1/product/suggest?q=
+func SuggestProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	queryParams := r.URL.Query()
+	search, limit := validator.ValidateSuggestProductQuery(queryParams.Get("q"), queryParams.Get("limit"))
+
+	suggestions, err := s.SuggestProducts(search, limit)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching suggestions"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"suggestions": suggestions,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// ubah stock lewat ledger. payload {"delta", "type", "reason", "variantId"} untuk perubahan relatif,
+// {"stock"} (format lama) tetap didukung dan dicatat sebagai adjustment
 func UpdateStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
 
 	type stockStruct struct {
-		Stock int `json:"stock"`
+		Stock       *int   `json:"stock"`
+		Delta       int    `json:"delta"`
+		Type        string `json:"type"`
+		Reason      string `json:"reason"`
+		VariantId   string `json:"variantId"`
+		WarehouseId string `json:"warehouseId"`
 	}
 
 	vars := mux.Vars(r)
@@ -51,7 +96,7 @@ func UpdateStock(s datastore.Store, w http.ResponseWriter, r *http.Request) type
 	var stock *stockStruct
 
 	err = json.Unmarshal(body, &stock)
-	if err != nil {
+	if err != nil || stock == nil {
 
 		log.Println("error when Unmarshal body in update stock product usecase", err)
 
@@ -78,7 +123,34 @@ func UpdateStock(s datastore.Store, w http.ResponseWriter, r *http.Request) type
 		}
 	}
 
-	if err := s.UpdateStockProduct(productIdUrlPath, stock.Stock); err != nil {
+	movement := entities.StockMovement{
+		ProductId:   product.ID,
+		VariantId:   stock.VariantId,
+		WarehouseId: stock.WarehouseId,
+		Type:        stock.Type,
+		Delta:       stock.Delta,
+		Reason:      strings.TrimSpace(stock.Reason),
+		ActorId:     userId,
+	}
+
+	if stock.Stock != nil {
+		movement.Type = "adjustment"
+		err = validator.ValidateSetStockPayload(*stock.Stock, &movement)
+	} else {
+		err = validator.ValidateStockMovementPayload(&movement)
+	}
+
+	if err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	// stock product yang punya variant dihitung dari stock variantnya, jadi wajib pilih variant
+	variants, err := s.ListProductVariants(product.ID)
+	if err != nil {
 
 		return types.AppError{
 			Error:  fmt.Errorf("failed to update stock"),
@@ -86,9 +158,64 @@ func UpdateStock(s datastore.Store, w http.ResponseWriter, r *http.Request) type
 		}
 	}
 
+	if len(*variants) > 0 && movement.VariantId == "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product has variants, update the variant stock instead"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if movement.VariantId != "" && !hasVariant(*variants, movement.VariantId) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid stock variantId"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if movement.WarehouseId != "" {
+		if err := checkWarehouseOwner(s, movement.WarehouseId, userId); err.Error != nil {
+			return err
+		}
+	}
+
+	var stockAfter int
+	if stock.Stock != nil {
+		stockAfter, err = s.SetStock(&movement, *stock.Stock)
+	} else {
+		stockAfter, err = s.AdjustStock(&movement)
+	}
+
+	if err == datastore.ErrInsufficientStock {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when updating stock", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("failed to update stock"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if updatedProduct, err := s.GetProductById(productIdUrlPath); err == nil {
+		recordProductHistory(s, productIdUrlPath, userId, product, updatedProduct)
+	}
+
+	notifyBackInStock(s, product.ID, product.Stock)
+
 	resp := types.ServerResponse{
 		Message: "Stock updated successfully",
-		Data:    nil,
+		Data: map[string]interface{}{
+			"stock": stockAfter,
+		},
 	}
 
 	helper.WriteJson(w, http.StatusOK, resp)
@@ -145,15 +272,221 @@ func DeleteProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) ty
 	}
 }
 
+// product yang dihapus masih bisa dikembalikan selama grace period, POST /v1/product/{id}/restore
+func RestoreProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !helper.ValidateUUID(productIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	err := s.RestoreProduct(productIdUrlPath, userId, config.LoadConfig().App.DeleteGracePeriod)
+	switch {
+	case err == sql.ErrNoRows:
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist or can no longer be restored"),
+			Status: http.StatusNotFound,
+		}
+	case err != nil:
+
+		log.Println("error when restoring product", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when restoring product, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	product, err := s.GetProductById(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching product"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Product restored successfully",
+		Data: map[string]interface{}{
+			"product": product,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// ubah status listing (draft/scheduled/published/archived) beserta jadwalnya, PATCH /v1/product/{id}/status
+func UpdateProductStatus(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type statusStruct struct {
+		Status    string     `json:"status"`
+		PublishAt *time.Time `json:"publishAt"`
+		ArchiveAt *time.Time `json:"archiveAt"`
+	}
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload statusStruct
+	if err := json.Unmarshal(body, &payload); err != nil {
+
+		log.Println("error when Unmarshal body in update product status usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	currentProduct, err := s.GetProductById(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	product := *currentProduct
+	product.Status = strings.ToLower(strings.TrimSpace(payload.Status))
+	product.PublishAt = payload.PublishAt
+	product.ArchiveAt = payload.ArchiveAt
+
+	// product yang sudah published tetap pakai waktu publish lama
+	if product.Status == "published" && currentProduct.Status == "published" && product.PublishAt == nil {
+		product.PublishAt = currentProduct.PublishAt
+	}
+
+	if product.Status == "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid status"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := validator.ValidateProductStatusPayload(&product, time.Now()); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.UpdateProductStatus(productIdUrlPath, &product); err != nil {
+
+		log.Println("error when updating product status", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed to update product status"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	respProduct, err := s.GetProductById(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed to update product status"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	recordProductHistory(s, productIdUrlPath, userId, currentProduct, respProduct)
+
+	resp := types.ServerResponse{
+		Message: "Product status updated successfully",
+		Data: map[string]interface{}{
+			"product": respProduct,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// product yang belum/tidak published hanya terlihat oleh seller pemiliknya dan admin
+func canViewProduct(product *entities.Product, userId string) bool {
+
+	return product.Status == "published" || (userId != "" && (userId == product.SellerId || auth.IsAdmin(userId)))
+}
+
+// product untuk endpoint publik turunan product (variant, image, review, question),
+// 404 kalau product sudah dihapus atau belum boleh dilihat user
+func getViewableProduct(s datastore.Store, r *http.Request, productId string) (*entities.Product, types.AppError) {
+
+	if !helper.ValidateUUID(productId) {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	product, err := s.GetProductById(productId)
+	if err != nil || product.DeletedAt.Valid || !canViewProduct(product, auth.GetUserIdFromJWT(r)) {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	return product, types.AppError{}
+}
+
 func ListProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
 	//nampilin list product, GET /v1/product
 	queries := getListProductQuery(r)
 	userid := auth.GetUserIdFromJWT(r)
 
+	// cursor rusak/diubah client ditolak, cursor dari sort lain tetap dianggap halaman pertama
+	if queries.Cursor != "" && helper.DecodeCursor(queries.Cursor) == nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid cursor"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
 	//validasi query
 	validQuery := validator.ValidateListProductQuery(queries)
 
-	products, err := s.ListProducts(validQuery, userid)
+	products, pageInfo, err := s.ListProducts(validQuery, userid)
 	if err != nil {
 
 		return types.AppError{
@@ -162,11 +495,37 @@ func ListProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) type
 		}
 	}
 
+	if err := applyActiveDiscounts(s, *products); err != nil {
+
+		log.Println("error when getting active discounts", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching products"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	data := map[string]interface{}{
+		"products":   products,
+		"pagination": pageInfo,
+	}
+
+	if validQuery.Facets {
+		facets, err := s.ListProductFacets(validQuery, userid)
+		if err != nil {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Error when fetching products"),
+				Status: http.StatusInternalServerError,
+			}
+		}
+
+		data["facets"] = facets
+	}
+
 	resp := types.ServerResponse{
 		Message: "Ok",
-		Data: map[string]interface{}{
-			"products": products,
-		},
+		Data:    data,
 	}
 
 	helper.WriteJson(w, http.StatusOK, resp)
@@ -191,7 +550,7 @@ func GetProductById(s datastore.Store, w http.ResponseWriter, r *http.Request) t
 	}
 
 	product, err := s.GetProductById(productIdUrlPath)
-	if err != nil {
+	if err != nil || !canViewProduct(product, auth.GetUserIdFromJWT(r)) {
 
 		return types.AppError{
 			Error:  fmt.Errorf("Product didnot exist"),
@@ -199,10 +558,50 @@ func GetProductById(s datastore.Store, w http.ResponseWriter, r *http.Request) t
 		}
 	}
 
+	variants, err := s.ListProductVariants(product.ID)
+	if err != nil {
+
+		log.Println("error when getting product variants", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	product.Variants = *variants
+
+	detail := []entities.Product{*product}
+	if err := applyActiveDiscounts(s, detail); err != nil {
+
+		log.Println("error when getting active discount", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	product = &detail[0]
+
+	// halaman pertama Q&A, halaman berikutnya lewat GET /v1/product/{id}/question
+	questions, questionPageInfo, err := listProductQuestions(s, product.ID, product.SellerId, auth.GetUserIdFromJWT(r), validator.PRODUCTDETAILQUESTIONS, 0)
+	if err != nil {
+
+		log.Println("error when getting product questions", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
 	resp := types.ServerResponse{
 		Message: "Ok",
 		Data: map[string]interface{}{
-			"product": product,
+			"product":            product,
+			"questions":          questions,
+			"questionPagination": questionPageInfo,
 		},
 	}
 
@@ -214,6 +613,8 @@ func GetProductById(s datastore.Store, w http.ResponseWriter, r *http.Request) t
 	}
 }
 
+// stock tidak ikut diubah di sini (field stock diabaikan), pakai POST /v1/product/{id}/stock dengan delta
+// supaya stock yang sudah direservasi checkout tidak tertimpa. imageUrl juga diabaikan, ikut gambar product
 func UpdateProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
 
 	vars := mux.Vars(r)
@@ -274,10 +675,57 @@ func UpdateProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) ty
 		}
 	}
 
+	if err := checkLeafCategory(s, product.CategoryId); err.Error != nil {
+		return err
+	}
+
+	currentProduct, err := s.GetProductById(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product did not exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	// sku tidak dikirim = tetap pakai sku lama
+	if product.Sku == "" {
+		product.Sku = currentProduct.Sku
+	}
+
+	if product.Sku != currentProduct.Sku {
+		existing, err := s.GetProductBySku(currentProduct.SellerId, product.Sku)
+		if err != nil {
+
+			log.Println("error when checking product sku in productuc.go:", err)
+
+			return types.AppError{
+				Error:  fmt.Errorf("Failed to update product"),
+				Status: http.StatusInternalServerError,
+			}
+		}
+
+		if existing != nil {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Product sku already used"),
+				Status: http.StatusConflict,
+			}
+		}
+	}
+
 	if err := s.UpdateProduct(productIdUrlPath, product); err != nil {
 
 		log.Println("error when updating product in productuc.go:", err)
 
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Product sku already used"),
+				Status: http.StatusConflict,
+			}
+		}
+
 		return types.AppError{
 			Error:  fmt.Errorf("Failed to update product"),
 			Status: http.StatusInternalServerError,
@@ -295,6 +743,8 @@ func UpdateProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) ty
 		}
 	}
 
+	recordProductHistory(s, productIdUrlPath, userId, currentProduct, respProduct)
+
 	resp := types.ServerResponse{
 		Message: "Product updated susscessfully",
 		Data: map[string]interface{}{
@@ -314,6 +764,10 @@ func CreateProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) ty
 
 	sellerId := auth.GetUserIdFromJWT(r)
 
+	if err := checkVerifiedSeller(s, sellerId); err.Error != nil {
+		return err
+	}
+
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 
@@ -350,12 +804,32 @@ func CreateProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) ty
 		}
 	}
 
+	if err := validator.ValidateProductStatusPayload(product, time.Now()); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := checkLeafCategory(s, product.CategoryId); err.Error != nil {
+		return err
+	}
+
 	id := uuid.NewString()
 
 	if err := s.CreateProduct(id, sellerId, product); err != nil {
 
 		log.Println("error when creating product", err)
 
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Product sku already used"),
+				Status: http.StatusConflict,
+			}
+		}
+
 		return types.AppError{
 			Error:  fmt.Errorf("Failed when creating product, please try again."),
 			Status: http.StatusInternalServerError,
@@ -374,6 +848,8 @@ func CreateProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) ty
 		}
 	}
 
+	recordProductHistory(s, id, sellerId, nil, newProduct)
+
 	resp := types.ServerResponse{
 		Message: "Product created susscessfully",
 		Data: map[string]interface{}{
@@ -396,47 +872,94 @@ func getListProductQuery(r *http.Request) types.ListQuery {
 	// return product created by current user  "true"|"false"
 	userOnly := queryParams.Get("useronly")
 
-	// pagination
+	// pagination, cursor dari pagination.next/prev response sebelumnya (offset diabaikan kalau ada cursor)
 	limit := queryParams.Get("limit")
 	offset := queryParams.Get("offset")
+	cursor := queryParams.Get("cursor")
+
+	// return total product yang cocok dengan filter "true"|"false"
+	withTotal := queryParams.Get("withtotal")
 
 	// filter by tags
 	tags := queryParams["tags"]
 
-	// filter by conditions
+	// product cocok kalau punya salah satu tags "any" atau semua tags "all", default any
+	tagMatch := queryParams.Get("tagmatch")
+
+	// filter by conditions "new"|"second"|"any", default any
 	condition := queryParams.Get("condition")
 
-	// return product by requester user that have 0 stock
+	// (deprecated, pakai stock) "true" = ikutkan product dengan stock 0
 	showEmptyStock := queryParams.Get("showemptystock")
 
+	// product dengan stock 0 "include"|"exclude"|"only", default exclude
+	stock := queryParams.Get("stock")
+
+	// filter by seller id
+	seller := queryParams.Get("seller")
+
+	// filter by slug storefront
+	storefront := queryParams.Get("store")
+
+	// filter by isPurchaseable "true"|"false"
+	purchaseable := queryParams.Get("purchaseable")
+
 	// return where product price bellow maxprice
 	maxPrice := queryParams.Get("maxprice")
 
 	// return where product price higher than minprice
 	minPrice := queryParams.Get("minprice")
 
-	// sort product by "asc"|"desc"
+	// return product dengan rata-rata rating minimal minrating (1-5)
+	minRating := queryParams.Get("minrating")
+
+	// arah sort default untuk order yang tidak menyebut arah "asc"|"desc"
 	sort := queryParams.Get("sort")
 
-	// order product "price"|"date"|"name" default date
+	// order product "price"|"date"|"name"|"stock"|"rating"|"relevance" default date, relevance hanya kalau ada search.
+	// bisa lebih dari satu kolom dengan arah masing-masing, contoh "price:asc,date:desc"
 	order := queryParams.Get("order")
 
-	// get product where name like search query
+	// full-text search di name, descriptions dan tags (prefix match)
 	search := queryParams.Get("search")
 
+	// filter product yang punya variant tersedia dengan atribut "key:value"
+	attributes := queryParams["attr"]
+
+	// filter by category slug, termasuk semua sub category
+	category := queryParams.Get("category")
+
+	// return jumlah per condition, tags, range harga, seller dan ketersediaan stock "true"|"false"
+	facets := queryParams.Get("facets")
+
+	// filter status product sendiri "draft"|"scheduled"|"published"|"archived"|"any", hanya berlaku dengan useronly=true
+	status := queryParams.Get("status")
+
 	return types.ListQuery{
 
 		UserOnly:       userOnly,
 		Limit:          limit,
 		Offset:         offset,
 		Tags:           tags,
+		TagMatch:       tagMatch,
 		Condition:      condition,
 		ShowEmptyStock: showEmptyStock,
+		Stock:          stock,
+		Seller:         seller,
+		Storefront:     storefront,
+		Purchaseable:   purchaseable,
 		MaxPrice:       maxPrice,
 		MinPrice:       minPrice,
+		MinRating:      minRating,
 		Sort:           sort,
 		Order:          order,
 		Search:         search,
+		Attributes:     attributes,
+		Category:       category,
+		Facets:         facets,
+		Cursor:         cursor,
+		WithTotal:      withTotal,
+		Status:         status,
 	}
 
 }
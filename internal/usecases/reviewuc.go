@@ -0,0 +1,427 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+type ReviewUseCase interface {
+	CreateReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ListProductReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UpdateReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ReplyReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	VoteReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UnvoteReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// review hanya bisa dibuat buyer untuk transaksi yang sudah "diterima", satu review per transaksi.
+// POST /v1/transaction/{id}/review
+func CreateReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	transactionIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !helper.ValidateUUID(transactionIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Transaction didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	tx, err := s.GetTransaction(transactionIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Transaction didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if tx.Buyer.ID != userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	if tx.Transaction.Status != "diterima" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Only received transaction can be reviewed"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	review, appErr := readReviewPayload(r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	review.TransactionId = tx.Transaction.ID
+	review.ProductId = tx.Product.ID
+	review.VariantId = tx.Transaction.VariantId
+	review.BuyerId = tx.Buyer.ID
+	review.SellerId = tx.Seller.ID
+
+	id := uuid.NewString()
+
+	if err := s.CreateReview(id, review); err != nil {
+
+		log.Println("error when creating review", err)
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Transaction already reviewed"),
+				Status: http.StatusConflict,
+			}
+		}
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating review, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	newReview, err := s.GetReview(id)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating review, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Review created successfully",
+		Data: map[string]interface{}{
+			"review": newReview,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+// GET /v1/product/{id}/review?order=recent|helpful|rating&rating=1-5&limit=&offset=
+func ListProductReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+
+	if _, err := getViewableProduct(s, r, productIdUrlPath); err.Error != nil {
+		return err
+	}
+
+	queryParams := r.URL.Query()
+	q := validator.ValidateListReviewQuery(types.ListQueryReview{
+		Limit:  queryParams.Get("limit"),
+		Offset: queryParams.Get("offset"),
+		Order:  queryParams.Get("order"),
+		Rating: queryParams.Get("rating"),
+	})
+
+	reviews, err := s.ListProductReviews(productIdUrlPath, q)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching reviews"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"reviews": reviews,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// buyer bisa ubah rating dan isi review miliknya, PATCH /v1/review/{id}
+func UpdateReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	reviewIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	review, appErr := getReview(s, reviewIdUrlPath)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if review.BuyerId != userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	payload, appErr := readReviewPayload(r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if err := s.UpdateReview(review.ID, payload.Rating, payload.Body); err != nil {
+
+		log.Println("error when updating review", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating review, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeReview(s, w, review.ID, "Review updated successfully")
+}
+
+// seller membalas review product miliknya, PUT /v1/review/{id}/reply
+func ReplyReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type replyStruct struct {
+		Reply string `json:"reply"`
+	}
+
+	vars := mux.Vars(r)
+	reviewIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	review, appErr := getReview(s, reviewIdUrlPath)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if review.SellerId != userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in ReplyReview")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload *replyStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil || payload == nil {
+
+		log.Println("error when Unmarshal body in reply review usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := validator.ValidateReviewReplyPayload(payload.Reply); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.ReplyReview(review.ID, payload.Reply); err != nil {
+
+		log.Println("error when replying review", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when replying review, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeReview(s, w, review.ID, "Review replied successfully")
+}
+
+// tandai review sebagai membantu, POST /v1/review/{id}/vote
+func VoteReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	reviewIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	review, appErr := getReview(s, reviewIdUrlPath)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if review.BuyerId == userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Cannot vote your own review"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.VoteReview(review.ID, userId); err != nil {
+
+		log.Println("error when voting review", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when voting review, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeReview(s, w, review.ID, "Review voted successfully")
+}
+
+// batalkan vote, DELETE /v1/review/{id}/vote
+func UnvoteReview(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	reviewIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	review, appErr := getReview(s, reviewIdUrlPath)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if err := s.UnvoteReview(review.ID, userId); err != nil {
+
+		log.Println("error when removing review vote", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when removing vote, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeReview(s, w, review.ID, "Review vote removed successfully")
+}
+
+func getReview(s datastore.Store, reviewId string) (*entities.Review, types.AppError) {
+
+	if !helper.ValidateUUID(reviewId) {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Review didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	review, err := s.GetReview(reviewId)
+	if err != nil {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Review didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	return review, types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// ambil ulang review setelah ada perubahan lalu kirim sebagai response
+func writeReview(s datastore.Store, w http.ResponseWriter, reviewId, message string) types.AppError {
+
+	review, err := s.GetReview(reviewId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching review"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: message,
+		Data: map[string]interface{}{
+			"review": review,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func readReviewPayload(r *http.Request) (*entities.Review, types.AppError) {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in review usecase")
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var review *entities.Review
+
+	err = json.Unmarshal(body, &review)
+	if err != nil || review == nil {
+
+		log.Println("error when Unmarshal body in review usecase", err)
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := validator.ValidateReviewPayload(review); err != nil {
+
+		return nil, types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	return review, types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
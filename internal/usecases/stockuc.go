@@ -0,0 +1,269 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/gorilla/mux"
+)
+
+type StockUseCase interface {
+	ListStockMovements(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UpdateLowStockThreshold(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	SubscribeStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UnsubscribeStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// ledger stock product, hanya untuk seller pemilik product
+// GET /v1/product/{id}/stock/movements?type=&limit=&offset=
+func ListStockMovements(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	queryParams := r.URL.Query()
+	movementType, limit, offset := validator.ValidateListStockMovementQuery(queryParams.Get("type"), queryParams.Get("limit"), queryParams.Get("offset"))
+
+	movements, total, err := s.ListStockMovements(productIdUrlPath, movementType, limit, offset)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching stock movements"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"movements": movements,
+			"pagination": types.OffsetPageInfo{
+				Limit:  limit,
+				Offset: offset,
+				Total:  total,
+			},
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// seller dikabari saat stock turun di bawah threshold setelah penjualan, 0 = tidak aktif.
+// PUT /v1/product/{id}/stock/threshold
+func UpdateLowStockThreshold(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type thresholdStruct struct {
+		Threshold *int `json:"threshold"`
+	}
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in UpdateLowStockThreshold usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload thresholdStruct
+
+	err = json.Unmarshal(body, &payload)
+	if err != nil || payload.Threshold == nil {
+
+		log.Println("error when Unmarshal body in update low stock threshold usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := validator.ValidateLowStockThreshold(*payload.Threshold); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.SetLowStockThreshold(productIdUrlPath, *payload.Threshold); err != nil {
+
+		log.Println("error when updating low stock threshold", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating low stock threshold, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Low stock threshold updated successfully",
+		Data: map[string]interface{}{
+			"threshold": *payload.Threshold,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// buyer minta dikabari saat product yang stock nya habis tersedia lagi.
+// POST /v1/product/{id}/stock/subscribe
+func SubscribeStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !helper.ValidateUUID(productIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	product, err := s.GetProductById(productIdUrlPath)
+	if err != nil || !canViewProduct(product, userId) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if product.SellerId == userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Cannot subscribe to your own product"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if product.Stock > 0 {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product is still in stock"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.SubscribeStock(product.ID, userId); err != nil {
+
+		log.Println("error when subscribing stock", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when subscribing, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "You will be notified when this product is back in stock",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// DELETE /v1/product/{id}/stock/subscribe
+func UnsubscribeStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if !helper.ValidateUUID(productIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Product didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if err := s.UnsubscribeStock(productIdUrlPath, userId); err != nil {
+
+		log.Println("error when unsubscribing stock", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when unsubscribing, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Unsubscribed successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// kabari subscriber kalau stock naik dari habis jadi tersedia, error hanya di log
+// karena perubahan stock nya sudah tersimpan
+func notifyBackInStock(s datastore.Store, productId string, previousStock int) {
+
+	if previousStock > 0 {
+		return
+	}
+
+	if _, err := s.NotifyBackInStock(productId); err != nil {
+		log.Println("error when sending back in stock notification", err)
+	}
+}
+
+func hasVariant(variants []entities.ProductVariant, variantId string) bool {
+
+	for _, variant := range variants {
+		if variant.ID == variantId {
+			return true
+		}
+	}
+
+	return false
+}
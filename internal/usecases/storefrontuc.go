@@ -0,0 +1,354 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+type StorefrontUseCase interface {
+	CreateStorefront(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	GetMyStorefront(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UpdateStorefront(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	GetStorefront(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ListStorefrontProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// satu seller hanya punya satu storefront, slug dibuat dari nama kalau kosong. POST /v1/storefront
+func CreateStorefront(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkVerifiedSeller(s, userId); err.Error != nil {
+		return err
+	}
+
+	if _, err := s.GetStorefrontBySeller(userId); err == nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Storefront already exists"),
+			Status: http.StatusConflict,
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in CreateStorefront usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var storefront entities.Storefront
+
+	err = json.Unmarshal(body, &storefront)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in create storefront usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	storefront.SellerId = userId
+	trimStorefront(&storefront)
+
+	if storefront.Slug == "" {
+		storefront.Slug = slugify(storefront.Name)
+	}
+
+	if err := validator.ValidateStorefrontPayload(&storefront); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	id := uuid.NewString()
+
+	if err := s.CreateStorefront(id, &storefront); err != nil {
+
+		log.Println("error when creating storefront", err)
+
+		return storefrontWriteError(err)
+	}
+
+	newStorefront, err := s.GetStorefrontBySeller(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching storefront"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Storefront created successfully",
+		Data: map[string]interface{}{
+			"storefront": newStorefront,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+// storefront milik user. GET /v1/storefront
+func GetMyStorefront(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	storefront, err := s.GetStorefrontBySeller(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Storefront didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"storefront": storefront,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// field yang tidak dikirim tetap seperti sebelumnya, vacationMode true mematikan isPurchaseable
+// semua product seller dan false mengembalikannya. PATCH /v1/storefront
+func UpdateStorefront(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	storefront, err := s.GetStorefrontBySeller(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Storefront didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in UpdateStorefront usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	id := storefront.ID
+
+	err = json.Unmarshal(body, storefront)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in update storefront usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	storefront.ID = id
+	storefront.SellerId = userId
+	trimStorefront(storefront)
+
+	if err := validator.ValidateStorefrontPayload(storefront); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.UpdateStorefront(storefront.ID, storefront); err != nil {
+
+		log.Println("error when updating storefront", err)
+
+		return storefrontWriteError(err)
+	}
+
+	updatedStorefront, err := s.GetStorefrontBySeller(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching storefront"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Storefront updated successfully",
+		Data: map[string]interface{}{
+			"storefront": updatedStorefront,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// halaman publik storefront beserta jumlah product dan rating. GET /v1/storefront/{slug}
+func GetStorefront(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	storefront, appErr := getStorefrontFromPath(s, r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	stats, err := s.GetStorefrontStats(storefront.SellerId)
+	if err != nil {
+
+		log.Println("error when getting storefront stats", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching storefront"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"storefront": storefront,
+			"stats":      stats,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// list product storefront, query sama dengan GET /v1/product. GET /v1/storefront/{slug}/product
+func ListStorefrontProduct(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	storefront, appErr := getStorefrontFromPath(s, r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	query := r.URL.Query()
+	query.Set("store", storefront.Slug)
+	query.Del("seller")
+	query.Del("useronly")
+	r.URL.RawQuery = query.Encode()
+
+	return ListProduct(s, w, r)
+}
+
+func getStorefrontFromPath(s datastore.Store, r *http.Request) (*entities.Storefront, types.AppError) {
+
+	vars := mux.Vars(r)
+	slugUrlPath := strings.ToLower(vars["slug"])
+
+	if !validator.ValidateSlug(slugUrlPath) {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Storefront didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	storefront, err := s.GetStorefrontBySlug(slugUrlPath)
+	if err != nil {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Storefront didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	return storefront, types.AppError{}
+}
+
+func storefrontWriteError(err error) types.AppError {
+
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Storefront slug already used"),
+			Status: http.StatusConflict,
+		}
+	}
+
+	return types.AppError{
+		Error:  fmt.Errorf("Failed when saving storefront, please try again."),
+		Status: http.StatusInternalServerError,
+	}
+}
+
+func trimStorefront(f *entities.Storefront) {
+
+	f.Name = strings.TrimSpace(f.Name)
+	f.Slug = strings.ToLower(strings.TrimSpace(f.Slug))
+	f.Description = strings.TrimSpace(f.Description)
+	f.LogoUrl = strings.TrimSpace(f.LogoUrl)
+	f.BannerUrl = strings.TrimSpace(f.BannerUrl)
+	f.Location = strings.TrimSpace(f.Location)
+	f.VacationMessage = strings.TrimSpace(f.VacationMessage)
+
+	if f.OpeningHours == nil {
+		f.OpeningHours = map[string]string{}
+	}
+}
+
+// "Toko Budi Jaya!" -> "toko-budi-jaya"
+func slugify(name string) string {
+
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(slug) > validator.MAXSLUG {
+		slug = strings.TrimRight(slug[:validator.MAXSLUG], "-")
+	}
+
+	return slug
+}
@@ -6,7 +6,9 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/GetterSethya/golangApiMarketplace/config"
 	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
 	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
 	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
@@ -62,11 +64,33 @@ func CreateTransaction(s datastore.Store, w http.ResponseWriter, r *http.Request
 		}
 	}
 
+	newTransaction, appErr := placeOrder(s, buyerId, transaction)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	resp := types.ServerResponse{
+		Message: "Transaction created susscessfully",
+		Data:    newTransaction,
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+// buat transaksi untuk satu product (dan variant), cek stock lalu kurangi stock.
+// dipakai oleh CreateTransaction dan checkout wishlist
+func placeOrder(s datastore.Store, buyerId string, transaction *entities.Transaction) (*datastore.TransactionReturn, types.AppError) {
+
 	product, err := s.GetProductById(transaction.ProductId)
 	if err != nil {
 		log.Println("error when creating transaction", err)
 
-		return types.AppError{
+		return nil, types.AppError{
 			Error:  fmt.Errorf("Failed when creating transaction, product didnot exist"),
 			Status: http.StatusBadRequest,
 		}
@@ -74,20 +98,164 @@ func CreateTransaction(s datastore.Store, w http.ResponseWriter, r *http.Request
 
 	if buyerId == product.SellerId {
 
-		return types.AppError{
+		return nil, types.AppError{
 			Error:  fmt.Errorf("Cannot buy your own product"),
 			Status: http.StatusBadRequest,
 		}
 	}
 
-	total := product.Price * float64(transaction.Quantity)
+	if !product.IsPurchaseable || product.Status != "published" {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Product is not purchaseable"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	variants, err := s.ListProductVariants(product.ID)
+	if err != nil {
+		log.Println("error when getting product variants in create transaction", err)
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Failed when creating transaction, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	// product yang punya variant wajib pilih variant, harga dan stock ikut variant
+	var variant *entities.ProductVariant
+	price := product.Price
+	stock := product.Stock
+
+	if len(*variants) > 0 {
+		for i := range *variants {
+			if (*variants)[i].ID == transaction.VariantId {
+				variant = &(*variants)[i]
+				break
+			}
+		}
+
+		if variant == nil {
+
+			return nil, types.AppError{
+				Error:  fmt.Errorf("Invalid transaction variantId"),
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		price = variant.EffectivePrice(product.Price)
+		stock = variant.Stock
+	} else if transaction.VariantId != "" {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid transaction variantId"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if stock < transaction.Quantity {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Insufficient stock"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	// diskon dan coupon hanya dari server, abaikan nilai yang dikirim client
+	transaction.DiscountId = ""
+	transaction.DiscountAmount = 0
+	transaction.CouponId = ""
+	transaction.CouponDiscount = 0
+	transaction.WarehouseId = ""
+
 	id := uuid.NewString()
 
+	discount, err := s.GetActiveDiscount(product.ID)
+	if err != nil {
+		log.Println("error when getting active discount in create transaction", err)
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Failed when creating transaction, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if discount != nil {
+		if appErr := claimDiscount(s, discount, buyerId, id, transaction.Quantity); appErr.Error != nil {
+			return nil, appErr
+		}
+
+		discountedPrice := discount.Apply(price)
+		transaction.DiscountId = discount.ID
+		transaction.DiscountAmount = (price - discountedPrice) * float64(transaction.Quantity)
+		price = discountedPrice
+	}
+
+	transaction.Subtotal = price * float64(transaction.Quantity)
+
+	if transaction.CouponCode != "" {
+		coupon, couponDiscount, appErr := checkCoupon(s, transaction.CouponCode, product, transaction.Subtotal, time.Now())
+		if appErr.Error == nil {
+			appErr = claimCoupon(s, coupon, buyerId, id, couponDiscount)
+		}
+
+		if appErr.Error != nil {
+			releaseOrderClaims(s, id, buyerId)
+			return nil, appErr
+		}
+
+		transaction.CouponId = coupon.ID
+		transaction.CouponDiscount = couponDiscount
+	}
+
+	total := transaction.Subtotal - transaction.CouponDiscount
+
+	// stock ditahan sampai transaksi diterima seller, dilepas lagi kalau kadaluarsa atau ditolak
+	reservation := entities.StockReservation{
+		TransactionId: id,
+		ProductId:     product.ID,
+		BuyerId:       buyerId,
+		Quantity:      transaction.Quantity,
+		ExpiresAt:     time.Now().Add(config.LoadConfig().App.StockReservationTTL),
+	}
+
+	if variant != nil {
+		reservation.VariantId = variant.ID
+	}
+
+	if err := reserveOrderStock(s, &reservation, transaction.Latitude, transaction.Longitude); err != nil {
+
+		releaseOrderClaims(s, id, buyerId)
+
+		if err == datastore.ErrInsufficientStock {
+
+			return nil, types.AppError{
+				Error:  err,
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		log.Println("error when reserving stock in create transaction", err)
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Failed when creating transaction, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if err := s.NotifyLowStock(product.ID, transaction.Quantity); err != nil {
+		log.Println("error when sending low stock notification", err)
+	}
+
+	transaction.WarehouseId = reservation.WarehouseId
+
 	if err := s.CreateTransaction(id, buyerId, product.SellerId, product.ID, total, transaction); err != nil {
 
 		log.Println("error when creating transaction", err)
 
-		return types.AppError{
+		releaseOrderClaims(s, id, buyerId)
+
+		return nil, types.AppError{
 			Error:  fmt.Errorf("Failed when creating transaction, please try again."),
 			Status: http.StatusInternalServerError,
 		}
@@ -98,30 +266,63 @@ func CreateTransaction(s datastore.Store, w http.ResponseWriter, r *http.Request
 
 		log.Println("error when getting transaction", err)
 
-		return types.AppError{
+		return nil, types.AppError{
 			Error:  fmt.Errorf("Failed when creating transaction, please try again."),
 			Status: http.StatusInternalServerError,
 		}
 	}
 
-	if err := s.UpdateStockProduct(product.ID, product.Stock-1); err != nil {
-		log.Println("error when updating product stock in create transaction", err)
+	return newTransaction, types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+// ambil kuota diskon, kuota flash sale yang habis atau batas per buyer yang terlewati ditolak
+func claimDiscount(s datastore.Store, discount *entities.Discount, buyerId, transactionId string, quantity int) types.AppError {
+
+	err := s.ClaimDiscount(discount.ID, buyerId, transactionId, quantity)
+
+	switch {
+	case err == datastore.ErrDiscountUnavailable, err == datastore.ErrDiscountSoldOut:
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusConflict,
+		}
+	case err == datastore.ErrDiscountBuyerLimit:
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	case err != nil:
+		log.Println("error when claiming discount", err)
+
 		return types.AppError{
 			Error:  fmt.Errorf("Failed when creating transaction, please try again."),
 			Status: http.StatusInternalServerError,
 		}
+	default:
+		return types.AppError{
+			Error:  nil,
+			Status: http.StatusOK,
+		}
 	}
+}
 
-	resp := types.ServerResponse{
-		Message: "Transaction created susscessfully",
-		Data:    newTransaction,
+// kembalikan kuota diskon, pemakaian coupon dan stock yang ditahan kalau transaksi batal.
+// release yang tidak punya claim untuk transaksi ini tidak melakukan apa-apa
+func releaseOrderClaims(s datastore.Store, transactionId, actorId string) {
+
+	if err := s.ReleaseDiscountClaim(transactionId); err != nil {
+		log.Println("error when releasing discount claim", err)
 	}
 
-	helper.WriteJson(w, http.StatusCreated, resp)
+	if err := s.ReleaseCouponClaim(transactionId); err != nil {
+		log.Println("error when releasing coupon claim", err)
+	}
 
-	return types.AppError{
-		Error:  nil,
-		Status: http.StatusCreated,
+	if err := s.ReleaseReservation(transactionId, actorId, "transaction cancelled"); err != nil {
+		log.Println("error when releasing stock reservation", err)
 	}
 }
 
@@ -178,9 +379,18 @@ func GetTransaction(s datastore.Store, w http.ResponseWriter, r *http.Request) t
 func ListTransaction(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
 	queries := getListTransactionQuery(r)
 	userId := auth.GetUserIdFromJWT(r)
+
+	// cursor rusak/diubah client ditolak, cursor dari sort lain tetap dianggap halaman pertama
+	if queries.Cursor != "" && helper.DecodeCursor(queries.Cursor) == nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid cursor"),
+			Status: http.StatusBadRequest,
+		}
+	}
 	validQuery := validator.ValidateListTransactionQuery(queries)
 
-	transactions, err := s.ListTransaction(validQuery, userId)
+	transactions, pageInfo, err := s.ListTransaction(validQuery, userId)
 	if err != nil {
 
 		return types.AppError{
@@ -193,6 +403,7 @@ func ListTransaction(s datastore.Store, w http.ResponseWriter, r *http.Request)
 		Message: "Ok",
 		Data: map[string]interface{}{
 			"transactions": transactions,
+			"pagination":   pageInfo,
 		},
 	}
 
@@ -263,7 +474,7 @@ func UpdateStatusTransaction(s datastore.Store, w http.ResponseWriter, r *http.R
 		}
 	}
 
-	if tx.Buyer.ID != userId || tx.Seller.ID != userId {
+	if tx.Buyer.ID != userId && tx.Seller.ID != userId {
 
 		return types.AppError{
 			Error:  fmt.Errorf("Forbidden"),
@@ -271,21 +482,40 @@ func UpdateStatusTransaction(s datastore.Store, w http.ResponseWriter, r *http.R
 		}
 	}
 
-	if tx.Buyer.ID == userId && !(transaction.Status == "diterima") {
+	// status yang memang bukan hak role user -> forbidden, status asal yang salah -> bad request
+	from, ok := transactionTransitionFrom(tx, userId, transaction.Status)
+	if !ok {
 		return types.AppError{
 			Error:  fmt.Errorf("Forbidden"),
 			Status: http.StatusForbidden,
 		}
 	}
 
-	if tx.Seller.ID == userId && !(transaction.Status == "diterima seller" || transaction.Status == "dalam pengiriman") {
+	if from != tx.Transaction.Status {
 		return types.AppError{
-			Error:  fmt.Errorf("Forbidden"),
-			Status: http.StatusForbidden,
+			Error:  fmt.Errorf("Cannot change transaction status from %s to %s", tx.Transaction.Status, transaction.Status),
+			Status: http.StatusBadRequest,
 		}
 	}
 
-	if err := s.UpdateStatusTransaction(tx.Transaction.ID, transaction.Status); err != nil {
+	// diterima seller sekaligus commit reservation stock dalam satu transaksi db
+	if transaction.Status == "diterima seller" {
+		err = s.AcceptTransaction(tx.Transaction.ID, tx.Transaction.Status, userId)
+	} else {
+		err = s.UpdateStatusTransaction(tx.Transaction.ID, tx.Transaction.Status, transaction.Status)
+	}
+
+	if err == datastore.ErrTransactionStatusChanged || err == datastore.ErrReservationReleased {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusConflict,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when updating transaction status", err)
 
 		return types.AppError{
 			Error:  fmt.Errorf("Failed updating transaction, something went wrong"),
@@ -293,6 +523,10 @@ func UpdateStatusTransaction(s datastore.Store, w http.ResponseWriter, r *http.R
 		}
 	}
 
+	if transaction.Status == "ditolak" {
+		releaseOrderClaims(s, tx.Transaction.ID, userId)
+	}
+
 	resp := types.ServerResponse{
 		Message: "Ok",
 		Data:    nil,
@@ -306,6 +540,33 @@ func UpdateStatusTransaction(s datastore.Store, w http.ResponseWriter, r *http.R
 	}
 }
 
+// transisi status yang boleh per role, status tujuan -> status asal.
+// "ditolak" dan "diterima" adalah status akhir, tidak bisa diubah lagi
+var (
+	sellerTransactionTransitions = map[string]string{
+		"diterima seller":  "menunggu",
+		"ditolak":          "menunggu",
+		"dalam pengiriman": "diterima seller",
+	}
+	buyerTransactionTransitions = map[string]string{
+		"diterima": "dalam pengiriman",
+	}
+)
+
+// return status asal yang dibutuhkan untuk mengubah transaksi ke status, false kalau
+// user (buyer/seller transaksi) tidak boleh mengubah ke status tersebut sama sekali
+func transactionTransitionFrom(tx *datastore.TransactionReturn, userId, status string) (string, bool) {
+
+	transitions := buyerTransactionTransitions
+	if tx.Seller.ID == userId {
+		transitions = sellerTransactionTransitions
+	}
+
+	from, ok := transitions[status]
+
+	return from, ok
+}
+
 func getListTransactionQuery(r *http.Request) types.ListQueryTransaction {
 
 	queryParams := r.URL.Query()
@@ -313,25 +574,54 @@ func getListTransactionQuery(r *http.Request) types.ListQueryTransaction {
 	// return transaction list as seller
 	seller := queryParams.Get("seller")
 
-	// pagination
+	// pagination, cursor dari pagination.next/prev response sebelumnya (offset diabaikan kalau ada cursor)
 	limit := queryParams.Get("limit")
 	offset := queryParams.Get("offset")
+	cursor := queryParams.Get("cursor")
 
-	// sort product by "asc"|"desc"
+	// return total transaction "true"|"false"
+	withTotal := queryParams.Get("withtotal")
+
+	// sort transaction by "asc"|"desc"
 	sort := queryParams.Get("sort")
 
-	// order product "price"|"date"|"name" default date
+	// order transaction "date"|"total" default date
 	order := queryParams.Get("order")
 
 	// get product where id == productId
 	search := queryParams.Get("search")
 
 	return types.ListQueryTransaction{
-		Seller: seller,
-		Limit:  limit,
-		Offset: offset,
-		Sort:   sort,
-		Order:  order,
-		Search: search,
+		Seller:    seller,
+		Limit:     limit,
+		Offset:    offset,
+		Sort:      sort,
+		Order:     order,
+		Search:    search,
+		Cursor:    cursor,
+		WithTotal: withTotal,
+	}
+}
+
+// reservation diambil dari warehouse terdekat/prioritas yang stock nya cukup, kalau tidak ada
+// dari stock yang belum dialokasikan. pilih ulang kalau stock warehouse keburu habis
+func reserveOrderStock(s datastore.Store, reservation *entities.StockReservation, latitude, longitude *float64) error {
+
+	const maxAttempts = 3
+
+	for attempt := 1; ; attempt++ {
+		warehouseId, err := s.ChooseFulfilmentWarehouse(reservation.ProductId, reservation.VariantId, reservation.Quantity, latitude, longitude)
+		if err != nil {
+			return err
+		}
+
+		reservation.WarehouseId = warehouseId
+
+		err = s.ReserveStock(reservation)
+		if err == datastore.ErrInsufficientStock && warehouseId != "" && attempt < maxAttempts {
+			continue
+		}
+
+		return err
 	}
 }
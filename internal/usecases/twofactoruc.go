@@ -0,0 +1,548 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/config"
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+)
+
+type TwoFactorUseCase interface {
+	GetTwoFactorStatus(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	SetupTwoFactor(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	EnableTwoFactor(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	DisableTwoFactor(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	RegenerateRecoveryCodes(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	LoginTwoFactor(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// kode dari authenticator app atau salah satu recovery code
+type secondFactorStruct struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+// GET /v1/user/2fa/status
+func GetTwoFactorStatus(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	status := entities.TwoFactorStatus{
+		Enabled: user.TotpEnabledAt.Valid,
+	}
+
+	if user.TotpEnabledAt.Valid {
+		status.EnabledAt = &user.TotpEnabledAt.Time
+
+		status.RecoveryCodesLeft, err = s.CountRecoveryCodes(userId)
+		if err != nil {
+
+			log.Println("error when counting recovery codes", err)
+
+			return types.AppError{
+				Error:  fmt.Errorf("Something went wrong. Please try again"),
+				Status: http.StatusInternalServerError,
+			}
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"twoFactor": status,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// langkah 1 enrol: secret baru + uri otpauth:// untuk QR code. 2fa belum aktif sampai
+// dikonfirmasi lewat POST /v1/user/2fa/enable. POST /v1/user/2fa/setup
+func SetupTwoFactor(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if user.TotpEnabledAt.Valid {
+
+		return types.AppError{
+			Error:  datastore.ErrTOTPAlreadyEnabled,
+			Status: http.StatusConflict,
+		}
+	}
+
+	secret := auth.GenerateTOTPSecret()
+
+	err = s.SetTOTPSecret(userId, secret)
+	if err == datastore.ErrTOTPAlreadyEnabled {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusConflict,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when setting totp secret", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when setting up two-factor authentication, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Scan the QR code with your authenticator app, then confirm with a code",
+		Data: map[string]interface{}{
+			"secret":          secret,
+			"provisioningUri": auth.TOTPProvisioningURI(secret, user.Username),
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// langkah 2 enrol: kode pertama dari authenticator mengaktifkan 2fa, recovery code
+// hanya ditampilkan sekali di response ini. POST /v1/user/2fa/enable
+func EnableTwoFactor(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	var payload secondFactorStruct
+	if err := readTwoFactorBody(r, &payload); err.Error != nil {
+		return err
+	}
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if user.TotpEnabledAt.Valid {
+
+		return types.AppError{
+			Error:  datastore.ErrTOTPAlreadyEnabled,
+			Status: http.StatusConflict,
+		}
+	}
+
+	if user.TotpSecret == "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Two-factor setup not started"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	step, ok := auth.ValidateTOTP(user.TotpSecret, strings.TrimSpace(payload.Code), time.Now())
+	if !ok {
+
+		return types.AppError{
+			Error:  datastore.ErrTOTPInvalid,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	codes, hashes := generateRecoveryCodes()
+
+	err = s.EnableTOTP(userId, step, hashes)
+	if err == datastore.ErrTOTPAlreadyEnabled {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusConflict,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when enabling totp", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when enabling two-factor authentication, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Two-factor authentication enabled, store your recovery codes somewhere safe",
+		Data: map[string]interface{}{
+			"recoveryCodes": codes,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// butuh password dan kode 2fa. seller yang masih punya rekening payout tidak bisa mematikan 2fa.
+// POST /v1/user/2fa/disable
+func DisableTwoFactor(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type disableStruct struct {
+		Password string `json:"password"`
+		secondFactorStruct
+	}
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	var payload disableStruct
+	if err := readTwoFactorBody(r, &payload); err.Error != nil {
+		return err
+	}
+
+	user, err := s.GetUserById(userId)
+	if err != nil || !helper.CompareHash(user.HashPassword, payload.Password) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid password"),
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	bankAccounts, err := s.ListBankAccount(userId)
+	if err != nil {
+
+		log.Println("error when listing bank account in DisableTwoFactor", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong. Please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if bankAccounts != nil && len(*bankAccounts) > 0 {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Remove your payout bank accounts before disabling two-factor authentication"),
+			Status: http.StatusConflict,
+		}
+	}
+
+	if err := verifySecondFactor(s, user, payload.secondFactorStruct); err.Error != nil {
+		return err
+	}
+
+	if err := s.DisableTOTP(userId); err != nil {
+
+		log.Println("error when disabling totp", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when disabling two-factor authentication, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Two-factor authentication disabled",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// recovery code lama tidak berlaku lagi. POST /v1/user/2fa/recovery-codes
+func RegenerateRecoveryCodes(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	var payload secondFactorStruct
+	if err := readTwoFactorBody(r, &payload); err.Error != nil {
+		return err
+	}
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if err := verifySecondFactor(s, user, payload); err.Error != nil {
+		return err
+	}
+
+	codes, hashes := generateRecoveryCodes()
+
+	if err := s.ReplaceRecoveryCodes(userId, hashes); err != nil {
+
+		log.Println("error when replacing recovery codes", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when generating recovery codes, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Recovery codes regenerated, store them somewhere safe",
+		Data: map[string]interface{}{
+			"recoveryCodes": codes,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// langkah kedua login untuk user dengan 2fa, challengeToken didapat dari POST /v1/user/login.
+// POST /v1/user/login/2fa
+func LoginTwoFactor(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type loginTwoFactorStruct struct {
+		ChallengeToken string `json:"challengeToken"`
+		secondFactorStruct
+	}
+
+	var payload loginTwoFactorStruct
+	if err := readTwoFactorBody(r, &payload); err.Error != nil {
+		return err
+	}
+
+	secret := config.LoadConfig().App.JWTSecret
+
+	userId := auth.ValidateChallengeJWT(payload.ChallengeToken, secret)
+	if userId == "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid or expired challenge token, please login again"),
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid or expired challenge token, please login again"),
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	if err := verifySecondFactor(s, user, payload.secondFactorStruct); err.Error != nil {
+		return err
+	}
+
+	jwtToken, err := auth.CreateJWT(user.ID, user.TokenVersion, secret)
+	if err != nil {
+		log.Println("Error when creating JWT in twofactoruc.go:", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Login succesfull",
+		Data: map[string]interface{}{
+			"username":    user.Username,
+			"name":        user.Name,
+			"accessToken": jwtToken,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// rekening payout (bank account) hanya untuk seller yang sudah mengaktifkan 2fa
+func checkTwoFactorSeller(s datastore.Store, userId string) types.AppError {
+
+	user, err := s.GetUserById(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if !user.TotpEnabledAt.Valid {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Enable two-factor authentication before managing payout accounts"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	return types.AppError{}
+}
+
+// cek kode TOTP (atau recovery code kalau code kosong). kode salah dihitung,
+// setelah datastore.MAXTOTPATTEMPTS kali verifikasi dikunci sementara
+func verifySecondFactor(s datastore.Store, user *entities.User, payload secondFactorStruct) types.AppError {
+
+	if !user.TotpEnabledAt.Valid {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Two-factor authentication is not enabled"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if user.TotpLockedUntil.Valid && time.Now().Before(user.TotpLockedUntil.Time) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Too many invalid two-factor codes, please try again later"),
+			Status: http.StatusTooManyRequests,
+		}
+	}
+
+	code := strings.TrimSpace(payload.Code)
+	recoveryCode := normalizeRecoveryCode(payload.RecoveryCode)
+
+	var err error
+	switch {
+	case code != "":
+		if step, ok := auth.ValidateTOTP(user.TotpSecret, code, time.Now()); ok {
+			err = s.UseTOTPStep(user.ID, step)
+		} else {
+			err = datastore.ErrTOTPInvalid
+		}
+	case recoveryCode != "":
+		err = s.UseRecoveryCode(user.ID, helper.HashToken(recoveryCode))
+	default:
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing code"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err == datastore.ErrTOTPInvalid {
+
+		if err := s.RecordTOTPFailure(user.ID); err != nil {
+			log.Println("error when recording totp failure", err)
+		}
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when verifying second factor", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong. Please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return types.AppError{}
+}
+
+func readTwoFactorBody(r *http.Request, payload interface{}) types.AppError {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in two factor usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	if err := json.Unmarshal(body, payload); err != nil {
+
+		log.Println("error when Unmarshal body in two factor usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	return types.AppError{}
+}
+
+// recovery code format "xxxxx-xxxxx" (hex), yang disimpan hanya hash versi normalnya
+func generateRecoveryCodes() ([]string, []string) {
+
+	codes := make([]string, 0, validator.RECOVERYCODECOUNT)
+	hashes := make([]string, 0, validator.RECOVERYCODECOUNT)
+
+	for i := 0; i < validator.RECOVERYCODECOUNT; i++ {
+		token := helper.GenerateRandomToken(5)
+
+		codes = append(codes, token[:5]+"-"+token[5:])
+		hashes = append(hashes, helper.HashToken(token))
+	}
+
+	return codes, hashes
+}
+
+// "ABCDE-12345 " -> "abcde12345"
+func normalizeRecoveryCode(code string) string {
+
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -1,6 +1,7 @@
 package usecases
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,11 +22,13 @@ import (
 
 type UserUseCase interface {
 	CreateUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
-	GetUserById(s datastore.Store, w http.ResponseWriter, r *http.Request) (*entities.User, types.AppError)
-	GetUserByUsername(s datastore.Store, w http.ResponseWriter, r *http.Request) (*entities.User, types.AppError)
+	GetUserById(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	GetUserByUsername(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	GetMe(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 	UpdateUser(s datastore.Store, w http.ResponseWriter, r *http.Request) (*entities.User, types.AppError)
 	DeleteUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 	AuthorizeUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	RestoreUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
 }
 
 func CreateUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
@@ -83,7 +86,8 @@ func CreateUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types
 	}
 
 	secret := config.LoadConfig().App.JWTSecret
-	accessToken, err := auth.CreateJWT(id, secret)
+	// user baru, tokenVersion masih 0
+	accessToken, err := auth.CreateJWT(id, 0, secret)
 	if err != nil {
 
 		log.Println("Error when creating JWT")
@@ -152,17 +156,24 @@ func AuthorizeUser(s datastore.Store, w http.ResponseWriter, r *http.Request) ty
 		}
 	}
 
+	// username tidak ada dan password salah dibedakan hanya di log
+	password := user.Password
 	user, err = s.GetUserByUsername(user.Username)
-	if err != nil {
-		log.Println("Error when in GetUserByUsername in useruc.go:", err)
+	if err != nil || !helper.CompareHash(user.HashPassword, password) {
+		log.Println("Error when authorizing user in useruc.go:", err)
 
 		return types.AppError{
-			Error:  fmt.Errorf(err.Error()),
-			Status: http.StatusNotFound,
+			Error:  fmt.Errorf("Invalid username/password"),
+			Status: http.StatusUnauthorized,
 		}
 	}
 
-	jwtToken, err := auth.CreateJWT(user.ID, config.LoadConfig().App.JWTSecret)
+	// user dengan 2fa belum dapat token login, hanya challenge token untuk POST /v1/user/login/2fa
+	if user.TotpEnabledAt.Valid {
+		return writeTwoFactorChallenge(w, user)
+	}
+
+	jwtToken, err := auth.CreateJWT(user.ID, user.TokenVersion, config.LoadConfig().App.JWTSecret)
 	if err != nil {
 		log.Println("Error when creating JWT in useruc.go:", err)
 
@@ -189,23 +200,115 @@ func AuthorizeUser(s datastore.Store, w http.ResponseWriter, r *http.Request) ty
 	}
 }
 
-func GetUserById(s datastore.Store, w http.ResponseWriter, r *http.Request) (*entities.User, types.AppError) {
-	//TODO
-	// fungsi s.GetUserById(id) udah ada, tinggal ambil id dari path -> validasi -> GetUserById() -> return json
+// profil publik user. GET /v1/user/{id}
+func GetUserById(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
 
-	return &entities.User{}, types.AppError{
-		Error:  nil,
-		Status: 200,
+	vars := mux.Vars(r)
+	userIdUrlPath := vars["id"]
+
+	if !helper.ValidateUUID(userIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	user, err := s.GetUserById(userIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	return writeUserProfile(s, w, user, false)
+}
+
+// profil publik user. GET /v1/user/username/{username}
+func GetUserByUsername(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	usernameUrlPath := vars["username"]
+
+	if len(usernameUrlPath) < validator.MINUSERNAME || len(usernameUrlPath) > validator.MAXUSERNAME {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	user, err := s.GetUserByUsername(usernameUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	return writeUserProfile(s, w, user, false)
+}
+
+// profil user yang sedang login beserta kontaknya. GET /v1/user/me
+func GetMe(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	user, err := s.GetUserById(auth.GetUserIdFromJWT(r))
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User didnot exist"),
+			Status: http.StatusNotFound,
+		}
 	}
+
+	return writeUserProfile(s, w, user, true)
 }
 
-func GetUserByUsername(s datastore.Store, w http.ResponseWriter, r *http.Request) (*entities.User, types.AppError) {
-	//TODO
-	// fungsi s.GetUserByUsername(username) udah ada, tinggal ambil username dari path -> validasi -> GetUserByUsername() -> return json
+// hanya UserMinimal yang dikirim, hash password tidak pernah ikut. kontak hanya untuk user itu sendiri
+func writeUserProfile(s datastore.Store, w http.ResponseWriter, user *entities.User, withContact bool) types.AppError {
+
+	stats, err := s.GetUserStats(user.ID)
+	if err != nil {
+
+		log.Println("error when getting user stats", err)
 
-	return &entities.User{}, types.AppError{
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong. Please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	data := map[string]interface{}{
+		"user":  toUserMinimal(user),
+		"stats": stats,
+	}
+
+	if withContact {
+		data["contact"] = toUserContact(user)
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data:    data,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
 		Error:  nil,
-		Status: 200,
+		Status: http.StatusOK,
+	}
+}
+
+func toUserMinimal(user *entities.User) entities.UserMinimal {
+
+	return entities.UserMinimal{
+		ID:       user.ID,
+		Name:     user.Name,
+		Username: user.Username,
 	}
 }
 
@@ -281,7 +384,7 @@ func UpdateUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types
 	resp := types.ServerResponse{
 		Message: "User edited successfully",
 		Data: map[string]interface{}{
-			"user": user,
+			"user": toUserMinimal(user),
 		},
 	}
 
@@ -327,3 +430,109 @@ func DeleteUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types
 		Status: 200,
 	}
 }
+
+// akun yang dihapus bisa dikembalikan selama grace period dengan username dan password,
+// product dan bank account yang ikut terhapus bersama akun juga dikembalikan. POST /v1/user/restore
+func RestoreUser(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		log.Println("Error when reading body")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid username/password"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var payload entities.User
+	if err := json.Unmarshal(body, &payload); err != nil {
+		log.Println("Error when unmarshaling body")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid username/password"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := validator.ValidateLoginPayload(&payload); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	user, err := s.GetDeletedUserByUsername(payload.Username)
+	if err != nil || !helper.CompareHash(user.HashPassword, payload.Password) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid username/password"),
+			Status: http.StatusUnauthorized,
+		}
+	}
+
+	err = s.RestoreUser(user.ID, config.LoadConfig().App.DeleteGracePeriod)
+	switch {
+	case err == sql.ErrNoRows:
+
+		return types.AppError{
+			Error:  fmt.Errorf("Account can no longer be restored"),
+			Status: http.StatusGone,
+		}
+	case err != nil:
+
+		log.Println("error when restoring user", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when restoring user, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "User restored successfully",
+		Data: map[string]interface{}{
+			"username": user.Username,
+			"name":     user.Name,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func writeTwoFactorChallenge(w http.ResponseWriter, user *entities.User) types.AppError {
+
+	challengeToken, err := auth.CreateChallengeJWT(user.ID, config.LoadConfig().App.JWTSecret)
+	if err != nil {
+		log.Println("Error when creating challenge JWT in useruc.go:", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Something went wrong, please try again"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Two-factor authentication required",
+		Data: map[string]interface{}{
+			"twoFactorRequired": true,
+			"challengeToken":    challengeToken,
+			"expiresIn":         int(auth.CHALLENGETTL.Seconds()),
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
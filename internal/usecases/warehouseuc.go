@@ -0,0 +1,474 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type WarehouseUseCase interface {
+	CreateWarehouse(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ListWarehouse(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UpdateWarehouse(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	DeleteWarehouse(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ListWarehouseStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	TransferStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// POST /v1/warehouse
+func CreateWarehouse(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in CreateWarehouse usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	// warehouse baru default aktif
+	warehouse := entities.Warehouse{IsActive: true}
+
+	err = json.Unmarshal(body, &warehouse)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in create warehouse usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	warehouse.SellerId = userId
+	trimWarehouse(&warehouse)
+
+	if err := validator.ValidateWarehousePayload(&warehouse); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	id := uuid.NewString()
+
+	if err := s.CreateWarehouse(id, &warehouse); err != nil {
+
+		log.Println("error when creating warehouse", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating warehouse, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	newWarehouse, err := s.GetWarehouse(id)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching warehouse"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Warehouse created successfully",
+		Data: map[string]interface{}{
+			"warehouse": newWarehouse,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+// warehouse milik user. GET /v1/warehouse
+func ListWarehouse(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	warehouses, err := s.ListWarehouses(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching warehouses"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"warehouses": warehouses,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// field yang tidak dikirim tetap seperti sebelumnya. PATCH /v1/warehouse/{id}
+func UpdateWarehouse(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	warehouseIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkWarehouseOwner(s, warehouseIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	warehouse, err := s.GetWarehouse(warehouseIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Warehouse didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in UpdateWarehouse usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	err = json.Unmarshal(body, warehouse)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in update warehouse usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	warehouse.ID = warehouseIdUrlPath
+	warehouse.SellerId = userId
+	trimWarehouse(warehouse)
+
+	if err := validator.ValidateWarehousePayload(warehouse); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err := s.UpdateWarehouse(warehouse.ID, warehouse); err != nil {
+
+		log.Println("error when updating warehouse", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating warehouse, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	updatedWarehouse, err := s.GetWarehouse(warehouse.ID)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching warehouse"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Warehouse updated successfully",
+		Data: map[string]interface{}{
+			"warehouse": updatedWarehouse,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// warehouse harus kosong dulu. DELETE /v1/warehouse/{id}
+func DeleteWarehouse(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	warehouseIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkWarehouseOwner(s, warehouseIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	err := s.DeleteWarehouse(warehouseIdUrlPath)
+	if err == datastore.ErrWarehouseNotEmpty {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusConflict,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when deleting warehouse", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting warehouse, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Warehouse deleted successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// stock product per warehouse, sisa dari stock total adalah stock yang belum dialokasikan.
+// GET /v1/product/{id}/warehouse-stock
+func ListWarehouseStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	stocks, err := s.ListWarehouseStocks(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching warehouse stocks"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"stocks": stocks,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// pindah stock antar warehouse milik seller, warehouse kosong = stock yang belum dialokasikan.
+// POST /v1/product/{id}/stock/transfer
+func TransferStock(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	productIdUrlPath := vars["id"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	if err := checkProductOwner(s, productIdUrlPath, userId); err.Error != nil {
+		return err
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in TransferStock usecase")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var transfer entities.StockTransfer
+
+	err = json.Unmarshal(body, &transfer)
+	if err != nil {
+
+		log.Println("error when Unmarshal body in transfer stock usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	transfer.ProductId = productIdUrlPath
+	transfer.ActorId = userId
+	transfer.Reason = strings.TrimSpace(transfer.Reason)
+
+	if err := validator.ValidateStockTransferPayload(&transfer); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	variants, err := s.ListProductVariants(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when transferring stock, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if len(*variants) > 0 && !hasVariant(*variants, transfer.VariantId) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid transfer variantId"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if len(*variants) == 0 && transfer.VariantId != "" {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid transfer variantId"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	for _, warehouseId := range []string{transfer.FromWarehouseId, transfer.ToWarehouseId} {
+		if warehouseId == "" {
+			continue
+		}
+
+		if err := checkWarehouseOwner(s, warehouseId, userId); err.Error != nil {
+			return err
+		}
+	}
+
+	err = s.TransferStock(&transfer)
+	if err == datastore.ErrInsufficientStock {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if err != nil {
+
+		log.Println("error when transferring stock", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when transferring stock, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	stocks, err := s.ListWarehouseStocks(productIdUrlPath)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching warehouse stocks"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Stock transferred successfully",
+		Data: map[string]interface{}{
+			"stocks": stocks,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// warehouse harus ada dan milik user
+func checkWarehouseOwner(s datastore.Store, warehouseId, userId string) types.AppError {
+
+	if !helper.ValidateUUID(warehouseId) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Warehouse didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	warehouse, err := s.GetWarehouse(warehouseId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Warehouse didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if warehouse.SellerId != userId {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func trimWarehouse(w *entities.Warehouse) {
+
+	w.Name = strings.TrimSpace(w.Name)
+	w.Address = strings.TrimSpace(w.Address)
+	w.City = strings.TrimSpace(w.City)
+}
@@ -0,0 +1,693 @@
+package usecases
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/auth"
+	"github.com/GetterSethya/golangApiMarketplace/internal/datastore"
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+	"github.com/GetterSethya/golangApiMarketplace/internal/validator"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+)
+
+type WishlistUseCase interface {
+	ListWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	CreateWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	GetWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UpdateWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	DeleteWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	AddWishlistItem(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	RemoveWishlistItem(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	ShareWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	UnshareWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	GetSharedWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+	CheckoutWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError
+}
+
+// semua wishlist milik user, GET /v1/wishlist
+func ListWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlists, err := s.ListWishlists(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching wishlists"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Ok",
+		Data: map[string]interface{}{
+			"wishlists": wishlists,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+func CreateWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := readWishlistPayload(r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	wishlists, err := s.ListWishlists(userId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating wishlist, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	if len(*wishlists) >= validator.MAXWISHLISTS {
+
+		return types.AppError{
+			Error:  fmt.Errorf("User can only have %d wishlists", validator.MAXWISHLISTS),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	id := uuid.NewString()
+
+	if err := s.CreateWishlist(id, userId, wishlist.Name); err != nil {
+
+		log.Println("error when creating wishlist", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating wishlist, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	newWishlist, err := s.GetWishlist(id)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when creating wishlist, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Wishlist created successfully",
+		Data: map[string]interface{}{
+			"wishlist": newWishlist,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusCreated, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusCreated,
+	}
+}
+
+// detail wishlist beserta item, harga dan stock terkini, GET /v1/wishlist/{id}
+func GetWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := checkWishlistOwner(s, vars["id"], userId)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	return writeWishlist(s, w, wishlist, http.StatusOK, "Ok")
+}
+
+func UpdateWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := checkWishlistOwner(s, vars["id"], userId)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	payload, appErr := readWishlistPayload(r)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if err := s.UpdateWishlist(wishlist.ID, payload.Name); err != nil {
+
+		log.Println("error when updating wishlist", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when updating wishlist, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	wishlist.Name = payload.Name
+
+	return writeWishlist(s, w, wishlist, http.StatusOK, "Wishlist updated successfully")
+}
+
+func DeleteWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := checkWishlistOwner(s, vars["id"], userId)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if err := s.DeleteWishlist(wishlist.ID); err != nil {
+
+		log.Println("error when deleting wishlist", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when deleting wishlist, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Wishlist deleted successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// simpan product ke wishlist, harga dan stock saat ini ikut disimpan. POST /v1/wishlist/{id}/item
+func AddWishlistItem(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := checkWishlistOwner(s, vars["id"], userId)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if wishlist.ItemCount >= validator.MAXWISHLISTITEMS {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Wishlist can only have %d items", validator.MAXWISHLISTITEMS),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in AddWishlistItem")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var item *entities.WishlistItem
+
+	err = json.Unmarshal(body, &item)
+	if err != nil || item == nil {
+
+		log.Println("error when Unmarshal body in add wishlist item usecase", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+
+	if err := validator.ValidateWishlistItemPayload(item); err != nil {
+
+		return types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	product, err := s.GetProductById(item.ProductId)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid wishlist item productId"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	variants, err := s.ListProductVariants(product.ID)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when adding wishlist item, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	// sama seperti transaksi, product yang punya variant wajib pilih variant
+	item.PriceAtAdd = product.Price
+	item.StockAtAdd = product.Stock
+
+	if len(*variants) > 0 || item.VariantId != "" {
+		var variant *entities.ProductVariant
+		for i := range *variants {
+			if (*variants)[i].ID == item.VariantId {
+				variant = &(*variants)[i]
+				break
+			}
+		}
+
+		if variant == nil {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Invalid wishlist item variantId"),
+				Status: http.StatusBadRequest,
+			}
+		}
+
+		item.PriceAtAdd = variant.EffectivePrice(product.Price)
+		item.StockAtAdd = variant.Stock
+	}
+
+	if err := s.AddWishlistItem(uuid.NewString(), wishlist.ID, item); err != nil {
+
+		log.Println("error when adding wishlist item", err)
+
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Product already in wishlist"),
+				Status: http.StatusConflict,
+			}
+		}
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when adding wishlist item, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeWishlist(s, w, wishlist, http.StatusCreated, "Wishlist item added successfully")
+}
+
+// DELETE /v1/wishlist/{id}/item/{itemId}
+func RemoveWishlistItem(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	itemIdUrlPath := vars["itemId"]
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := checkWishlistOwner(s, vars["id"], userId)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if !helper.ValidateUUID(itemIdUrlPath) {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Wishlist item didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if err := s.DeleteWishlistItem(wishlist.ID, itemIdUrlPath); err != nil {
+
+		if err == sql.ErrNoRows {
+
+			return types.AppError{
+				Error:  fmt.Errorf("Wishlist item didnot exist"),
+				Status: http.StatusNotFound,
+			}
+		}
+
+		log.Println("error when removing wishlist item", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when removing wishlist item, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	return writeWishlist(s, w, wishlist, http.StatusOK, "Wishlist item removed successfully")
+}
+
+// bikin link share baru (link lama tidak berlaku lagi), POST /v1/wishlist/{id}/share
+func ShareWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := checkWishlistOwner(s, vars["id"], userId)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	token := helper.GenerateRandomToken(16)
+
+	if err := s.SetWishlistShareToken(wishlist.ID, token); err != nil {
+
+		log.Println("error when sharing wishlist", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when sharing wishlist, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Wishlist shared successfully",
+		Data: map[string]interface{}{
+			"shareToken": token,
+			"path":       "/v1/wishlist/shared/" + token,
+		},
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// DELETE /v1/wishlist/{id}/share
+func UnshareWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := checkWishlistOwner(s, vars["id"], userId)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	if err := s.SetWishlistShareToken(wishlist.ID, ""); err != nil {
+
+		log.Println("error when unsharing wishlist", err)
+
+		return types.AppError{
+			Error:  fmt.Errorf("Failed when unsharing wishlist, please try again."),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	resp := types.ServerResponse{
+		Message: "Wishlist unshared successfully",
+		Data:    nil,
+	}
+
+	helper.WriteJson(w, http.StatusOK, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// wishlist yang dibagikan, bisa dilihat tanpa login. GET /v1/wishlist/shared/{token}
+func GetSharedWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	vars := mux.Vars(r)
+	token := vars["token"]
+
+	if token == "" || len(token) > 64 {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Wishlist didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	wishlist, err := s.GetWishlistByShareToken(token)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Wishlist didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	wishlist.ShareToken = ""
+
+	return writeWishlist(s, w, wishlist, http.StatusOK, "Ok")
+}
+
+// pindahkan item wishlist jadi transaksi, item yang berhasil dihapus dari wishlist.
+// itemIds kosong = semua item. POST /v1/wishlist/{id}/checkout
+func CheckoutWishlist(s datastore.Store, w http.ResponseWriter, r *http.Request) types.AppError {
+
+	type checkoutStruct struct {
+		ItemIds []string `json:"itemIds"`
+	}
+
+	type failedItem struct {
+		ItemId string `json:"itemId"`
+		Error  string `json:"error"`
+	}
+
+	vars := mux.Vars(r)
+	userId := auth.GetUserIdFromJWT(r)
+
+	wishlist, appErr := checkWishlistOwner(s, vars["id"], userId)
+	if appErr.Error != nil {
+		return appErr
+	}
+
+	var payload checkoutStruct
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in CheckoutWishlist")
+
+		return types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	if len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &payload); err != nil {
+
+			log.Println("error when Unmarshal body in checkout wishlist usecase", err)
+
+			return types.AppError{
+				Error:  fmt.Errorf("Invalid/missing field"),
+				Status: http.StatusBadRequest,
+			}
+		}
+	}
+
+	items, err := s.ListWishlistItems(wishlist.ID)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching wishlist items"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	selected := map[string]bool{}
+	for _, itemId := range payload.ItemIds {
+		selected[itemId] = true
+	}
+
+	transactions := []datastore.TransactionReturn{}
+	failed := []failedItem{}
+
+	for _, item := range *items {
+		if len(selected) > 0 && !selected[item.ID] {
+			continue
+		}
+
+		delete(selected, item.ID)
+
+		transaction := entities.Transaction{
+			ProductId: item.ProductId,
+			VariantId: item.VariantId,
+			Quantity:  item.Quantity,
+		}
+
+		newTransaction, appErr := placeOrder(s, userId, &transaction)
+		if appErr.Error != nil {
+			failed = append(failed, failedItem{ItemId: item.ID, Error: appErr.Error.Error()})
+			continue
+		}
+
+		transactions = append(transactions, *newTransaction)
+
+		if err := s.DeleteWishlistItem(wishlist.ID, item.ID); err != nil {
+			log.Println("error when removing checked out wishlist item", err)
+		}
+	}
+
+	// itemIds yang tidak ada di wishlist
+	for itemId := range selected {
+		failed = append(failed, failedItem{ItemId: itemId, Error: "Wishlist item didnot exist"})
+	}
+
+	status := http.StatusCreated
+	message := "Wishlist checked out successfully"
+
+	if len(transactions) == 0 {
+		status = http.StatusBadRequest
+		message = "No wishlist item could be checked out"
+	}
+
+	resp := types.ServerResponse{
+		Message: message,
+		Data: map[string]interface{}{
+			"transactions": transactions,
+			"failed":       failed,
+		},
+	}
+
+	helper.WriteJson(w, status, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: status,
+	}
+}
+
+func checkWishlistOwner(s datastore.Store, wishlistId, userId string) (*entities.Wishlist, types.AppError) {
+
+	if !helper.ValidateUUID(wishlistId) {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Wishlist didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	wishlist, err := s.GetWishlist(wishlistId)
+	if err != nil {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Wishlist didnot exist"),
+			Status: http.StatusNotFound,
+		}
+	}
+
+	if wishlist.UserId != userId {
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Forbidden"),
+			Status: http.StatusForbidden,
+		}
+	}
+
+	return wishlist, types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
+
+// kirim wishlist beserta item terkini sebagai response
+func writeWishlist(s datastore.Store, w http.ResponseWriter, wishlist *entities.Wishlist, status int, message string) types.AppError {
+
+	items, err := s.ListWishlistItems(wishlist.ID)
+	if err != nil {
+
+		return types.AppError{
+			Error:  fmt.Errorf("Error when fetching wishlist items"),
+			Status: http.StatusInternalServerError,
+		}
+	}
+
+	wishlist.Items = *items
+	wishlist.ItemCount = len(*items)
+
+	resp := types.ServerResponse{
+		Message: message,
+		Data: map[string]interface{}{
+			"wishlist": wishlist,
+		},
+	}
+
+	helper.WriteJson(w, status, resp)
+
+	return types.AppError{
+		Error:  nil,
+		Status: status,
+	}
+}
+
+func readWishlistPayload(r *http.Request) (*entities.Wishlist, types.AppError) {
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+
+		log.Println("error when reading body in wishlist usecase")
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	defer r.Body.Close()
+
+	var wishlist *entities.Wishlist
+
+	err = json.Unmarshal(body, &wishlist)
+	if err != nil || wishlist == nil {
+
+		log.Println("error when Unmarshal body in wishlist usecase", err)
+
+		return nil, types.AppError{
+			Error:  fmt.Errorf("Invalid/missing field"),
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	wishlist.Name = strings.TrimSpace(wishlist.Name)
+
+	if err := validator.ValidateWishlistPayload(wishlist); err != nil {
+
+		return nil, types.AppError{
+			Error:  err,
+			Status: http.StatusBadRequest,
+		}
+	}
+
+	return wishlist, types.AppError{
+		Error:  nil,
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,52 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+)
+
+const (
+	MAXCATEGORYNAME = 100
+	MINCATEGORYNAME = 2
+	MAXSLUG         = 100
+)
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+func ValidateCategoryPayload(c *entities.Category) error {
+
+	var invalidFields []string
+	nameLength := len(c.Name)
+
+	if c.Name == "" || nameLength < MINCATEGORYNAME || nameLength > MAXCATEGORYNAME {
+		invalidFields = append(invalidFields, "category name")
+	}
+
+	if !ValidateSlug(c.Slug) {
+		invalidFields = append(invalidFields, "category slug")
+	}
+
+	if c.ParentId != "" && !helper.ValidateUUID(c.ParentId) {
+		invalidFields = append(invalidFields, "category parentId")
+	}
+
+	if c.Position < 0 {
+		invalidFields = append(invalidFields, "category position")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+// slug huruf kecil, angka dan strip, contoh: "pakaian-pria"
+func ValidateSlug(slug string) bool {
+
+	return len(slug) <= MAXSLUG && slugRegex.MatchString(slug)
+}
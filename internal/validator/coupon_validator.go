@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+)
+
+const (
+	MINCOUPONCODE         = 4
+	MAXCOUPONCODE         = 32
+	MAXCOUPONPERCENTAGE   = 100
+	MAXCOUPONELIGIBLEITEM = 100
+)
+
+var couponCodeRegex = regexp.MustCompile(`^[A-Z0-9_-]+$`)
+
+// code dinormalkan ke uppercase, startsAt kosong diisi now
+func ValidateCouponPayload(c *entities.Coupon, now time.Time) error {
+
+	var invalidFields []string
+	c.Code = NormalizeCouponCode(c.Code)
+
+	if len(c.Code) < MINCOUPONCODE || len(c.Code) > MAXCOUPONCODE || !couponCodeRegex.MatchString(c.Code) {
+		invalidFields = append(invalidFields, "coupon code")
+	}
+
+	switch c.Type {
+	case "percentage":
+		if c.Value <= 0 || c.Value > MAXCOUPONPERCENTAGE {
+			invalidFields = append(invalidFields, "coupon value")
+		}
+	case "fixed":
+		if c.Value <= 0 || c.Value > MAXPRICE {
+			invalidFields = append(invalidFields, "coupon value")
+		}
+	default:
+		invalidFields = append(invalidFields, "coupon type")
+	}
+
+	if c.MaxDiscount < 0 || c.MaxDiscount > MAXPRICE {
+		invalidFields = append(invalidFields, "coupon maxDiscount")
+	}
+
+	if c.MinSpend < 0 || c.MinSpend > MAXPRICE {
+		invalidFields = append(invalidFields, "coupon minSpend")
+	}
+
+	if !validateUUIDs(c.ProductIds) {
+		invalidFields = append(invalidFields, "coupon productIds")
+	}
+
+	if !validateUUIDs(c.CategoryIds) {
+		invalidFields = append(invalidFields, "coupon categoryIds")
+	}
+
+	if c.UsageLimit < 0 {
+		invalidFields = append(invalidFields, "coupon usageLimit")
+	}
+
+	if c.PerUserLimit < 0 || (c.UsageLimit > 0 && c.PerUserLimit > c.UsageLimit) {
+		invalidFields = append(invalidFields, "coupon perUserLimit")
+	}
+
+	if c.StartsAt.IsZero() {
+		c.StartsAt = now
+	}
+
+	if !c.ExpiresAt.After(c.StartsAt) || !c.ExpiresAt.After(now) {
+		invalidFields = append(invalidFields, "coupon expiresAt")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+func NormalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateUUIDs(ids []string) bool {
+
+	if len(ids) > MAXCOUPONELIGIBLEITEM {
+		return false
+	}
+
+	for _, id := range ids {
+		if !helper.ValidateUUID(id) {
+			return false
+		}
+	}
+
+	return true
+}
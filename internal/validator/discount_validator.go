@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+const (
+	MAXDISCOUNTNAME       = 100
+	MINDISCOUNTPERCENTAGE = 1
+	MAXDISCOUNTPERCENTAGE = 99
+
+	// flash sale harus singkat dan kuotanya terbatas
+	MAXFLASHSALEDURATION = 24 * time.Hour
+)
+
+// startsAt kosong diisi now, harga fixed dibandingkan dengan harga product
+func ValidateDiscountPayload(d *entities.Discount, productPrice float64, now time.Time) error {
+
+	var invalidFields []string
+	nameLength := len(strings.TrimSpace(d.Name))
+
+	if nameLength == 0 || nameLength > MAXDISCOUNTNAME {
+		invalidFields = append(invalidFields, "discount name")
+	}
+
+	switch d.Type {
+	case "percentage":
+		if d.Value < MINDISCOUNTPERCENTAGE || d.Value > MAXDISCOUNTPERCENTAGE {
+			invalidFields = append(invalidFields, "discount value")
+		}
+	case "fixed":
+		if d.Value <= 0 || d.Value >= productPrice {
+			invalidFields = append(invalidFields, "discount value")
+		}
+	default:
+		invalidFields = append(invalidFields, "discount type")
+	}
+
+	if d.StartsAt.IsZero() {
+		d.StartsAt = now
+	}
+
+	if !d.EndsAt.After(d.StartsAt) || !d.EndsAt.After(now) {
+		invalidFields = append(invalidFields, "discount endsAt")
+	}
+
+	if d.QuantityLimit < 0 || d.QuantityLimit > MAXSTOCK {
+		invalidFields = append(invalidFields, "discount quantityLimit")
+	}
+
+	if d.PerBuyerLimit < 0 || d.PerBuyerLimit > MAXSTOCK {
+		invalidFields = append(invalidFields, "discount perBuyerLimit")
+	}
+
+	if d.IsFlashSale {
+		if d.QuantityLimit == 0 {
+			invalidFields = append(invalidFields, "discount quantityLimit")
+		}
+
+		if d.PerBuyerLimit == 0 {
+			invalidFields = append(invalidFields, "discount perBuyerLimit")
+		}
+
+		if d.EndsAt.Sub(d.StartsAt) > MAXFLASHSALEDURATION {
+			invalidFields = append(invalidFields, "discount endsAt")
+		}
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
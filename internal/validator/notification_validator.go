@@ -0,0 +1,37 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	MAXLOWSTOCKTHRESHOLD = 32000
+	MAXNOTIFICATIONLIMIT = 100
+)
+
+func ValidateLowStockThreshold(threshold int) error {
+
+	if threshold < 0 || threshold > MAXLOWSTOCKTHRESHOLD {
+		return fmt.Errorf("Invalid low stock threshold")
+	}
+
+	return nil
+}
+
+// return unread, limit dan offset, default limit 20
+func ValidateListNotificationQuery(unread, limit, offset string) (bool, int, int) {
+
+	parsedLimit, err := strconv.Atoi(limit)
+	if err != nil || parsedLimit < 1 || parsedLimit > MAXNOTIFICATIONLIMIT {
+		parsedLimit = 20
+	}
+
+	parsedOffset, err := strconv.Atoi(offset)
+	if err != nil || parsedOffset < 0 {
+		parsedOffset = 0
+	}
+
+	return strings.ToLower(unread) == "true", parsedLimit, parsedOffset
+}
@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+)
+
+const MAXPRODUCTBATCHITEMS = 500
+
+// mode batch "atomic" (semua atau tidak sama sekali) atau "best_effort", default atomic
+func ValidateProductBatchMode(mode string) (string, error) {
+
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	switch mode {
+	case "":
+		return "atomic", nil
+	case "atomic", "best_effort":
+		return mode, nil
+	default:
+		return "", fmt.Errorf("Invalid batch mode")
+	}
+}
+
+func ValidateProductBatchItem(item *entities.ProductBatchItem) error {
+
+	var invalidFields []string
+
+	if !helper.ValidateUUID(item.ProductId) {
+		invalidFields = append(invalidFields, "item productId")
+	}
+
+	if item.VariantId != "" && !helper.ValidateUUID(item.VariantId) {
+		invalidFields = append(invalidFields, "item variantId")
+	}
+
+	if item.Stock == nil && item.Delta == nil && item.Price == nil {
+		invalidFields = append(invalidFields, "item change (stock, delta or price required)")
+	}
+
+	if item.Stock != nil && item.Delta != nil {
+		invalidFields = append(invalidFields, "item stock (use either stock or delta)")
+	}
+
+	if item.Stock != nil && (*item.Stock < 0 || *item.Stock > MAXSTOCK) {
+		invalidFields = append(invalidFields, "item stock")
+	}
+
+	if item.Delta != nil && (*item.Delta == 0 || *item.Delta > MAXSTOCK || *item.Delta < -MAXSTOCK) {
+		invalidFields = append(invalidFields, "item delta")
+	}
+
+	if item.Price != nil && (*item.Price < MINPRICE || *item.Price > MAXPRICE) {
+		invalidFields = append(invalidFields, "item price")
+	}
+
+	if len(item.Reason) > MAXSTOCKREASON {
+		invalidFields = append(invalidFields, "item reason")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
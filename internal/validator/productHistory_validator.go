@@ -0,0 +1,21 @@
+package validator
+
+import "strconv"
+
+const MAXHISTORYLIMIT = 50
+
+// return limit dan offset, default limit 20
+func ValidateListProductHistoryQuery(limit, offset string) (int, int) {
+
+	parsedLimit, err := strconv.Atoi(limit)
+	if err != nil || parsedLimit < 1 || parsedLimit > MAXHISTORYLIMIT {
+		parsedLimit = 20
+	}
+
+	parsedOffset, err := strconv.Atoi(offset)
+	if err != nil || parsedOffset < 0 {
+		parsedOffset = 0
+	}
+
+	return parsedLimit, parsedOffset
+}
@@ -0,0 +1,35 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+)
+
+const (
+	MAXPRODUCTIMAGES = 10
+)
+
+// urutan baru harus berisi semua gambar milik product, masing-masing tepat satu kali
+func ValidateReorderProductImagesPayload(imageIds []string, images []entities.ProductImage) error {
+
+	if len(imageIds) != len(images) {
+		return fmt.Errorf("Invalid imageIds, must contain every image of the product")
+	}
+
+	existing := map[string]bool{}
+	for _, img := range images {
+		existing[img.ID] = true
+	}
+
+	seen := map[string]bool{}
+	for _, id := range imageIds {
+		if !helper.ValidateUUID(id) || !existing[id] || seen[id] {
+			return fmt.Errorf("Invalid imageIds, must contain every image of the product")
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	MAXPRODUCTIMPORTSIZE = 5 << 20 // byte
+	MAXPRODUCTIMPORTROWS = 5000
+)
+
+// format dari query ?format=, kalau kosong dari content type. return "" kalau tidak dikenali
+func ValidateProductFileFormat(format, contentType string) string {
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		contentType = strings.ToLower(contentType)
+
+		switch {
+		case strings.HasPrefix(contentType, "text/csv"):
+			format = "csv"
+		case strings.HasPrefix(contentType, "application/x-ndjson"), strings.HasPrefix(contentType, "application/jsonl"):
+			format = "jsonl"
+		}
+	}
+
+	if format != "csv" && format != "jsonl" {
+		return ""
+	}
+
+	return format
+}
+
+func ValidateProductImportPayload(payload []byte) error {
+
+	if len(strings.TrimSpace(string(payload))) == 0 {
+		return fmt.Errorf("Import file is empty")
+	}
+
+	if len(payload) > MAXPRODUCTIMPORTSIZE {
+		return fmt.Errorf("Import file is too large, maximum size is %d MB", MAXPRODUCTIMPORTSIZE>>20)
+	}
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	MINQUESTIONLENGTH = 5
+	MAXQUESTIONLENGTH = 1000
+	MAXANSWERLENGTH   = 2000
+	MAXQUESTIONLIMIT  = 50
+
+	// jumlah pertanyaan yang ikut di detail product
+	PRODUCTDETAILQUESTIONS = 5
+)
+
+func ValidateProductQuestionPayload(question string) error {
+
+	length := len(strings.TrimSpace(question))
+
+	if length < MINQUESTIONLENGTH || length > MAXQUESTIONLENGTH {
+		return fmt.Errorf("Invalid question")
+	}
+
+	return nil
+}
+
+func ValidateProductAnswerPayload(answer string) error {
+
+	length := len(strings.TrimSpace(answer))
+
+	if length == 0 || length > MAXANSWERLENGTH {
+		return fmt.Errorf("Invalid answer")
+	}
+
+	return nil
+}
+
+// return limit dan offset, default limit 10
+func ValidateListProductQuestionQuery(limit, offset string) (int, int) {
+
+	parsedLimit, err := strconv.Atoi(limit)
+	if err != nil || parsedLimit < 1 || parsedLimit > MAXQUESTIONLIMIT {
+		parsedLimit = 10
+	}
+
+	parsedOffset, err := strconv.Atoi(offset)
+	if err != nil || parsedOffset < 0 {
+		parsedOffset = 0
+	}
+
+	return parsedLimit, parsedOffset
+}
@@ -0,0 +1,55 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+const (
+	MAXSKU               = 64
+	MAXVARIANTNAME       = 100
+	MAXVARIANTATTRIBUTES = 10
+	MAXVARIANTATTRLENGTH = 50
+)
+
+func ValidateProductVariantPayload(v *entities.ProductVariant) error {
+
+	var invalidFields []string
+	skuLength := len(v.Sku)
+	nameLength := len(v.Name)
+
+	if v.Sku == "" || skuLength > MAXSKU || strings.ContainsAny(v.Sku, " \t\n") {
+		invalidFields = append(invalidFields, "variant sku")
+	}
+
+	if v.Name == "" || nameLength > MAXVARIANTNAME {
+		invalidFields = append(invalidFields, "variant name")
+	}
+
+	if v.Price != nil && (*v.Price < MINPRICE || *v.Price > MAXPRICE) {
+		invalidFields = append(invalidFields, "variant price")
+	}
+
+	if v.Stock < 0 || v.Stock > MAXSTOCK {
+		invalidFields = append(invalidFields, "variant stock")
+	}
+
+	if len(v.Attributes) > MAXVARIANTATTRIBUTES {
+		invalidFields = append(invalidFields, "variant attributes")
+	} else {
+		for key, value := range v.Attributes {
+			if key == "" || value == "" || len(key) > MAXVARIANTATTRLENGTH || len(value) > MAXVARIANTATTRLENGTH {
+				invalidFields = append(invalidFields, "variant attributes")
+				break
+			}
+		}
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
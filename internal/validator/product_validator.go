@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
 	"github.com/GetterSethya/golangApiMarketplace/internal/types"
 )
 
@@ -16,8 +18,40 @@ const (
 	MINPRICE       = 0
 	MAXIMAGEURL    = 255
 	MAXSTOCK       = 32000
+	MAXSUGGEST     = 20
+	MAXSEARCH      = 100
+	MAXCURSORVALUE = 255
+	MAXTAGLENGTH   = 50
+	MAXFILTERTAGS  = 10
+	MAXSORTFIELDS  = 3
 )
 
+// field sort yang diizinkan (lowercase dari query param -> nama field)
+var productSortFields = map[string]string{
+	"date":      "createdAt",
+	"createdat": "createdAt",
+	"name":      "name",
+	"price":     "price",
+	"stock":     "stock",
+	"rating":    "rating",
+	"relevance": "relevance",
+}
+
+func ValidateSuggestProductQuery(search, limit string) (string, int) {
+
+	parsedLimit, err := strconv.Atoi(limit)
+	if err != nil || parsedLimit < 1 || parsedLimit > MAXSUGGEST {
+		parsedLimit = 10
+	}
+
+	search = strings.TrimSpace(search)
+	if len(search) > MAXSEARCH {
+		search = search[:MAXSEARCH]
+	}
+
+	return search, parsedLimit
+}
+
 func ValidateListProductQuery(q types.ListQuery) types.ListQueryValid {
 	userOnly := strings.ToLower(q.UserOnly)
 	limit, err := strconv.Atoi(q.Limit)
@@ -31,9 +65,9 @@ func ValidateListProductQuery(q types.ListQuery) types.ListQueryValid {
 	}
 
 	condition := strings.ToLower(q.Condition)
-	showEmptyStock := strings.ToLower(q.ShowEmptyStock)
+	tagMatch := strings.ToLower(q.TagMatch)
+	stock := strings.ToLower(q.Stock)
 	sort := strings.ToLower(q.Sort)
-	order := strings.ToLower(q.Order)
 
 	minPrice, err := strconv.Atoi(q.MinPrice)
 	if err != nil {
@@ -45,43 +79,147 @@ func ValidateListProductQuery(q types.ListQuery) types.ListQueryValid {
 		maxPrice = 0
 	}
 
+	minRating, err := strconv.ParseFloat(q.MinRating, 64)
+	if err != nil || minRating < 0 || minRating > MAXRATING {
+		minRating = 0
+	}
+
 	if !(userOnly == "false" || userOnly == "true") {
 		userOnly = "false"
 	}
 
 	if !(condition == "new" || condition == "second") {
-		condition = "new"
+		condition = "any"
+	}
+
+	if tagMatch != "all" {
+		tagMatch = "any"
 	}
 
-	if !(showEmptyStock == "false" || showEmptyStock == "true") {
-		showEmptyStock = "false"
+	// showemptystock lama tetap didukung, true = ikutkan product stock kosong
+	if !(stock == "include" || stock == "exclude" || stock == "only") {
+		stock = "exclude"
+		if strings.ToLower(q.ShowEmptyStock) == "true" {
+			stock = "include"
+		}
 	}
 
 	if !(sort == "asc" || sort == "desc") {
 		sort = "desc"
 	}
 
-	if order == "date" {
-		order = "createdAt"
+	status := strings.ToLower(q.Status)
+	if !validateProductStatus(status) {
+		status = "any"
+	}
+
+	seller := ""
+	if helper.ValidateUUID(q.Seller) {
+		seller = q.Seller
+	}
+
+	storefront := strings.ToLower(q.Storefront)
+	if !ValidateSlug(storefront) {
+		storefront = ""
+	}
+
+	var isPurchaseable *bool
+	if purchaseable, err := strconv.ParseBool(q.Purchaseable); err == nil {
+		isPurchaseable = &purchaseable
 	}
 
-	if !(order == "createdAt" || order == "name" || sort == "price") {
-		order = "createdAt"
+	var tags []string
+	for _, tag := range q.Tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || len(tag) > MAXTAGLENGTH {
+			continue
+		}
+
+		tags = append(tags, tag)
+		if len(tags) == MAXFILTERTAGS {
+			break
+		}
 	}
 
+	// format attr "key:value", contoh ?attr=size:XL&attr=color:merah
+	attributes := map[string]string{}
+	for _, attr := range q.Attributes {
+		key, value, ok := strings.Cut(attr, ":")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if !ok || key == "" || value == "" || len(key) > MAXVARIANTATTRLENGTH || len(value) > MAXVARIANTATTRLENGTH {
+			continue
+		}
+
+		attributes[key] = value
+	}
+
+	sorts := validateProductSorts(q.Order, sort == "desc", strings.TrimSpace(q.Search) != "")
+
 	return types.ListQueryValid{
 		UserOnly:       userOnly,
 		Limit:          limit,
 		Offset:         offset,
-		Tags:           q.Tags,
+		Tags:           tags,
+		TagMatch:       tagMatch,
 		Condition:      condition,
-		ShowEmptyStock: showEmptyStock,
+		Stock:          stock,
+		SellerId:       seller,
+		Storefront:     storefront,
+		IsPurchaseable: isPurchaseable,
 		MaxPrice:       float64(maxPrice),
 		MinPrice:       float64(minPrice),
-		Sort:           sort,
-		Order:          order,
+		MinRating:      minRating,
+		Sorts:          sorts,
 		Search:         q.Search,
+		Attributes:     attributes,
+		Category:       strings.ToLower(q.Category),
+		Facets:         strings.ToLower(q.Facets) == "true",
+		Cursor:         validateCursor(q.Cursor, helper.SortSignature(sorts)),
+		WithTotal:      strings.ToLower(q.WithTotal) == "true",
+		Status:         status,
+	}
+}
+
+// order "field[:asc|desc],..." contoh order=price:asc,date:desc.
+// field tanpa arah pakai arah dari param sort, field diluar whitelist dan duplikat dibuang
+func validateProductSorts(order string, defaultDesc bool, hasSearch bool) []types.SortField {
+
+	var sorts []types.SortField
+	used := map[string]bool{}
+
+	for _, part := range strings.Split(order, ",") {
+		field, direction, _ := strings.Cut(strings.TrimSpace(part), ":")
+		field = productSortFields[strings.ToLower(field)]
+		direction = strings.ToLower(direction)
+
+		// relevance hanya berlaku kalau ada search query
+		if field == "" || used[field] || (field == "relevance" && !hasSearch) {
+			continue
+		}
+
+		if !(direction == "" || direction == "asc" || direction == "desc") {
+			continue
+		}
+
+		desc := defaultDesc
+		if direction != "" {
+			desc = direction == "desc"
+		}
+
+		used[field] = true
+		sorts = append(sorts, types.SortField{Field: field, Desc: desc})
+
+		if len(sorts) == MAXSORTFIELDS {
+			break
+		}
 	}
+
+	if len(sorts) == 0 {
+		sorts = append(sorts, types.SortField{Field: "createdAt", Desc: defaultDesc})
+	}
+
+	return sorts
 }
 
 func ValidateUpdateProductPayload(p *entities.Product) error {
@@ -97,16 +235,18 @@ func ValidateUpdateProductPayload(p *entities.Product) error {
 		invalidFields = append(invalidFields, "product price")
 	}
 
-	if p.ImageUrl == "" || len(p.ImageUrl) > MAXIMAGEURL {
-		invalidFields = append(invalidFields, "product imageUrl")
+	// imageUrl dan stock tidak divalidasi, update product tidak mengubah keduanya
+
+	if !validateCondition(p.Condition) {
+		invalidFields = append(invalidFields, "product condition")
 	}
 
-	if p.Stock < 0 || p.Stock > MAXSTOCK {
-		invalidFields = append(invalidFields, "product stock")
+	if p.CategoryId != "" && !helper.ValidateUUID(p.CategoryId) {
+		invalidFields = append(invalidFields, "product categoryId")
 	}
 
-	if !validateCondition(p.Condition) {
-		invalidFields = append(invalidFields, "product condition")
+	if len(p.Sku) > MAXSKU || strings.ContainsAny(p.Sku, " \t\n") {
+		invalidFields = append(invalidFields, "product sku")
 	}
 
 	if len(invalidFields) > 0 {
@@ -129,7 +269,8 @@ func ValidateCreateProductPayload(p *entities.Product) error {
 		invalidFields = append(invalidFields, "product price")
 	}
 
-	if p.ImageUrl == "" || len(p.ImageUrl) > MAXIMAGEURL {
+	// imageUrl boleh kosong, nanti diisi otomatis dari upload gambar
+	if len(p.ImageUrl) > MAXIMAGEURL {
 		invalidFields = append(invalidFields, "product imageUrl")
 	}
 
@@ -141,6 +282,79 @@ func ValidateCreateProductPayload(p *entities.Product) error {
 		invalidFields = append(invalidFields, "product condition")
 	}
 
+	if p.CategoryId != "" && !helper.ValidateUUID(p.CategoryId) {
+		invalidFields = append(invalidFields, "product categoryId")
+	}
+
+	if len(p.Sku) > MAXSKU || strings.ContainsAny(p.Sku, " \t\n") {
+		invalidFields = append(invalidFields, "product sku")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+// cursor dari sort yang berbeda tidak bisa dipakai, anggap halaman pertama
+func validateCursor(token, order string) *types.Cursor {
+
+	cursor := helper.DecodeCursor(token)
+	if cursor == nil || cursor.Order != order {
+		return nil
+	}
+
+	for _, value := range cursor.Values {
+		if len(value) > MAXCURSORVALUE {
+			return nil
+		}
+	}
+
+	return cursor
+}
+
+func validateProductStatus(status string) bool {
+	return status == "draft" || status == "scheduled" || status == "published" || status == "archived"
+}
+
+// tentukan status akhir dari payload. published dengan publishAt di masa depan jadi scheduled,
+// status kosong = published (atau scheduled kalau ada publishAt)
+func ValidateProductStatusPayload(p *entities.Product, now time.Time) error {
+
+	var invalidFields []string
+
+	if p.Status == "" {
+		p.Status = "published"
+	}
+
+	if p.Status == "published" && p.PublishAt != nil && p.PublishAt.After(now) {
+		p.Status = "scheduled"
+	}
+
+	switch p.Status {
+	case "draft":
+		p.PublishAt = nil
+	case "scheduled":
+		if p.PublishAt == nil || !p.PublishAt.After(now) {
+			invalidFields = append(invalidFields, "publishAt")
+		}
+	case "published":
+		if p.PublishAt == nil {
+			p.PublishAt = &now
+		}
+	case "archived":
+		p.ArchiveAt = nil
+	default:
+		invalidFields = append(invalidFields, "status")
+	}
+
+	if p.ArchiveAt != nil {
+		if !p.ArchiveAt.After(now) || (p.PublishAt != nil && !p.ArchiveAt.After(*p.PublishAt)) {
+			invalidFields = append(invalidFields, "archiveAt")
+		}
+	}
+
 	if len(invalidFields) > 0 {
 		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
 	}
@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/types"
+)
+
+const (
+	MINRATING      = 1
+	MAXRATING      = 5
+	MAXREVIEWBODY  = 2000
+	MAXREVIEWREPLY = 1000
+	MAXREVIEWLIMIT = 50
+)
+
+func ValidateReviewPayload(r *entities.Review) error {
+
+	var invalidFields []string
+
+	if r.Rating < MINRATING || r.Rating > MAXRATING {
+		invalidFields = append(invalidFields, "review rating")
+	}
+
+	if len(r.Body) > MAXREVIEWBODY {
+		invalidFields = append(invalidFields, "review body")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+func ValidateReviewReplyPayload(reply string) error {
+
+	if strings.TrimSpace(reply) == "" || len(reply) > MAXREVIEWREPLY {
+		return fmt.Errorf("Invalid review reply")
+	}
+
+	return nil
+}
+
+func ValidateListReviewQuery(q types.ListQueryReview) types.ListQueryReviewValid {
+
+	order := strings.ToLower(q.Order)
+
+	limit, err := strconv.Atoi(q.Limit)
+	if err != nil || limit < 1 || limit > MAXREVIEWLIMIT {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(q.Offset)
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	rating, err := strconv.Atoi(q.Rating)
+	if err != nil || rating < MINRATING || rating > MAXRATING {
+		rating = 0
+	}
+
+	if !(order == "recent" || order == "helpful" || order == "rating") {
+		order = "recent"
+	}
+
+	return types.ListQueryReviewValid{
+		Limit:  limit,
+		Offset: offset,
+		Order:  order,
+		Rating: rating,
+	}
+}
@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+)
+
+const (
+	MAXSTOCKREASON        = 255
+	MAXSTOCKMOVEMENTLIMIT = 100
+)
+
+// type yang boleh dicatat manual oleh seller, sisanya dari checkout/transaksi
+func ValidateStockMovementPayload(m *entities.StockMovement) error {
+
+	var invalidFields []string
+
+	switch m.Type {
+	case "restock", "return":
+		if m.Delta <= 0 || m.Delta > MAXSTOCK {
+			invalidFields = append(invalidFields, "stock delta")
+		}
+	case "adjustment":
+		if m.Delta == 0 || m.Delta > MAXSTOCK || m.Delta < -MAXSTOCK {
+			invalidFields = append(invalidFields, "stock delta")
+		}
+	default:
+		invalidFields = append(invalidFields, "stock type")
+	}
+
+	if len(m.Reason) > MAXSTOCKREASON {
+		invalidFields = append(invalidFields, "stock reason")
+	}
+
+	if m.VariantId != "" && !helper.ValidateUUID(m.VariantId) {
+		invalidFields = append(invalidFields, "stock variantId")
+	}
+
+	if m.WarehouseId != "" && !helper.ValidateUUID(m.WarehouseId) {
+		invalidFields = append(invalidFields, "stock warehouseId")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+// set stock ke angka absolut (format lama endpoint stock)
+func ValidateSetStockPayload(stock int, m *entities.StockMovement) error {
+
+	var invalidFields []string
+
+	if stock < 0 || stock > MAXSTOCK {
+		invalidFields = append(invalidFields, "stock")
+	}
+
+	if len(m.Reason) > MAXSTOCKREASON {
+		invalidFields = append(invalidFields, "stock reason")
+	}
+
+	if m.VariantId != "" && !helper.ValidateUUID(m.VariantId) {
+		invalidFields = append(invalidFields, "stock variantId")
+	}
+
+	if m.WarehouseId != "" && !helper.ValidateUUID(m.WarehouseId) {
+		invalidFields = append(invalidFields, "stock warehouseId")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+// return type (kosong = semua), limit dan offset, default limit 20
+func ValidateListStockMovementQuery(movementType, limit, offset string) (string, int, int) {
+
+	movementType = strings.ToLower(movementType)
+	switch movementType {
+	case "restock", "sale", "reservation", "release", "adjustment", "return", "transfer":
+	default:
+		movementType = ""
+	}
+
+	parsedLimit, err := strconv.Atoi(limit)
+	if err != nil || parsedLimit < 1 || parsedLimit > MAXSTOCKMOVEMENTLIMIT {
+		parsedLimit = 20
+	}
+
+	parsedOffset, err := strconv.Atoi(offset)
+	if err != nil || parsedOffset < 0 {
+		parsedOffset = 0
+	}
+
+	return movementType, parsedLimit, parsedOffset
+}
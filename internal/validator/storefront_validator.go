@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+)
+
+const (
+	MAXSTOREFRONTNAME            = 100
+	MINSTOREFRONTNAME            = 2
+	MAXSTOREFRONTDESCRIPTION     = 2000
+	MAXSTOREFRONTLOCATION        = 100
+	MAXSTOREFRONTVACATIONMESSAGE = 255
+)
+
+// format jam buka "HH:MM-HH:MM"
+var openingHoursRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]-([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+var storefrontDays = map[string]bool{
+	"mon": true,
+	"tue": true,
+	"wed": true,
+	"thu": true,
+	"fri": true,
+	"sat": true,
+	"sun": true,
+}
+
+func ValidateStorefrontPayload(f *entities.Storefront) error {
+
+	var invalidFields []string
+	nameLength := len(f.Name)
+
+	if nameLength < MINSTOREFRONTNAME || nameLength > MAXSTOREFRONTNAME {
+		invalidFields = append(invalidFields, "storefront name")
+	}
+
+	if !ValidateSlug(f.Slug) {
+		invalidFields = append(invalidFields, "storefront slug")
+	}
+
+	if len(f.Description) > MAXSTOREFRONTDESCRIPTION {
+		invalidFields = append(invalidFields, "storefront description")
+	}
+
+	if len(f.LogoUrl) > MAXIMAGEURL {
+		invalidFields = append(invalidFields, "storefront logoUrl")
+	}
+
+	if len(f.BannerUrl) > MAXIMAGEURL {
+		invalidFields = append(invalidFields, "storefront bannerUrl")
+	}
+
+	if len(f.Location) > MAXSTOREFRONTLOCATION {
+		invalidFields = append(invalidFields, "storefront location")
+	}
+
+	if !validOpeningHours(f.OpeningHours) {
+		invalidFields = append(invalidFields, "storefront openingHours")
+	}
+
+	if len(f.VacationMessage) > MAXSTOREFRONTVACATIONMESSAGE {
+		invalidFields = append(invalidFields, "storefront vacationMessage")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+// hari yang tidak diisi dianggap tutup
+func validOpeningHours(openingHours map[string]string) bool {
+
+	for day, hours := range openingHours {
+		if !storefrontDays[day] {
+			return false
+		}
+
+		if hours != "closed" && !openingHoursRegex.MatchString(hours) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -50,17 +50,19 @@ func ValidateListTransactionQuery(q types.ListQueryTransaction) types.ListQueryT
 		order = "createdAt"
 	}
 
-	if !(order == "createdAt" || order == "name" || sort == "price") {
+	if !(order == "createdAt" || order == "total") {
 		order = "createdAt"
 	}
 
 	return types.ListQueryTransactionValid{
-		Seller: parsedSeller,
-		Limit:  limit,
-		Offset: offset,
-		Sort:   sort,
-		Order:  order,
-		Search: q.Search,
+		Seller:    parsedSeller,
+		Limit:     limit,
+		Offset:    offset,
+		Sort:      sort,
+		Order:     order,
+		Search:    q.Search,
+		Cursor:    validateCursor(q.Cursor, order+":"+sort),
+		WithTotal: strings.ToLower(q.WithTotal) == "true",
 	}
 }
 
@@ -82,6 +84,10 @@ func ValidateCreateTransactionPayload(p *entities.Transaction) error {
 		invalidFields = append(invalidFields, "transaction productId")
 	}
 
+	if p.VariantId != "" && !helper.ValidateUUID(p.VariantId) {
+		invalidFields = append(invalidFields, "transaction variantId")
+	}
+
 	if notesLength > MAXNOTESLENGTH {
 		invalidFields = append(invalidFields, "transaction notes")
 	}
@@ -90,6 +96,14 @@ func ValidateCreateTransactionPayload(p *entities.Transaction) error {
 		invalidFields = append(invalidFields, "transaction quantity")
 	}
 
+	if len(p.CouponCode) > MAXCOUPONCODE {
+		invalidFields = append(invalidFields, "transaction couponCode")
+	}
+
+	if (p.Latitude == nil) != (p.Longitude == nil) || !validCoordinate(p.Latitude, p.Longitude) {
+		invalidFields = append(invalidFields, "transaction coordinate")
+	}
+
 	if len(invalidFields) > 0 {
 		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
 	}
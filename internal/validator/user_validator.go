@@ -2,7 +2,9 @@ package validator
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
 )
@@ -14,15 +16,27 @@ const (
 	MINUSERNAME = 5
 	MINPASSWORD = 5
 	MAXPASSWORD = 15
+	MAXEMAIL    = 255
+
+	CONTACTCODELENGTH = 6
+	CONTACTCODETTL    = 15 * time.Minute
+	CONTACTCODERESEND = time.Minute // jeda minimal kirim ulang kode
+
+	RECOVERYCODECOUNT = 10 // recovery code 2fa per user, masing-masing sekali pakai
 )
 
+var emailRegex = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+// format E.164, contoh "+6281234567890"
+var phoneRegex = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+
 func ValidateRegisterPayload(user *entities.User) error {
 
 	var invalidFields []string
 
 	nameLength := len(user.Name)
 	usernameLength := len(user.Username)
-	passwordLength := len(user.HashPassword)
+	passwordLength := len(user.Password)
 
 	if user.Name == "" || nameLength < MINNAME || nameLength > MAXNAME {
 		invalidFields = append(invalidFields, "name")
@@ -32,7 +46,7 @@ func ValidateRegisterPayload(user *entities.User) error {
 		invalidFields = append(invalidFields, "username")
 	}
 
-	if user.HashPassword == "" || passwordLength < MINPASSWORD || passwordLength > MAXPASSWORD {
+	if user.Password == "" || passwordLength < MINPASSWORD || passwordLength > MAXPASSWORD {
 		invalidFields = append(invalidFields, "password")
 	}
 
@@ -48,13 +62,13 @@ func ValidateLoginPayload(user *entities.User) error {
 	var invalidFields []string
 
 	usernameLength := len(user.Username)
-	passwordLength := len(user.HashPassword)
+	passwordLength := len(user.Password)
 
 	if user.Username == "" || usernameLength < MINUSERNAME || usernameLength > MAXUSERNAME || len(strings.Split(user.Username, " ")) > 1 {
 		invalidFields = append(invalidFields, "username")
 	}
 
-	if user.HashPassword == "" || passwordLength < MINPASSWORD || passwordLength > MAXPASSWORD {
+	if user.Password == "" || passwordLength < MINPASSWORD || passwordLength > MAXPASSWORD {
 		invalidFields = append(invalidFields, "password")
 	}
 
@@ -86,3 +100,36 @@ func ValidateUpdatePayload(user *entities.User) error {
 
 	return nil
 }
+
+func ValidatePassword(password string) bool {
+
+	passwordLength := len(password)
+
+	return passwordLength >= MINPASSWORD && passwordLength <= MAXPASSWORD
+}
+
+// email dan phone opsional, kosong berarti dihapus
+func ValidateContactPayload(email, phone string) error {
+
+	var invalidFields []string
+
+	if email != "" && (len(email) > MAXEMAIL || !emailRegex.MatchString(email)) {
+		invalidFields = append(invalidFields, "email")
+	}
+
+	if phone != "" && !phoneRegex.MatchString(phone) {
+		invalidFields = append(invalidFields, "phone")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+// channel verifikasi "email"|"phone"
+func ValidateContactChannel(channel string) bool {
+
+	return channel == "email" || channel == "phone"
+}
@@ -0,0 +1,98 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+)
+
+const (
+	MAXWAREHOUSENAME     = 100
+	MAXWAREHOUSEADDRESS  = 255
+	MAXWAREHOUSECITY     = 100
+	MAXWAREHOUSEPRIORITY = 1000
+)
+
+func ValidateWarehousePayload(w *entities.Warehouse) error {
+
+	var invalidFields []string
+	nameLength := len(w.Name)
+
+	if nameLength < 1 || nameLength > MAXWAREHOUSENAME {
+		invalidFields = append(invalidFields, "warehouse name")
+	}
+
+	if len(w.Address) > MAXWAREHOUSEADDRESS {
+		invalidFields = append(invalidFields, "warehouse address")
+	}
+
+	if len(w.City) > MAXWAREHOUSECITY {
+		invalidFields = append(invalidFields, "warehouse city")
+	}
+
+	// koordinat harus diisi berpasangan
+	if (w.Latitude == nil) != (w.Longitude == nil) || !validCoordinate(w.Latitude, w.Longitude) {
+		invalidFields = append(invalidFields, "warehouse coordinate")
+	}
+
+	if w.Priority < 0 || w.Priority > MAXWAREHOUSEPRIORITY {
+		invalidFields = append(invalidFields, "warehouse priority")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+func ValidateStockTransferPayload(t *entities.StockTransfer) error {
+
+	var invalidFields []string
+
+	if t.FromWarehouseId != "" && !helper.ValidateUUID(t.FromWarehouseId) {
+		invalidFields = append(invalidFields, "transfer fromWarehouseId")
+	}
+
+	if t.ToWarehouseId != "" && !helper.ValidateUUID(t.ToWarehouseId) {
+		invalidFields = append(invalidFields, "transfer toWarehouseId")
+	}
+
+	if t.FromWarehouseId == t.ToWarehouseId {
+		invalidFields = append(invalidFields, "transfer warehouse")
+	}
+
+	if t.VariantId != "" && !helper.ValidateUUID(t.VariantId) {
+		invalidFields = append(invalidFields, "transfer variantId")
+	}
+
+	if t.Quantity < 1 || t.Quantity > MAXSTOCK {
+		invalidFields = append(invalidFields, "transfer quantity")
+	}
+
+	if len(t.Reason) > MAXSTOCKREASON {
+		invalidFields = append(invalidFields, "transfer reason")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}
+
+// koordinat kosong dianggap valid
+func validCoordinate(latitude, longitude *float64) bool {
+
+	if latitude != nil && (*latitude < -90 || *latitude > 90) {
+		return false
+	}
+
+	if longitude != nil && (*longitude < -180 || *longitude > 180) {
+		return false
+	}
+
+	return true
+}
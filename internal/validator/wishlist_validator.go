@@ -0,0 +1,49 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GetterSethya/golangApiMarketplace/internal/entities"
+	"github.com/GetterSethya/golangApiMarketplace/internal/helper"
+)
+
+const (
+	MAXWISHLISTNAME  = 100
+	MAXWISHLISTS     = 20
+	MAXWISHLISTITEMS = 100
+)
+
+func ValidateWishlistPayload(w *entities.Wishlist) error {
+
+	name := strings.TrimSpace(w.Name)
+
+	if name == "" || len(name) > MAXWISHLISTNAME {
+		return fmt.Errorf("Invalid wishlist name")
+	}
+
+	return nil
+}
+
+func ValidateWishlistItemPayload(item *entities.WishlistItem) error {
+
+	var invalidFields []string
+
+	if !helper.ValidateUUID(item.ProductId) {
+		invalidFields = append(invalidFields, "wishlist item productId")
+	}
+
+	if item.VariantId != "" && !helper.ValidateUUID(item.VariantId) {
+		invalidFields = append(invalidFields, "wishlist item variantId")
+	}
+
+	if item.Quantity < MINQTT || item.Quantity > MAXQTT {
+		invalidFields = append(invalidFields, "wishlist item quantity")
+	}
+
+	if len(invalidFields) > 0 {
+		return fmt.Errorf("Invalid " + strings.Join(invalidFields, ", "))
+	}
+
+	return nil
+}